@@ -0,0 +1,123 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/user"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/lvrach/slack-social-ai/internal/history"
+)
+
+// AuditCmd shows the audit log of changes to history entries.
+type AuditCmd struct {
+	ID     string   `help:"Show only events for this entry ID." name:"id"`
+	Action []string `help:"Show only these actions, e.g. remove, evict, clear (repeatable)." short:"a"`
+	Actor  string   `help:"Show only events by this actor (agent or user name)."`
+	Since  string   `help:"Show only events newer than this (duration like 2d, 12h, or RFC3339)."`
+	Limit  int      `help:"Show at most N most recent events (0 = all)." short:"n" default:"50"`
+}
+
+func (cmd *AuditCmd) Run(globals *Globals) error {
+	events, err := history.ReadAudit()
+	if err != nil {
+		return newCLIError(ExitRuntimeError, "audit_error",
+			fmt.Sprintf("Failed to read audit log: %s", err))
+	}
+
+	var since time.Time
+	if cmd.Since != "" {
+		if since, err = parseSince(cmd.Since, time.Now().In(scheduleLocation())); err != nil {
+			return err
+		}
+	}
+	events = filterAudit(events, cmd.ID, cmd.Action, cmd.Actor, since)
+	if cmd.Limit > 0 && len(events) > cmd.Limit {
+		events = events[len(events)-cmd.Limit:]
+	}
+
+	if globals.JSON {
+		if events == nil {
+			events = []history.AuditEvent{}
+		}
+		resp := map[string]any{"events": events, "count": len(events)}
+		b, _ := json.Marshal(resp)
+		fmt.Fprintln(os.Stdout, string(b))
+		return nil
+	}
+
+	if len(events) == 0 {
+		fmt.Fprintln(os.Stdout, "No audit events.")
+		return nil
+	}
+	for _, ev := range events {
+		who := ev.Actor
+		if ev.Command != "" {
+			who += " (" + ev.Command + ")"
+		}
+		fmt.Fprintf(os.Stdout, "[%s] %-8s %s  %s → %s  %s\n",
+			formatShortTime(ev.Time), ev.Action, ev.EntryID,
+			orDash(ev.Before), orDash(ev.After), strings.TrimSpace(who))
+		if ev.Message != "" {
+			fmt.Fprintf(os.Stdout, "    %s\n", ev.Message)
+		}
+	}
+	return nil
+}
+
+// filterAudit keeps events matching every non-empty filter.
+func filterAudit(events []history.AuditEvent, id string, actions []string, actor string, since time.Time) []history.AuditEvent {
+	var result []history.AuditEvent
+	for _, ev := range events {
+		if id != "" && ev.EntryID != id {
+			continue
+		}
+		if len(actions) > 0 && !slices.Contains(actions, ev.Action) {
+			continue
+		}
+		if actor != "" && !strings.EqualFold(ev.Actor, actor) {
+			continue
+		}
+		if !since.IsZero() {
+			ts, err := time.Parse(time.RFC3339, ev.Time)
+			if err != nil || ts.Before(since) {
+				continue
+			}
+		}
+		result = append(result, ev)
+	}
+	return result
+}
+
+func orDash(s string) string {
+	if s == "" {
+		return "-"
+	}
+	return s
+}
+
+// auditActorName identifies who is running the CLI: the calling agent if
+// one is detected, otherwise the OS user.
+func auditActorName(getenv func(string) string) string {
+	if agent := getenv("SLACK_SOCIAL_AI_AGENT"); agent != "" {
+		return strings.ToLower(agent)
+	}
+	if agent, _ := detectAgent(getenv); agent != "" {
+		return agent
+	}
+	if u, err := user.Current(); err == nil {
+		return u.Username
+	}
+	return getenv("USER")
+}
+
+// auditCommandName strips argument placeholders from a kong command path,
+// e.g. "queue remove <id>" → "queue remove".
+func auditCommandName(path string) string {
+	fields := strings.Fields(path)
+	fields = slices.DeleteFunc(fields, func(f string) bool { return strings.HasPrefix(f, "<") })
+	return strings.Join(fields, " ")
+}
@@ -0,0 +1,96 @@
+package main
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/lvrach/slack-social-ai/internal/history"
+)
+
+func TestAuditCommandName(t *testing.T) {
+	assert.Equal(t, "queue remove", auditCommandName("queue remove <id>"))
+	assert.Equal(t, "post", auditCommandName("post <message>"))
+	assert.Equal(t, "history", auditCommandName("history"))
+}
+
+func TestAuditActorName(t *testing.T) {
+	env := func(vars map[string]string) func(string) string {
+		return func(k string) string { return vars[k] }
+	}
+	assert.Equal(t, "claude", auditActorName(env(map[string]string{"CLAUDECODE": "1"})))
+	assert.Equal(t, "bot", auditActorName(env(map[string]string{"SLACK_SOCIAL_AI_AGENT": "Bot", "CLAUDECODE": "1"})))
+	assert.NotEmpty(t, auditActorName(env(nil)), "falls back to the OS user")
+}
+
+func TestParseSince(t *testing.T) {
+	now := time.Date(2026, 3, 2, 12, 0, 0, 0, time.UTC)
+
+	got, err := parseSince("2d", now)
+	require.NoError(t, err)
+	assert.Equal(t, now.Add(-48*time.Hour), got)
+
+	got, err = parseSince("2026-03-01T00:00:00Z", now)
+	require.NoError(t, err)
+	assert.Equal(t, time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC), got)
+
+	_, err = parseSince("yesterday", now)
+	var cliErr *CLIError
+	require.True(t, asCLIError(err, &cliErr))
+	assert.Equal(t, "invalid_time", cliErr.Code)
+}
+
+func TestFilterAudit(t *testing.T) {
+	events := []history.AuditEvent{
+		{Time: "2026-03-01T10:00:00Z", Action: "append", EntryID: "a", Actor: "claude"},
+		{Time: "2026-03-02T10:00:00Z", Action: "remove", EntryID: "a", Actor: "alice"},
+		{Time: "2026-03-02T11:00:00Z", Action: "evict", EntryID: "b", Actor: "claude"},
+	}
+	since := time.Date(2026, 3, 2, 0, 0, 0, 0, time.UTC)
+
+	assert.Len(t, filterAudit(events, "a", nil, "", time.Time{}), 2)
+	assert.Len(t, filterAudit(events, "", []string{"remove", "evict"}, "", time.Time{}), 2)
+	assert.Len(t, filterAudit(events, "", nil, "Claude", time.Time{}), 2)
+	assert.Len(t, filterAudit(events, "", nil, "", since), 2)
+	assert.Len(t, filterAudit(events, "a", nil, "claude", since), 0)
+}
+
+func TestAuditCmd_JSON(t *testing.T) {
+	withTempHome(t)
+
+	e, err := history.Append("gone soon", "queued", time.Time{})
+	require.NoError(t, err)
+	_, err = history.Remove(e.ID)
+	require.NoError(t, err)
+
+	output := captureStdout(t, func() {
+		assert.NoError(t, (&AuditCmd{Action: []string{"remove"}}).Run(&Globals{JSON: true}))
+	})
+
+	var resp struct {
+		Events []history.AuditEvent `json:"events"`
+		Count  int                  `json:"count"`
+	}
+	require.NoError(t, json.Unmarshal([]byte(output), &resp))
+	require.Equal(t, 1, resp.Count)
+	assert.Equal(t, e.ID, resp.Events[0].EntryID)
+	assert.Equal(t, "queued", resp.Events[0].Before)
+	assert.Equal(t, "gone soon", resp.Events[0].Message)
+}
+
+func TestAuditCmd_Limit(t *testing.T) {
+	withTempHome(t)
+
+	for range 3 {
+		_, err := history.Append("x", "queued", time.Time{})
+		require.NoError(t, err)
+	}
+	output := captureStdout(t, func() {
+		assert.NoError(t, (&AuditCmd{Limit: 2}).Run(&Globals{}))
+	})
+	assert.Equal(t, 2, strings.Count(output, "append"))
+}
@@ -42,6 +42,7 @@ func runCLI(t *testing.T, args ...string) (stdout, stderr string, exitCode int)
 		"HOME="+home,
 		"XDG_DATA_HOME="+filepath.Join(home, ".local", "share"),
 		"XDG_CONFIG_HOME="+filepath.Join(home, ".config"),
+		"XDG_STATE_HOME="+filepath.Join(home, ".local", "state"),
 	)
 
 	var stdoutBuf, stderrBuf bytes.Buffer
@@ -105,6 +106,15 @@ func TestCLI_HistoryPublishedEmpty(t *testing.T) {
 	assert.Contains(t, stdout, "No history", "empty published history should say 'No history'")
 }
 
+func TestCLI_HistoryListIsDefault(t *testing.T) {
+	flat, _, exitCode := runCLI(t, "history", "--published", "--json")
+	require.Equal(t, 0, exitCode, "history --published --json should exit 0")
+
+	list, _, exitCode := runCLI(t, "history", "list", "--published", "--json")
+	require.Equal(t, 0, exitCode, "history list --published --json should exit 0")
+	assert.Equal(t, flat, list, "history without a subcommand should run history list")
+}
+
 func TestCLI_HistoryClearAllEmpty(t *testing.T) {
 	stdout, _, exitCode := runCLI(t, "history", "--clear-all")
 
@@ -0,0 +1,189 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strconv"
+
+	"github.com/lvrach/slack-social-ai/internal/config"
+	"github.com/lvrach/slack-social-ai/internal/history"
+)
+
+// ConfigCmd shows or changes general settings (the schedule has its own command).
+type ConfigCmd struct {
+	Show ConfigShowCmd `cmd:"" default:"withargs" help:"Show all settings."`
+	Set  ConfigSetCmd  `cmd:"" help:"Change a setting (e.g. config set require_approval true)."`
+}
+
+// configKey describes one user-settable config value.
+type configKey struct {
+	name string
+	help string
+	get  func(cfg config.Config) any
+	set  func(cfg *config.Config, value string) error
+}
+
+// configKeys lists the settings exposed by `config set`, in display order.
+var configKeys = []configKey{
+	{
+		name: "require_approval",
+		help: "Queue posts from non-interactive callers as drafts that need `queue approve`.",
+		get:  func(cfg config.Config) any { return cfg.RequireApproval },
+		set: func(cfg *config.Config, value string) error {
+			v, err := strconv.ParseBool(value)
+			if err != nil {
+				return fmt.Errorf("expected true or false, got %q", value)
+			}
+			cfg.RequireApproval = v
+			return nil
+		},
+	},
+	{
+		name: "default_ttl",
+		help: "Expire posts still queued after this long (e.g. 3d, 1w, 12h; \"off\" to disable).",
+		get: func(cfg config.Config) any {
+			if cfg.DefaultTTL == "" {
+				return "off"
+			}
+			return cfg.DefaultTTL
+		},
+		set: func(cfg *config.Config, value string) error {
+			if value == "off" || value == "" {
+				cfg.DefaultTTL = ""
+				return nil
+			}
+			if _, err := parseTTL(value); err != nil {
+				return fmt.Errorf("expected a duration like 3d, 1w, or 12h, got %q", value)
+			}
+			cfg.DefaultTTL = value
+			return nil
+		},
+	},
+	{
+		name: "duplicate_threshold",
+		help: "Similarity (0-1) at which post flags a near-duplicate of a recent post.",
+		get:  func(cfg config.Config) any { return cfg.DuplicateLimit() },
+		set: func(cfg *config.Config, value string) error {
+			v, err := strconv.ParseFloat(value, 64)
+			if err != nil || v <= 0 || v > 1 {
+				return fmt.Errorf("expected a number between 0 and 1, got %q", value)
+			}
+			cfg.DuplicateThreshold = v
+			return nil
+		},
+	},
+	{
+		name: "duplicate_action",
+		help: "What post does with a near-duplicate: refuse, warn, or off (--force always bypasses).",
+		get:  func(cfg config.Config) any { return cfg.DuplicatePolicy() },
+		set: func(cfg *config.Config, value string) error {
+			switch value {
+			case "refuse", "warn", "off":
+				cfg.DuplicateAction = value
+				return nil
+			}
+			return fmt.Errorf("expected refuse, warn, or off, got %q", value)
+		},
+	},
+	{
+		name: "undo_retention_days",
+		help: "Days to keep snapshots of removed entries for `undo`.",
+		get: func(cfg config.Config) any {
+			return int(cfg.UndoRetention().Hours() / 24)
+		},
+		set: func(cfg *config.Config, value string) error {
+			v, err := strconv.Atoi(value)
+			if err != nil || v <= 0 {
+				return fmt.Errorf("expected a positive number of days, got %q", value)
+			}
+			cfg.UndoRetentionDays = v
+			return nil
+		},
+	},
+	{
+		name: "lock_timeout_seconds",
+		help: "Seconds to wait for another command to release the history lock.",
+		get: func(cfg config.Config) any {
+			if d := cfg.LockTimeout(); d > 0 {
+				return int(d.Seconds())
+			}
+			return int(history.DefaultLockTimeout.Seconds())
+		},
+		set: func(cfg *config.Config, value string) error {
+			v, err := strconv.Atoi(value)
+			if err != nil || v <= 0 {
+				return fmt.Errorf("expected a positive number of seconds, got %q", value)
+			}
+			cfg.LockTimeoutSeconds = v
+			return nil
+		},
+	},
+}
+
+// ConfigShowCmd prints all settings.
+type ConfigShowCmd struct{}
+
+func (cmd *ConfigShowCmd) Run(globals *Globals) error {
+	cfg, err := config.Load()
+	if err != nil {
+		return newCLIError(ExitRuntimeError, "config_error",
+			fmt.Sprintf("Failed to load config: %s", err))
+	}
+
+	if globals.JSON {
+		resp := make(map[string]any, len(configKeys))
+		for _, k := range configKeys {
+			resp[k.name] = k.get(cfg)
+		}
+		return json.NewEncoder(os.Stdout).Encode(resp)
+	}
+
+	for _, k := range configKeys {
+		fmt.Fprintf(os.Stdout, "%-22s %v\n", k.name, k.get(cfg))
+		fmt.Fprintf(os.Stdout, "%-22s %s\n", "", inspectDimStyle.Render(k.help))
+	}
+	fmt.Fprintln(os.Stdout, "\nSchedule settings: run `slack-social-ai schedule status`.")
+	return nil
+}
+
+// ConfigSetCmd changes one setting.
+type ConfigSetCmd struct {
+	Key   string `arg:"" help:"Setting name (see config show)."`
+	Value string `arg:"" help:"New value."`
+}
+
+func (cmd *ConfigSetCmd) Run(globals *Globals) error {
+	var key *configKey
+	for i := range configKeys {
+		if configKeys[i].name == cmd.Key {
+			key = &configKeys[i]
+			break
+		}
+	}
+	if key == nil {
+		return newCLIError(ExitInvalidInput, "unknown_setting",
+			fmt.Sprintf("Unknown setting %q. Run `slack-social-ai config` to list settings.", cmd.Key))
+	}
+
+	cfg, err := config.Load()
+	if err != nil {
+		return newCLIError(ExitRuntimeError, "config_error",
+			fmt.Sprintf("Failed to load config: %s", err))
+	}
+	if err := key.set(&cfg, cmd.Value); err != nil {
+		return newCLIError(ExitInvalidInput, "invalid_setting",
+			fmt.Sprintf("Invalid value for %s: %s", key.name, err))
+	}
+	if err := config.Save(cfg); err != nil {
+		return fmt.Errorf("save config: %w", err)
+	}
+
+	msg := fmt.Sprintf("%s = %v", key.name, key.get(cfg))
+	if globals.JSON {
+		printSuccessJSON(msg)
+	} else {
+		printSuccessHuman(msg)
+	}
+	return nil
+}
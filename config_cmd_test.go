@@ -0,0 +1,91 @@
+package main
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/lvrach/slack-social-ai/internal/config"
+	"github.com/lvrach/slack-social-ai/internal/schedule"
+)
+
+func TestConfigSet_RequireApproval(t *testing.T) {
+	withTempHome(t)
+
+	captureStdout(t, func() {
+		assert.NoError(t, (&ConfigSetCmd{Key: "require_approval", Value: "true"}).Run(&Globals{}))
+	})
+
+	cfg, err := config.Load()
+	require.NoError(t, err)
+	assert.True(t, cfg.RequireApproval)
+
+	output := captureStdout(t, func() {
+		assert.NoError(t, (&ConfigShowCmd{}).Run(&Globals{JSON: true}))
+	})
+	var resp map[string]any
+	require.NoError(t, json.Unmarshal([]byte(output), &resp))
+	assert.Equal(t, true, resp["require_approval"])
+}
+
+func TestConfigSet_PreservesSchedule(t *testing.T) {
+	withTempHome(t)
+
+	cfg := config.Default()
+	cfg.Schedule.Windows = schedule.SameWindows([]string{"sat"}, schedule.Window{Start: schedule.At(7, 0), End: schedule.At(9, 30)})
+	require.NoError(t, config.Save(cfg))
+
+	captureStdout(t, func() {
+		assert.NoError(t, (&ConfigSetCmd{Key: "require_approval", Value: "true"}).Run(&Globals{}))
+	})
+
+	loaded, err := config.Load()
+	require.NoError(t, err)
+	assert.Equal(t, "sat 07:00-09:30", schedule.FormatWindows(loaded.Schedule.Windows))
+}
+
+func TestConfigSet_Errors(t *testing.T) {
+	withTempHome(t)
+
+	tests := []struct {
+		name string
+		cmd  ConfigSetCmd
+		code string
+	}{
+		{name: "unknown key", cmd: ConfigSetCmd{Key: "nope", Value: "1"}, code: "unknown_setting"},
+		{name: "bad bool", cmd: ConfigSetCmd{Key: "require_approval", Value: "maybe"}, code: "invalid_setting"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.cmd.Run(&Globals{})
+			var cliErr *CLIError
+			require.True(t, asCLIError(err, &cliErr))
+			assert.Equal(t, tt.code, cliErr.Code)
+		})
+	}
+}
+
+func TestConfigSet_DefaultTTL(t *testing.T) {
+	withTempHome(t)
+
+	captureStdout(t, func() {
+		assert.NoError(t, (&ConfigSetCmd{Key: "default_ttl", Value: "3d"}).Run(&Globals{}))
+	})
+	cfg, err := config.Load()
+	require.NoError(t, err)
+	assert.Equal(t, "3d", cfg.DefaultTTL)
+
+	err = (&ConfigSetCmd{Key: "default_ttl", Value: "soon"}).Run(&Globals{})
+	var cliErr *CLIError
+	require.True(t, asCLIError(err, &cliErr))
+	assert.Equal(t, "invalid_setting", cliErr.Code)
+
+	captureStdout(t, func() {
+		assert.NoError(t, (&ConfigSetCmd{Key: "default_ttl", Value: "off"}).Run(&Globals{}))
+	})
+	cfg, err = config.Load()
+	require.NoError(t, err)
+	assert.Empty(t, cfg.DefaultTTL)
+}
@@ -0,0 +1,187 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/lvrach/slack-social-ai/internal/config"
+	"github.com/lvrach/slack-social-ai/internal/history"
+)
+
+// longLockHold is how long a lock may be held before doctor warns that a
+// command may be hung.
+const longLockHold = time.Minute
+
+// DoctorCmd checks local state for problems that block other commands.
+type DoctorCmd struct {
+	Fix bool `help:"Clear stale locks left by commands that are gone."`
+}
+
+// doctorCheck is the outcome of one doctor check.
+type doctorCheck struct {
+	Name    string `json:"name"`
+	Status  string `json:"status"` // "ok" | "warning" | "problem" | "fixed"
+	Message string `json:"message"`
+}
+
+func (cmd *DoctorCmd) Run(globals *Globals) error {
+	checks := []doctorCheck{
+		cmd.checkLock(time.Now()),
+		checkHistoryFile(),
+		checkConfigFile(),
+	}
+
+	problems := 0
+	for _, c := range checks {
+		if c.Status == "problem" {
+			problems++
+		}
+	}
+
+	if globals.JSON {
+		resp := map[string]any{"status": "ok", "checks": checks}
+		if problems > 0 {
+			resp["status"] = "problems"
+		}
+		b, _ := json.Marshal(resp)
+		fmt.Fprintln(os.Stdout, string(b))
+	} else {
+		for _, c := range checks {
+			fmt.Fprintf(os.Stdout, "%-8s %-8s %s\n", c.Name, c.Status, c.Message)
+		}
+	}
+
+	if problems > 0 {
+		hint := ""
+		if !cmd.Fix {
+			hint = " Run `slack-social-ai doctor --fix` to clear stale locks."
+		}
+		return newCLIError(ExitRuntimeError, "doctor_problems",
+			fmt.Sprintf("%d problem%s found.%s", problems, plural(problems, "", "s"), hint))
+	}
+	return nil
+}
+
+func (cmd *DoctorCmd) checkLock(now time.Time) doctorCheck {
+	c := doctorCheck{Name: "lock"}
+	inspect := history.InspectLock
+	if cmd.Fix {
+		inspect = history.ClearStaleLock
+	}
+	state, err := inspect()
+	switch {
+	case err != nil:
+		c.Status, c.Message = "problem", fmt.Sprintf("cannot inspect history lock: %s", err)
+	case state.Stale && state.Held && cmd.Fix:
+		c.Status, c.Message = "warning", "cleared stale record of "+describeHolder(state.Holder)+
+			"; the lock is still held by another process, so other commands will time out until it exits"
+	case state.Stale && cmd.Fix:
+		c.Status, c.Message = "fixed", "cleared stale lock left by "+describeHolder(state.Holder)
+	case state.Stale:
+		c.Status, c.Message = "problem", "stale lock left by "+describeHolder(state.Holder)
+	case !state.Held:
+		c.Status, c.Message = "ok", "history lock is free"
+	default:
+		c.Status, c.Message = "ok", "held by "+describeHolder(state.Holder)
+		if state.Holder != nil {
+			if acquired, err := time.Parse(time.RFC3339, state.Holder.Acquired); err == nil &&
+				now.Sub(acquired) > longLockHold {
+				c.Status = "warning"
+				c.Message += fmt.Sprintf(" for %s; other commands will time out until it exits",
+					now.Sub(acquired).Round(time.Second))
+			}
+		}
+	}
+	return c
+}
+
+func checkHistoryFile() doctorCheck {
+	c := doctorCheck{Name: "history"}
+	entries, err := history.Load()
+	if err != nil {
+		c.Status, c.Message = "problem", fmt.Sprintf("cannot read history: %s", err)
+		if hErr := historyError(err); hErr != nil {
+			c.Message = hErr.Message
+		}
+		if errors.Is(err, history.ErrLockTimeout) {
+			c.Status = "warning"
+		}
+		return c
+	}
+	c.Status, c.Message = "ok", fmt.Sprintf("%d entr%s", len(entries), plural(len(entries), "y", "ies"))
+	return c
+}
+
+func checkConfigFile() doctorCheck {
+	c := doctorCheck{Name: "config"}
+	if _, err := config.Load(); err != nil {
+		c.Status, c.Message = "problem", fmt.Sprintf("cannot read config: %s", err)
+		return c
+	}
+	c.Status, c.Message = "ok", "readable"
+	return c
+}
+
+// describeHolder renders a lock holder for messages.
+func describeHolder(holder *history.LockHolder) string {
+	if holder == nil {
+		return "an unknown process"
+	}
+	desc := fmt.Sprintf("PID %d", holder.PID)
+	if holder.Command != "" {
+		desc += fmt.Sprintf(" (%s)", holder.Command)
+	}
+	if holder.Acquired != "" {
+		desc += " since " + holder.Acquired
+	}
+	return desc
+}
+
+// lockTimeoutError converts a history lock timeout into a lock_timeout
+// CLIError naming the holder, or returns nil for other errors.
+func lockTimeoutError(err error) *CLIError {
+	var lockErr *history.LockTimeoutError
+	if !errors.As(err, &lockErr) {
+		return nil
+	}
+	cliErr := newCLIError(ExitRuntimeError, "lock_timeout",
+		fmt.Sprintf("History is locked by %s; gave up after %s. "+
+			"Wait for it to finish, or run `slack-social-ai doctor --fix` if it has exited.",
+			describeHolder(lockErr.Holder), lockErr.Waited))
+	cliErr.Details = map[string]any{"waited_seconds": lockErr.Waited.Seconds()}
+	if h := lockErr.Holder; h != nil {
+		cliErr.Details["holder_pid"] = h.PID
+		cliErr.Details["holder_command"] = h.Command
+		cliErr.Details["holder_since"] = h.Acquired
+	}
+	return cliErr
+}
+
+// historyError converts history lock timeouts and corruption into CLIErrors
+// that tell the user what to do, or returns nil for other errors.
+func historyError(err error) *CLIError {
+	if lockErr := lockTimeoutError(err); lockErr != nil {
+		return lockErr
+	}
+	if errors.Is(err, history.ErrCorrupt) {
+		return newCLIError(ExitRuntimeError, "history_corrupt",
+			fmt.Sprintf("%s. Run `slack-social-ai history repair` to recover it.", err))
+	}
+	if errors.Is(err, history.ErrNewerVersion) {
+		return newCLIError(ExitRuntimeError, "unsupported_version",
+			fmt.Sprintf("%s. Downgrades are not supported.", err))
+	}
+	return nil
+}
+
+// historyFailure returns historyError(err) if set, otherwise a runtime
+// CLIError with the given code and message.
+func historyFailure(err error, code, message string) *CLIError {
+	if cliErr := historyError(err); cliErr != nil {
+		return cliErr
+	}
+	return newCLIError(ExitRuntimeError, code, message)
+}
@@ -0,0 +1,141 @@
+package main
+
+import (
+	"encoding/json"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/gofrs/flock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/lvrach/slack-social-ai/internal/history"
+)
+
+func dataFile(name string) string {
+	home, _ := os.UserHomeDir()
+	return filepath.Join(home, ".local", "share", "slack-social-ai", name)
+}
+
+// writeStaleLockHolder leaves a holder record from a process that has exited.
+func writeStaleLockHolder(t *testing.T) int {
+	t.Helper()
+	cmd := exec.Command("true")
+	require.NoError(t, cmd.Run())
+	pid := cmd.Process.Pid
+	data, err := json.Marshal(history.LockHolder{PID: pid, Command: "slack-social-ai queue inspect"})
+	require.NoError(t, err)
+	require.NoError(t, os.WriteFile(dataFile("history.lock.json"), data, 0o600))
+	return pid
+}
+
+func TestDoctor_Healthy(t *testing.T) {
+	withTempHome(t)
+
+	output := captureStdout(t, func() {
+		assert.NoError(t, (&DoctorCmd{}).Run(&Globals{JSON: true}))
+	})
+	var resp struct {
+		Status string        `json:"status"`
+		Checks []doctorCheck `json:"checks"`
+	}
+	require.NoError(t, json.Unmarshal([]byte(output), &resp))
+	assert.Equal(t, "ok", resp.Status)
+	for _, c := range resp.Checks {
+		assert.Equal(t, "ok", c.Status, c.Name)
+	}
+}
+
+func TestDoctor_StaleLock(t *testing.T) {
+	withTempHome(t)
+	writeStaleLockHolder(t)
+
+	var err error
+	output := captureStdout(t, func() {
+		err = (&DoctorCmd{}).Run(&Globals{})
+	})
+	var cliErr *CLIError
+	require.True(t, asCLIError(err, &cliErr))
+	assert.Equal(t, "doctor_problems", cliErr.Code)
+	assert.Contains(t, cliErr.Message, "doctor --fix")
+	assert.Contains(t, output, "stale lock left by PID")
+	assert.FileExists(t, dataFile("history.lock.json"))
+
+	output = captureStdout(t, func() {
+		assert.NoError(t, (&DoctorCmd{Fix: true}).Run(&Globals{}))
+	})
+	assert.Contains(t, output, "cleared stale lock")
+	assert.NoFileExists(t, dataFile("history.lock.json"))
+}
+
+func TestDoctor_LongHeldLock(t *testing.T) {
+	withTempHome(t)
+
+	fileLock := flock.New(dataFile("history.lock"))
+	locked, err := fileLock.TryLock()
+	require.NoError(t, err)
+	require.True(t, locked)
+	t.Cleanup(func() { _ = fileLock.Unlock() })
+	host, _ := os.Hostname()
+	data, err := json.Marshal(history.LockHolder{
+		PID:      os.Getpid(),
+		Command:  "slack-social-ai queue inspect",
+		Host:     host,
+		Acquired: time.Now().Add(-10 * time.Minute).UTC().Format(time.RFC3339),
+	})
+	require.NoError(t, err)
+	require.NoError(t, os.WriteFile(dataFile("history.lock.json"), data, 0o600))
+
+	c := (&DoctorCmd{Fix: true}).checkLock(time.Now())
+	assert.Equal(t, "warning", c.Status)
+	assert.Contains(t, c.Message, "queue inspect")
+	assert.FileExists(t, dataFile("history.lock.json"), "a live holder must not be cleared")
+}
+
+func TestLockTimeoutError(t *testing.T) {
+	err := &history.LockTimeoutError{
+		Holder: &history.LockHolder{PID: 4242, Command: "slack-social-ai queue edit", Acquired: "2026-03-01T10:00:00Z"},
+		Waited: 10 * time.Second,
+	}
+
+	cliErr := historyFailure(err, "queue_failed", "Failed to queue message")
+	assert.Equal(t, "lock_timeout", cliErr.Code)
+	assert.Contains(t, cliErr.Message, "PID 4242 (slack-social-ai queue edit)")
+	assert.Equal(t, 4242, cliErr.Details["holder_pid"])
+	assert.InDelta(t, 10.0, cliErr.Details["waited_seconds"], 0.001)
+
+	other := historyFailure(os.ErrPermission, "queue_failed", "Failed to queue message")
+	assert.Equal(t, "queue_failed", other.Code)
+	assert.Nil(t, lockTimeoutError(os.ErrPermission))
+}
+
+func TestHistoryError_NewerVersion(t *testing.T) {
+	withTempHome(t)
+	require.NoError(t, os.WriteFile(dataFile("history.json"), []byte(`{"version": 99, "entries": []}`), 0o600))
+
+	err := (&QueueShowCmd{}).Run(&Globals{})
+	var cliErr *CLIError
+	require.True(t, asCLIError(err, &cliErr))
+	assert.Equal(t, "unsupported_version", cliErr.Code)
+	assert.Contains(t, cliErr.Message, "upgrade slack-social-ai")
+}
+
+func TestDoctor_StaleRecordOfHeldLock(t *testing.T) {
+	withTempHome(t)
+
+	fileLock := flock.New(dataFile("history.lock"))
+	locked, err := fileLock.TryLock()
+	require.NoError(t, err)
+	require.True(t, locked)
+	t.Cleanup(func() { _ = fileLock.Unlock() })
+	writeStaleLockHolder(t)
+
+	c := (&DoctorCmd{Fix: true}).checkLock(time.Now())
+	assert.Equal(t, "warning", c.Status)
+	assert.Contains(t, c.Message, "still held")
+	assert.NoFileExists(t, dataFile("history.lock.json"))
+	assert.FileExists(t, dataFile("history.lock"), "a held lock must not be unlinked")
+}
@@ -0,0 +1,89 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/lvrach/slack-social-ai/internal/config"
+	"github.com/lvrach/slack-social-ai/internal/history"
+	"github.com/lvrach/slack-social-ai/internal/similarity"
+)
+
+// duplicateWindow is how far back published posts are compared against a
+// new message. Pending posts (queued, drafts) are always compared.
+const duplicateWindow = 30 * 24 * time.Hour
+
+// duplicateCandidates returns the entries a new post should not repeat:
+// everything still pending plus posts published within duplicateWindow.
+func duplicateCandidates(entries []history.Entry, now time.Time) []history.Entry {
+	var result []history.Entry
+	for _, e := range entries {
+		switch e.Status {
+		case "queued", "publishing", "draft":
+			result = append(result, e)
+		case "published":
+			published, err := time.Parse(time.RFC3339, e.PublishedAt)
+			if err == nil && now.Sub(published) <= duplicateWindow {
+				result = append(result, e)
+			}
+		}
+	}
+	return result
+}
+
+// findDuplicate returns the candidate most similar to message if its score
+// reaches threshold.
+func findDuplicate(message string, candidates []history.Entry, threshold float64) (history.Entry, float64, bool) {
+	texts := make([]string, len(candidates))
+	for i, e := range candidates {
+		texts[i] = e.Message
+	}
+	m, ok := similarity.Best(message, texts)
+	if !ok || m.Score < threshold {
+		return history.Entry{}, 0, false
+	}
+	return candidates[m.Index], m.Score, true
+}
+
+// checkDuplicate applies the configured near-duplicate policy to message.
+// It returns a duplicate_suspected CLIError when the policy is "refuse",
+// prints a warning to stderr when it is "warn", and does nothing when "off".
+func checkDuplicate(message string, cfg config.Config, now time.Time) error {
+	if cfg.DuplicatePolicy() == "off" {
+		return nil
+	}
+	entries, err := history.Load()
+	if err != nil {
+		return nil // best-effort: a history read error shouldn't block posting
+	}
+	return duplicateError(message, duplicateCandidates(entries, now), cfg)
+}
+
+// duplicateError applies the configured near-duplicate policy to message,
+// comparing it against candidates.
+func duplicateError(message string, candidates []history.Entry, cfg config.Config) error {
+	policy := cfg.DuplicatePolicy()
+	if policy == "off" {
+		return nil
+	}
+	match, score, found := findDuplicate(message, candidates, cfg.DuplicateLimit())
+	if !found {
+		return nil
+	}
+
+	desc := fmt.Sprintf("Message is %.0f%% similar to %s entry %s: %q",
+		score*100, match.Status, match.ID, truncate(firstLine(match.Message), 60))
+	if policy == "warn" {
+		fmt.Fprintf(os.Stderr, "Warning: %s.\n", desc)
+		return nil
+	}
+	cliErr := newCLIError(ExitInvalidInput, "duplicate_suspected",
+		desc+". Use --force to post anyway.")
+	cliErr.Details = map[string]any{
+		"match_id":     match.ID,
+		"match_status": match.Status,
+		"similarity":   score,
+	}
+	return cliErr
+}
@@ -0,0 +1,80 @@
+package main
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/lvrach/slack-social-ai/internal/config"
+	"github.com/lvrach/slack-social-ai/internal/history"
+)
+
+const dupMessage = "r/go\nTIL: errors.Is walks the whole wrap chain, so comparing sentinels just works."
+
+func TestDuplicateCandidates(t *testing.T) {
+	now := time.Date(2026, 3, 2, 12, 0, 0, 0, time.UTC)
+	recent := now.Add(-24 * time.Hour).Format(time.RFC3339)
+	old := now.Add(-60 * 24 * time.Hour).Format(time.RFC3339)
+
+	entries := []history.Entry{
+		{ID: "q", Status: "queued"},
+		{ID: "d", Status: "draft"},
+		{ID: "new", Status: "published", PublishedAt: recent},
+		{ID: "old", Status: "published", PublishedAt: old},
+		{ID: "x", Status: "expired"},
+	}
+	var ids []string
+	for _, e := range duplicateCandidates(entries, now) {
+		ids = append(ids, e.ID)
+	}
+	assert.Equal(t, []string{"q", "d", "new"}, ids)
+}
+
+func TestCheckDuplicate_Refuse(t *testing.T) {
+	withTempHome(t)
+
+	match, err := history.Append(dupMessage, "queued", time.Time{})
+	require.NoError(t, err)
+
+	err = checkDuplicate(dupMessage+"!", config.Default(), time.Now())
+	var cliErr *CLIError
+	require.True(t, asCLIError(err, &cliErr))
+	assert.Equal(t, "duplicate_suspected", cliErr.Code)
+	assert.Equal(t, ExitInvalidInput, cliErr.ExitCode)
+	assert.Equal(t, match.ID, cliErr.Details["match_id"])
+	assert.Contains(t, cliErr.Message, match.ID)
+	assert.Contains(t, cliErr.Message, "--force")
+}
+
+func TestCheckDuplicate_Distinct(t *testing.T) {
+	withTempHome(t)
+
+	_, err := history.Append(dupMessage, "queued", time.Time{})
+	require.NoError(t, err)
+
+	err = checkDuplicate("r/ai\nHot take: agents should write tests before touching prod.", config.Default(), time.Now())
+	assert.NoError(t, err)
+}
+
+func TestCheckDuplicate_Policy(t *testing.T) {
+	withTempHome(t)
+
+	_, err := history.Append(dupMessage, "queued", time.Time{})
+	require.NoError(t, err)
+
+	assert.NoError(t, checkDuplicate(dupMessage, config.Config{DuplicateAction: "warn"}, time.Now()))
+	assert.NoError(t, checkDuplicate(dupMessage, config.Config{DuplicateAction: "off"}, time.Now()))
+}
+
+func TestCheckDuplicate_Threshold(t *testing.T) {
+	withTempHome(t)
+
+	_, err := history.Append(dupMessage, "queued", time.Time{})
+	require.NoError(t, err)
+	reworded := "r/go\nTIL errors.Is walks the entire wrap chain, so sentinel comparisons just work."
+
+	assert.NoError(t, checkDuplicate(reworded, config.Config{DuplicateThreshold: 0.99}, time.Now()))
+	assert.Error(t, checkDuplicate(reworded, config.Config{DuplicateThreshold: 0.3}, time.Now()))
+}
@@ -4,21 +4,41 @@ import (
 	"encoding/json"
 	"fmt"
 	"os"
+	"slices"
 	"strings"
+	"time"
 
 	"github.com/lvrach/slack-social-ai/internal/history"
 )
 
-// HistoryCmd shows or manages post history.
+// HistoryCmd shows or manages post history. Listing is the default
+// subcommand, so `history [flags]` keeps working as it did before
+// `history stats` and `history repair` existed.
 type HistoryCmd struct {
-	QueuedOnly bool   `name:"queued" help:"Show only queued messages."`
-	Published  bool   `name:"published" help:"Show only published messages."`
-	Remove     string `help:"Remove a specific entry by ID."`
-	Clear      bool   `help:"Clear published history (keeps queue)."`
-	ClearAll   bool   `name:"clear-all" help:"Clear everything (published + queued)."`
+	List   HistoryListCmd   `cmd:"" default:"withargs" help:"List post history (or remove/clear entries)."`
+	Stats  HistoryStatsCmd  `cmd:"" help:"Show posting statistics."`
+	Repair HistoryRepairCmd `cmd:"" help:"Recover a corrupt history file from what still parses and the latest good backup."`
 }
 
-func (cmd *HistoryCmd) Run(globals *Globals) error {
+// HistoryListCmd lists or manages history entries.
+type HistoryListCmd struct {
+	QueuedOnly bool     `name:"queued" help:"Show only queued messages."`
+	Published  bool     `name:"published" help:"Show only published messages."`
+	Tag        []string `help:"Show only entries with this tag (repeatable)." short:"t"`
+	Agent      string   `help:"Show only entries queued by this agent (e.g. claude)."`
+	Status     []string `help:"Show only entries with this status (repeatable), e.g. published, queued, expired."`
+	Since      string   `help:"Show only entries from this time on (2h, 3d, HH:MM, 2006-01-02, or RFC3339)."`
+	Until      string   `help:"Show only entries before this time (same formats as --since; 2h means two hours ago)."`
+	Limit      int      `help:"Show at most N entries, newest first (0 = all)." short:"n"`
+	Offset     int      `help:"Skip the N newest matching entries." xor:"page"`
+	Cursor     string   `help:"Continue after this entry ID (the last one shown, or next_cursor from --page-info)." xor:"page"`
+	PageInfo   bool     `name:"page-info" help:"With --json, print {\"entries\", \"pagination\"} (total, has_more, next_cursor) instead of a plain array."`
+	Remove     string   `help:"Remove a specific entry by ID."`
+	Clear      bool     `help:"Clear published history (keeps queue)."`
+	ClearAll   bool     `name:"clear-all" help:"Clear everything (published + queued)."`
+}
+
+func (cmd *HistoryListCmd) Run(globals *Globals) error {
 	if cmd.ClearAll {
 		return cmd.clearAll(globals)
 	}
@@ -31,11 +51,11 @@ func (cmd *HistoryCmd) Run(globals *Globals) error {
 	return cmd.list(globals)
 }
 
-func (cmd *HistoryCmd) clearAll(globals *Globals) error {
+func (cmd *HistoryListCmd) clearAll(globals *Globals) error {
 	if err := history.ClearAll(); err != nil {
 		return fmt.Errorf("clear history: %w", err)
 	}
-	msg := "All history cleared."
+	msg := "All history cleared. Run `slack-social-ai undo` to restore."
 	if globals.JSON {
 		printSuccessJSON(msg)
 	} else {
@@ -44,11 +64,11 @@ func (cmd *HistoryCmd) clearAll(globals *Globals) error {
 	return nil
 }
 
-func (cmd *HistoryCmd) clearPublished(globals *Globals) error {
+func (cmd *HistoryListCmd) clearPublished(globals *Globals) error {
 	if err := history.ClearPublished(); err != nil {
 		return fmt.Errorf("clear published: %w", err)
 	}
-	msg := "Published history cleared. Queued messages preserved."
+	msg := "Published history cleared. Queued messages preserved. Run `slack-social-ai undo` to restore."
 	if globals.JSON {
 		printSuccessJSON(msg)
 	} else {
@@ -57,7 +77,7 @@ func (cmd *HistoryCmd) clearPublished(globals *Globals) error {
 	return nil
 }
 
-func (cmd *HistoryCmd) remove(globals *Globals, id string) error {
+func (cmd *HistoryListCmd) remove(globals *Globals, id string) error {
 	found, err := history.Remove(id)
 	if err != nil {
 		return fmt.Errorf("remove entry: %w", err)
@@ -66,7 +86,7 @@ func (cmd *HistoryCmd) remove(globals *Globals, id string) error {
 		return newCLIError(ExitInvalidInput, "not_found",
 			fmt.Sprintf("Entry %q not found.", id))
 	}
-	msg := fmt.Sprintf("Entry %s removed.", id)
+	msg := fmt.Sprintf("Entry %s removed. Run `slack-social-ai undo` to restore.", id)
 	if globals.JSON {
 		printSuccessJSON(msg)
 	} else {
@@ -75,7 +95,7 @@ func (cmd *HistoryCmd) remove(globals *Globals, id string) error {
 	return nil
 }
 
-func (cmd *HistoryCmd) list(globals *Globals) error {
+func (cmd *HistoryListCmd) list(globals *Globals) error {
 	var entries []history.Entry
 	var err error
 
@@ -90,12 +110,142 @@ func (cmd *HistoryCmd) list(globals *Globals) error {
 	if err != nil {
 		return fmt.Errorf("load history: %w", err)
 	}
+	entries = history.FilterByTags(entries, normalizeTags(cmd.Tag))
+	entries = filterByAgent(entries, cmd.Agent)
+	entries = filterByStatus(entries, cmd.Status)
+
+	now := time.Now().In(scheduleLocation())
+	var since, until time.Time
+	if cmd.Since != "" {
+		if since, err = parseSince(cmd.Since, now); err != nil {
+			return err
+		}
+	}
+	if cmd.Until != "" {
+		if until, err = parseSince(cmd.Until, now); err != nil {
+			return err
+		}
+	}
+	entries = filterByTime(entries, since, until)
 
 	// Reverse so most recent entries appear first.
-	for i, j := 0, len(entries)-1; i < j; i, j = i+1, j-1 {
-		entries[i], entries[j] = entries[j], entries[i]
+	slices.Reverse(entries)
+
+	// --json is always a plain array; --page-info opts in to the envelope,
+	// whether or not the listing is paged.
+	page, err := paginate(entries, cmd.Limit, cmd.Offset, cmd.Cursor)
+	if err != nil {
+		return err
+	}
+	if globals.JSON && cmd.PageInfo {
+		if page.Entries == nil {
+			page.Entries = []history.Entry{}
+		}
+		return json.NewEncoder(os.Stdout).Encode(page)
+	}
+	if err := printEntries(globals, page.Entries); err != nil {
+		return err
+	}
+	if !globals.JSON && page.Pagination.HasMore {
+		fmt.Printf("\nShowing %d of %d. Next page: --cursor %s\n",
+			len(page.Entries), page.Pagination.Total, page.Pagination.NextCursor)
 	}
+	return nil
+}
+
+// entryPage is the --json --page-info shape of a history listing.
+type entryPage struct {
+	Entries    []history.Entry `json:"entries"`
+	Pagination pageInfo        `json:"pagination"`
+}
+
+type pageInfo struct {
+	Total      int    `json:"total"`  // matching entries across all pages
+	Offset     int    `json:"offset"` // index of the first entry on this page
+	Limit      int    `json:"limit,omitempty"`
+	HasMore    bool   `json:"has_more"`
+	NextCursor string `json:"next_cursor,omitempty"` // pass to --cursor for the next page
+}
 
+// paginate slices newest-first entries into one page. The page starts at
+// offset, or just after the entry whose ID is cursor. Cursors stay valid as
+// new posts arrive because they anchor on an entry rather than a count.
+func paginate(entries []history.Entry, limit, offset int, cursor string) (entryPage, error) {
+	if limit < 0 || offset < 0 {
+		return entryPage{}, newCLIError(ExitInvalidInput, "invalid_page",
+			"--limit and --offset must not be negative.")
+	}
+	if cursor != "" {
+		i := slices.IndexFunc(entries, func(e history.Entry) bool { return e.ID == cursor })
+		if i == -1 {
+			return entryPage{}, newCLIError(ExitInvalidInput, "invalid_cursor",
+				fmt.Sprintf("Cursor %q does not match any entry in this listing.", cursor))
+		}
+		offset = i + 1
+	}
+
+	start := min(offset, len(entries))
+	end := len(entries)
+	if limit > 0 {
+		end = min(start+limit, len(entries))
+	}
+	page := entryPage{
+		Entries: entries[start:end],
+		Pagination: pageInfo{
+			Total:   len(entries),
+			Offset:  start,
+			Limit:   limit,
+			HasMore: end < len(entries),
+		},
+	}
+	if page.Pagination.HasMore && end > start {
+		page.Pagination.NextCursor = entries[end-1].ID
+	}
+	return page, nil
+}
+
+// filterByStatus keeps entries with one of statuses (case-insensitive).
+// An empty list returns entries unchanged.
+func filterByStatus(entries []history.Entry, statuses []string) []history.Entry {
+	if len(statuses) == 0 {
+		return entries
+	}
+	var result []history.Entry
+	for _, e := range entries {
+		if slices.ContainsFunc(statuses, func(s string) bool { return strings.EqualFold(s, e.Status) }) {
+			result = append(result, e)
+		}
+	}
+	return result
+}
+
+// filterByTime keeps entries whose time falls in [since, until).
+// Zero bounds are open. Published entries are dated by when they were
+// published, everything else by when it was created.
+func filterByTime(entries []history.Entry, since, until time.Time) []history.Entry {
+	if since.IsZero() && until.IsZero() {
+		return entries
+	}
+	var result []history.Entry
+	for _, e := range entries {
+		ts := e.CreatedAt
+		if e.PublishedAt != "" {
+			ts = e.PublishedAt
+		}
+		t, err := time.Parse(time.RFC3339, ts)
+		if err != nil {
+			continue
+		}
+		if (!since.IsZero() && t.Before(since)) || (!until.IsZero() && !t.Before(until)) {
+			continue
+		}
+		result = append(result, e)
+	}
+	return result
+}
+
+// printEntries prints entries as a JSON array or as human-readable blocks.
+func printEntries(globals *Globals, entries []history.Entry) error {
 	if globals.JSON {
 		if entries == nil {
 			entries = []history.Entry{}
@@ -119,13 +269,22 @@ func (cmd *HistoryCmd) list(globals *Globals) error {
 			scheduledInfo = fmt.Sprintf(" [at %s]", formatShortTime(e.ScheduledAt))
 		}
 
-		// Show ID for queued/publishing entries (useful for --remove).
+		// Show ID for entries still to publish (for --remove, and for
+		// `queue approve` on drafts).
 		idInfo := ""
-		if e.Status == "queued" || e.Status == "publishing" {
+		if e.Status != "published" {
 			idInfo = fmt.Sprintf("  (id: %s)", e.ID)
 		}
 
-		fmt.Printf("[%s] [%s]%s%s\n", formatShortTime(ts), status, scheduledInfo, idInfo)
+		tagInfo := ""
+		if len(e.Tags) > 0 {
+			tagInfo = " " + formatTags(e.Tags)
+		}
+
+		fmt.Printf("[%s] [%s]%s%s%s\n", formatShortTime(ts), status, tagInfo, scheduledInfo, idInfo)
+		if prov := formatProvenance(e.Agent, e.SessionID, e.Source); prov != "" {
+			fmt.Printf("(%s)\n", prov)
+		}
 		fmt.Println(e.Message)
 		if i < len(entries)-1 {
 			fmt.Println(separator)
@@ -134,6 +293,21 @@ func (cmd *HistoryCmd) list(globals *Globals) error {
 	return nil
 }
 
+// filterByAgent keeps entries queued by agent (case-insensitive).
+// An empty agent returns entries unchanged.
+func filterByAgent(entries []history.Entry, agent string) []history.Entry {
+	if agent == "" {
+		return entries
+	}
+	var result []history.Entry
+	for _, e := range entries {
+		if strings.EqualFold(e.Agent, agent) {
+			result = append(result, e)
+		}
+	}
+	return result
+}
+
 // formatShortTime extracts HH:MM from an RFC3339 timestamp for display,
 // or returns the raw string if parsing fails.
 func formatShortTime(rfc3339 string) string {
@@ -0,0 +1,59 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/lvrach/slack-social-ai/internal/history"
+)
+
+// HistoryRepairCmd recovers a corrupt history file.
+type HistoryRepairCmd struct{}
+
+func (cmd *HistoryRepairCmd) Run(globals *Globals) error {
+	report, err := history.Repair()
+	if err != nil {
+		return historyFailure(err, "repair_failed",
+			fmt.Sprintf("Failed to repair history: %s", err))
+	}
+
+	if globals.JSON {
+		resp := map[string]any{"status": "repaired", "report": report}
+		if report.Healthy {
+			resp["status"] = "healthy"
+		}
+		b, _ := json.Marshal(resp)
+		fmt.Fprintln(os.Stdout, string(b))
+		return nil
+	}
+
+	if report.Healthy {
+		fmt.Fprintf(os.Stdout, "History is healthy (%d %s). Nothing to repair.\n",
+			report.Entries, plural(report.Entries, "entry", "entries"))
+		return nil
+	}
+
+	fmt.Fprintf(os.Stdout, "Repaired history: %d %s.\n",
+		report.Entries, plural(report.Entries, "entry", "entries"))
+	fmt.Fprintf(os.Stdout, "  Salvaged from the damaged file: %d\n", report.Salvaged)
+	if report.Backup != "" {
+		fmt.Fprintf(os.Stdout, "  Restored from backup %s: %d", report.Backup, len(report.Restored))
+		if len(report.Restored) > 0 {
+			fmt.Fprintf(os.Stdout, " (%s)", strings.Join(report.Restored, ", "))
+		}
+		fmt.Fprintln(os.Stdout)
+	} else {
+		fmt.Fprintln(os.Stdout, "  No usable backup found.")
+	}
+	if len(report.Lost) > 0 {
+		fmt.Fprintf(os.Stdout, "  Lost: %d damaged %s\n",
+			len(report.Lost), plural(len(report.Lost), "fragment", "fragments"))
+		for _, l := range report.Lost {
+			fmt.Fprintf(os.Stdout, "    - %s  %s (%d bytes)\n", orDash(l.ID), orDash(l.Preview), l.Bytes)
+		}
+	}
+	fmt.Fprintf(os.Stdout, "Damaged file kept at %s.\n", report.CorruptCopy)
+	return nil
+}
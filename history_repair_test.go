@@ -0,0 +1,66 @@
+package main
+
+import (
+	"encoding/json"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/lvrach/slack-social-ai/internal/history"
+)
+
+func TestHistoryRepair_Truncated(t *testing.T) {
+	withTempHome(t)
+
+	for _, m := range []string{"first", "second"} {
+		_, err := history.Append(m, "queued", time.Time{})
+		require.NoError(t, err)
+	}
+	data, err := os.ReadFile(dataFile("history.json"))
+	require.NoError(t, err)
+	require.NoError(t, os.WriteFile(dataFile("history.json"), data[:len(data)/2], 0o600))
+
+	// Commands report the corruption with a pointer to repair.
+	err = (&QueueShowCmd{}).Run(&Globals{})
+	var cliErr *CLIError
+	require.True(t, asCLIError(err, &cliErr))
+	assert.Equal(t, "history_corrupt", cliErr.Code)
+	assert.Contains(t, cliErr.Message, "history repair")
+
+	output := captureStdout(t, func() {
+		assert.NoError(t, (&HistoryRepairCmd{}).Run(&Globals{JSON: true}))
+	})
+	var resp struct {
+		Status string               `json:"status"`
+		Report history.RepairReport `json:"report"`
+	}
+	require.NoError(t, json.Unmarshal([]byte(output), &resp))
+	assert.Equal(t, "repaired", resp.Status)
+	assert.Equal(t, 2, resp.Report.Entries)
+	assert.NotEmpty(t, resp.Report.Backup)
+
+	queued, err := history.Queued()
+	require.NoError(t, err)
+	assert.Len(t, queued, 2)
+}
+
+func TestHistoryRepair_Healthy(t *testing.T) {
+	withTempHome(t)
+
+	output := captureStdout(t, func() {
+		assert.NoError(t, (&HistoryRepairCmd{}).Run(&Globals{}))
+	})
+	assert.Contains(t, output, "Nothing to repair")
+}
+
+func TestDoctor_CorruptHistory(t *testing.T) {
+	withTempHome(t)
+	require.NoError(t, os.WriteFile(dataFile("history.json"), []byte("[{"), 0o600))
+
+	c := checkHistoryFile()
+	assert.Equal(t, "problem", c.Status)
+	assert.Contains(t, c.Message, "history repair")
+}
@@ -0,0 +1,430 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/lvrach/slack-social-ai/internal/history"
+)
+
+// onTimeTolerance is how late a post may go out and still count as on time.
+// It covers the gap between publish runs (the launchd timer fires every 10m).
+const onTimeTolerance = 15 * time.Minute
+
+// HistoryStatsCmd reports posting statistics.
+type HistoryStatsCmd struct {
+	Days int `help:"Number of days covered by the per-day and per-week counts." default:"28"`
+}
+
+// tagCount is the number of entries carrying a tag.
+type tagCount struct {
+	Tag   string `json:"tag"`
+	Count int    `json:"count"`
+}
+
+// namedCount is the number of entries in a named bucket (mood, agent, day).
+type namedCount struct {
+	Name  string `json:"name"`
+	Count int    `json:"count"`
+}
+
+// historyStats is everything `history stats` reports. The JSON shape keeps
+// the original total/tags/untagged fields at the top level.
+type historyStats struct {
+	Total     int          `json:"total"`
+	Published int          `json:"published"`
+	Tags      []tagCount   `json:"tags"`
+	Untagged  int          `json:"untagged"`
+	Statuses  []namedCount `json:"statuses"`
+	Moods     []namedCount `json:"moods"`
+	Agents    []namedCount `json:"agents"`
+	PerDay    []namedCount `json:"per_day"`  // published posts per local date, oldest first
+	PerWeek   []namedCount `json:"per_week"` // published posts per ISO week, oldest first
+	Wait      waitStats    `json:"wait"`
+	OnTime    onTimeStats  `json:"on_time"`
+	Failures  failureStats `json:"failures"`
+	Heatmap   [7][24]int   `json:"heatmap"` // published posts by local weekday (Mon=0) and hour
+}
+
+// waitStats measures time from queueing to publishing.
+type waitStats struct {
+	Samples       int     `json:"samples"`
+	AverageMinute float64 `json:"average_minutes"`
+	MedianMinute  float64 `json:"median_minutes"`
+}
+
+// onTimeStats compares publish times with the slot predicted when the post
+// was queued, or the time it was scheduled for if that is later.
+type onTimeStats struct {
+	Samples          int     `json:"samples"`
+	OnTime           int     `json:"on_time"`
+	Late             int     `json:"late"`
+	Rate             float64 `json:"rate"` // 0..1; 0 when there are no samples
+	ToleranceMinutes int     `json:"tolerance_minutes"`
+}
+
+// failureStats counts posts that did not go out as planned.
+type failureStats struct {
+	Failed    int `json:"failed"`    // entries in "failed" status
+	Expired   int `json:"expired"`   // entries that hit their TTL
+	Retried   int `json:"retried"`   // webhook failures reset to queued (audit log)
+	Recovered int `json:"recovered"` // stuck publishes recovered (audit log)
+}
+
+func (cmd *HistoryStatsCmd) Run(globals *Globals) error {
+	entries, err := history.Load()
+	if err != nil {
+		return fmt.Errorf("load history: %w", err)
+	}
+	events, _ := history.ReadAudit() // best-effort: only feeds failure counts
+
+	stats := computeStats(entries, events, cmd.Days, time.Now().In(scheduleLocation()))
+
+	if globals.JSON {
+		return json.NewEncoder(os.Stdout).Encode(stats)
+	}
+
+	if len(entries) == 0 {
+		fmt.Fprintln(os.Stdout, "No history.")
+		return nil
+	}
+	printStats(stats, cmd.Days)
+	return nil
+}
+
+// computeStats builds statistics from history entries and audit events.
+// Daily and weekly counts cover the last days days up to now.
+func computeStats(entries []history.Entry, events []history.AuditEvent, days int, now time.Time) historyStats {
+	s := historyStats{Total: len(entries)}
+	s.Tags, s.Untagged = countTags(entries)
+	if s.Tags == nil {
+		s.Tags = []tagCount{}
+	}
+
+	statuses := map[string]int{}
+	moods := map[string]int{}
+	agents := map[string]int{}
+	var waits []float64
+	for _, e := range entries {
+		statuses[e.Status]++
+		moods[inferMood(e.Message)]++
+		agent := e.Agent
+		if agent == "" {
+			agent = "(none)"
+		}
+		agents[agent]++
+
+		switch e.Status {
+		case "failed":
+			s.Failures.Failed++
+		case "expired":
+			s.Failures.Expired++
+		}
+
+		published, ok := parseRFC3339(e.PublishedAt)
+		if e.Status != "published" || !ok {
+			continue
+		}
+		s.Published++
+		local := published.In(now.Location())
+		s.Heatmap[(int(local.Weekday())+6)%7][local.Hour()]++
+
+		if created, ok := parseRFC3339(e.CreatedAt); ok && published.After(created) {
+			waits = append(waits, published.Sub(created).Minutes())
+		}
+
+		// A post rescheduled after it was queued keeps its old prediction,
+		// so it is measured against the later of the two.
+		predicted, hasPrediction := parseRFC3339(e.PredictedAt)
+		scheduled, hasSchedule := parseRFC3339(e.ScheduledAt)
+		slot := predicted
+		if hasSchedule && (!hasPrediction || scheduled.After(predicted)) {
+			slot = scheduled
+		}
+		if hasPrediction || hasSchedule {
+			s.OnTime.Samples++
+			if published.After(slot.Add(onTimeTolerance)) {
+				s.OnTime.Late++
+			} else {
+				s.OnTime.OnTime++
+			}
+		}
+	}
+	s.Statuses = rankCounts(statuses)
+	s.Moods = rankCounts(moods)
+	s.Agents = rankCounts(agents)
+	s.Wait = summarizeWaits(waits)
+	s.OnTime.ToleranceMinutes = int(onTimeTolerance.Minutes())
+	if s.OnTime.Samples > 0 {
+		s.OnTime.Rate = float64(s.OnTime.OnTime) / float64(s.OnTime.Samples)
+	}
+
+	for _, ev := range events {
+		switch ev.Action {
+		case "reset":
+			s.Failures.Retried++
+		case "recover":
+			s.Failures.Recovered++
+		}
+	}
+
+	s.PerDay, s.PerWeek = countPerDay(entries, days, now)
+	return s
+}
+
+// countPerDay counts published posts per local date and per ISO week over
+// the last days days, including days with no posts.
+func countPerDay(entries []history.Entry, days int, now time.Time) (perDay, perWeek []namedCount) {
+	if days <= 0 {
+		return []namedCount{}, []namedCount{}
+	}
+	loc := now.Location()
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, loc)
+	first := today.AddDate(0, 0, -(days - 1))
+
+	byDay := map[string]int{}
+	for _, e := range entries {
+		published, ok := parseRFC3339(e.PublishedAt)
+		if e.Status != "published" || !ok {
+			continue
+		}
+		byDay[published.In(loc).Format(time.DateOnly)]++
+	}
+
+	perDay = make([]namedCount, 0, days)
+	perWeek = []namedCount{}
+	for d := first; !d.After(today); d = d.AddDate(0, 0, 1) {
+		n := byDay[d.Format(time.DateOnly)]
+		perDay = append(perDay, namedCount{Name: d.Format(time.DateOnly), Count: n})
+
+		year, week := d.ISOWeek()
+		label := fmt.Sprintf("%d-W%02d", year, week)
+		if len(perWeek) == 0 || perWeek[len(perWeek)-1].Name != label {
+			perWeek = append(perWeek, namedCount{Name: label})
+		}
+		perWeek[len(perWeek)-1].Count += n
+	}
+	return perDay, perWeek
+}
+
+// moodMarkers maps a mood (from the guide's "Rotate the mood" list) to
+// hook-line emoji and phrases that signal it. Order matters: the first
+// match wins.
+var moodMarkers = []struct {
+	mood    string
+	markers []string
+}{
+	{mood: "hot-take", markers: []string{":hot_pepper:", ":fire:", "hot take"}},
+	{mood: "psa", markers: []string{":warning:", ":rotating_light:", ":no_entry:", "psa"}},
+	{mood: "til", markers: []string{"til", "today i learned"}},
+	{mood: "fun", markers: []string{":joy:", ":laughing:", ":sweat_smile:", ":upside_down_face:", ":clown_face:", ":see_no_evil:", ":melting_face:"}},
+	{mood: "question", markers: []string{":thinking_face:", ":question:", ":raising_hand:"}},
+}
+
+// inferMood guesses a post's mood from its hook line (the first line after
+// the r/<topic> header). Posts with no recognizable marker count as
+// "insight", the guide's default serious technical register.
+func inferMood(message string) string {
+	lines := strings.Split(strings.TrimSpace(message), "\n")
+	if len(lines) > 1 && parseTopic(message) != "" {
+		lines = lines[1:]
+	}
+	hook := strings.ToLower(strings.TrimSpace(lines[0]))
+	words := " " + strings.Join(strings.FieldsFunc(hook, isWordSep), " ") + " "
+	for _, m := range moodMarkers {
+		for _, marker := range m.markers {
+			if strings.HasPrefix(marker, ":") && strings.Contains(hook, marker) ||
+				strings.Contains(words, " "+marker+" ") {
+				return m.mood
+			}
+		}
+	}
+	if strings.HasSuffix(strings.TrimRight(hook, "*_ "), "?") {
+		return "question"
+	}
+	return "insight"
+}
+
+func isWordSep(r rune) bool {
+	return !(r >= 'a' && r <= 'z' || r >= '0' && r <= '9')
+}
+
+// summarizeWaits returns the average and median of wait times in minutes.
+func summarizeWaits(waits []float64) waitStats {
+	if len(waits) == 0 {
+		return waitStats{}
+	}
+	sorted := slices.Clone(waits)
+	slices.Sort(sorted)
+	sum := 0.0
+	for _, w := range sorted {
+		sum += w
+	}
+	median := sorted[len(sorted)/2]
+	if len(sorted)%2 == 0 {
+		median = (sorted[len(sorted)/2-1] + sorted[len(sorted)/2]) / 2
+	}
+	return waitStats{
+		Samples:       len(sorted),
+		AverageMinute: sum / float64(len(sorted)),
+		MedianMinute:  median,
+	}
+}
+
+// rankCounts sorts buckets by count (desc) then name.
+func rankCounts(counts map[string]int) []namedCount {
+	result := make([]namedCount, 0, len(counts))
+	for name, n := range counts {
+		result = append(result, namedCount{Name: name, Count: n})
+	}
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].Count != result[j].Count {
+			return result[i].Count > result[j].Count
+		}
+		return result[i].Name < result[j].Name
+	})
+	return result
+}
+
+func parseRFC3339(s string) (time.Time, bool) {
+	if s == "" {
+		return time.Time{}, false
+	}
+	t, err := time.Parse(time.RFC3339, s)
+	return t, err == nil
+}
+
+// printStats renders stats for the terminal.
+func printStats(s historyStats, days int) {
+	w := os.Stdout
+	fmt.Fprintf(w, "Entries: %d (%d published)\n\n", s.Total, s.Published)
+
+	printCounts("By status:", s.Statuses)
+
+	fmt.Fprintln(w, "By tag:")
+	for _, tc := range s.Tags {
+		fmt.Fprintf(w, "  %-16s %d\n", "r/"+tc.Tag, tc.Count)
+	}
+	if s.Untagged > 0 {
+		fmt.Fprintf(w, "  %-16s %d\n", "(untagged)", s.Untagged)
+	}
+	fmt.Fprintln(w)
+
+	printCounts("By mood:", s.Moods)
+	printCounts("By agent:", s.Agents)
+
+	if len(s.PerWeek) > 0 {
+		total := 0
+		for _, d := range s.PerDay {
+			total += d.Count
+		}
+		fmt.Fprintf(w, "Published in the last %d days: %d (%.1f/day)\n", days, total, float64(total)/float64(days))
+		for _, wk := range s.PerWeek {
+			fmt.Fprintf(w, "  %-16s %-3d %s\n", wk.Name, wk.Count, strings.Repeat("■", wk.Count))
+		}
+		fmt.Fprintln(w)
+	}
+
+	if s.Wait.Samples > 0 {
+		fmt.Fprintf(w, "Queue wait: avg %s, median %s (%d posts)\n",
+			formatMinutes(s.Wait.AverageMinute), formatMinutes(s.Wait.MedianMinute), s.Wait.Samples)
+	}
+	if s.OnTime.Samples > 0 {
+		fmt.Fprintf(w, "On time: %.0f%% (%d of %d within %dm of predicted slot)\n",
+			s.OnTime.Rate*100, s.OnTime.OnTime, s.OnTime.Samples, s.OnTime.ToleranceMinutes)
+	}
+	fmt.Fprintf(w, "Failures: %d failed, %d expired, %d webhook retries, %d recovered\n\n",
+		s.Failures.Failed, s.Failures.Expired, s.Failures.Retried, s.Failures.Recovered)
+
+	if s.Published > 0 {
+		fmt.Fprintln(w, "Publish heatmap (local time):")
+		printHeatmap(s.Heatmap)
+	}
+}
+
+func printCounts(title string, counts []namedCount) {
+	fmt.Fprintln(os.Stdout, title)
+	for _, c := range counts {
+		fmt.Fprintf(os.Stdout, "  %-16s %d\n", c.Name, c.Count)
+	}
+	fmt.Fprintln(os.Stdout)
+}
+
+// heatShades maps relative intensity to a cell, lightest first.
+var heatShades = []string{"·", "░", "▒", "▓", "█"}
+
+// printHeatmap prints publish counts as a weekday × hour grid.
+func printHeatmap(grid [7][24]int) {
+	peak := 0
+	for _, row := range grid {
+		for _, n := range row {
+			peak = max(peak, n)
+		}
+	}
+
+	fmt.Fprint(os.Stdout, "     ")
+	for h := 0; h < 24; h += 3 {
+		fmt.Fprintf(os.Stdout, "%-3d", h)
+	}
+	fmt.Fprintln(os.Stdout)
+	for d, name := range []string{"Mon", "Tue", "Wed", "Thu", "Fri", "Sat", "Sun"} {
+		var b strings.Builder
+		for _, n := range grid[d] {
+			shade := 0
+			switch {
+			case n > 0 && peak > 1:
+				shade = 1 + (n-1)*(len(heatShades)-2)/(peak-1)
+			case n > 0:
+				shade = len(heatShades) - 1
+			}
+			b.WriteString(heatShades[shade])
+		}
+		fmt.Fprintf(os.Stdout, " %s %s\n", name, b.String())
+	}
+	fmt.Fprintf(os.Stdout, "     (%s none … %s busiest hour, %d posts)\n", heatShades[0], heatShades[len(heatShades)-1], peak)
+}
+
+// formatMinutes renders a duration given in minutes, e.g. "45m" or "3h10m".
+func formatMinutes(m float64) string {
+	d := time.Duration(m * float64(time.Minute)).Round(time.Minute)
+	if d < time.Hour {
+		return fmt.Sprintf("%dm", int(d.Minutes()))
+	}
+	if d < 48*time.Hour {
+		return fmt.Sprintf("%dh%02dm", int(d.Hours()), int(d.Minutes())%60)
+	}
+	return fmt.Sprintf("%.1fd", d.Hours()/24)
+}
+
+// countTags tallies entries per tag, sorted by count (desc) then tag name.
+// It also returns the number of entries without any tag.
+func countTags(entries []history.Entry) ([]tagCount, int) {
+	counts := make(map[string]int)
+	untagged := 0
+	for _, e := range entries {
+		if len(e.Tags) == 0 {
+			untagged++
+			continue
+		}
+		for _, t := range e.Tags {
+			counts[t]++
+		}
+	}
+
+	var result []tagCount
+	for tag, n := range counts {
+		result = append(result, tagCount{Tag: tag, Count: n})
+	}
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].Count != result[j].Count {
+			return result[i].Count > result[j].Count
+		}
+		return result[i].Tag < result[j].Tag
+	})
+	return result, untagged
+}
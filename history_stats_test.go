@@ -0,0 +1,144 @@
+package main
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/lvrach/slack-social-ai/internal/config"
+	"github.com/lvrach/slack-social-ai/internal/history"
+)
+
+func TestInferMood(t *testing.T) {
+	tests := []struct {
+		message string
+		want    string
+	}{
+		{"r/go\n:bulb: *TIL: errors.Is walks the chain*\n\nbody", "til"},
+		{"r/ai\n:hot_pepper: *Prompts are APIs*", "hot-take"},
+		{"r/ai\n*Hot take: tests are specs*", "hot-take"},
+		{"r/security\n:warning: *PSA: rotate your tokens*", "psa"},
+		{"r/programming\n:joy: *The build passed on the first try*", "fun"},
+		{"r/go\n:bulb: *Who else vendors everything?*", "question"},
+		{"r/go\n:bulb: *sync.Pool is not a cache*", "insight"},
+		{"plain message, no header", "insight"},
+		{"r/go\n*Until recently I thought...*", "insight"}, // "til" must be a whole word
+	}
+	for _, tt := range tests {
+		assert.Equal(t, tt.want, inferMood(tt.message), tt.message)
+	}
+}
+
+func TestComputeStats(t *testing.T) {
+	loc := time.FixedZone("test", 2*3600)
+	now := time.Date(2026, 3, 4, 18, 0, 0, 0, loc) // Wednesday
+	at := func(day, hour, minute int) string {
+		return time.Date(2026, 3, day, hour, minute, 0, 0, loc).UTC().Format(time.RFC3339)
+	}
+
+	entries := []history.Entry{
+		// Published Mon 10:20, queued 60m earlier, predicted 10:10 → on time.
+		{Status: "published", Agent: "claude", Message: "r/go\n*TIL: x*",
+			CreatedAt: at(2, 9, 20), PublishedAt: at(2, 10, 20), PredictedAt: at(2, 10, 10)},
+		// Published Wed 10:50, queued 120m earlier, predicted 10:00 → late.
+		{Status: "published", Agent: "claude", Message: "r/go\n:fire: *Hot take*",
+			CreatedAt: at(4, 8, 50), PublishedAt: at(4, 10, 50), PredictedAt: at(4, 10, 0)},
+		// Published Wed 10:05, scheduled 10:00, no prediction → on time.
+		{Status: "published", Message: "plain",
+			CreatedAt: at(4, 9, 5), PublishedAt: at(4, 10, 5), ScheduledAt: at(4, 10, 0)},
+		{Status: "expired", Message: "stale", CreatedAt: at(1, 9, 0)},
+		{Status: "queued", Agent: "cursor", Message: "later", CreatedAt: at(4, 12, 0)},
+	}
+	events := []history.AuditEvent{{Action: "reset"}, {Action: "reset"}, {Action: "recover"}, {Action: "append"}}
+
+	s := computeStats(entries, events, 7, now)
+
+	assert.Equal(t, 5, s.Total)
+	assert.Equal(t, 3, s.Published)
+	assert.Equal(t, []namedCount{{"published", 3}, {"expired", 1}, {"queued", 1}}, s.Statuses)
+	assert.Equal(t, []namedCount{{"(none)", 2}, {"claude", 2}, {"cursor", 1}}, s.Agents)
+	assert.Equal(t, []namedCount{{"insight", 3}, {"hot-take", 1}, {"til", 1}}, s.Moods)
+
+	assert.Equal(t, 3, s.Wait.Samples)
+	assert.InDelta(t, 80.0, s.Wait.AverageMinute, 0.01)
+	assert.InDelta(t, 60.0, s.Wait.MedianMinute, 0.01)
+
+	assert.Equal(t, onTimeStats{Samples: 3, OnTime: 2, Late: 1, Rate: 2.0 / 3, ToleranceMinutes: 15}, s.OnTime)
+	assert.Equal(t, failureStats{Expired: 1, Retried: 2, Recovered: 1}, s.Failures)
+
+	assert.Equal(t, 1, s.Heatmap[0][10], "Monday 10:00 local")
+	assert.Equal(t, 2, s.Heatmap[2][10], "Wednesday 10:00 local")
+
+	require.Len(t, s.PerDay, 7)
+	assert.Equal(t, namedCount{"2026-03-04", 2}, s.PerDay[6])
+	assert.Equal(t, namedCount{"2026-03-02", 1}, s.PerDay[4])
+	assert.Equal(t, []namedCount{{"2026-W09", 0}, {"2026-W10", 3}}, s.PerWeek)
+}
+
+func TestComputeStats_Snoozed(t *testing.T) {
+	withTempHome(t)
+
+	// Predicted three hours ago, then snoozed to now.
+	entry, err := history.AppendEntry(history.Entry{
+		Message: "snoozed", Status: "queued",
+		PredictedAt: time.Now().Add(-3 * time.Hour).UTC().Format(time.RFC3339),
+	})
+	require.NoError(t, err)
+	_, err = history.Snooze(entry.ID, time.Now())
+	require.NoError(t, err)
+	claimed, err := history.ClaimNextReady()
+	require.NoError(t, err)
+	require.NotNil(t, claimed)
+	require.NoError(t, history.MarkPublished(claimed.ID))
+
+	entries, err := history.Load()
+	require.NoError(t, err)
+	s := computeStats(entries, nil, 7, time.Now())
+	assert.Equal(t, onTimeStats{Samples: 1, OnTime: 1, Rate: 1, ToleranceMinutes: 15}, s.OnTime,
+		"measured against the snoozed time, not the stale prediction")
+}
+
+func TestHistoryStats_Human(t *testing.T) {
+	withTempHome(t)
+
+	_, err := history.AppendEntry(history.Entry{Message: "r/go\n*TIL: x*", Status: "published", Tags: []string{"go"}})
+	require.NoError(t, err)
+
+	output := captureStdout(t, func() {
+		assert.NoError(t, (&HistoryStatsCmd{Days: 7}).Run(&Globals{}))
+	})
+	assert.Contains(t, output, "Entries: 1 (1 published)")
+	assert.Contains(t, output, "By mood:")
+	assert.Contains(t, output, "Published in the last 7 days: 1")
+	assert.Contains(t, output, "Publish heatmap")
+	assert.Contains(t, output, "busiest hour, 1 posts")
+}
+
+func TestFormatMinutes(t *testing.T) {
+	assert.Equal(t, "45m", formatMinutes(45))
+	assert.Equal(t, "3h10m", formatMinutes(190))
+	assert.Equal(t, "2.5d", formatMinutes(60*60))
+}
+
+func TestHistoryStats_ScheduleTimezone(t *testing.T) {
+	withTempHome(t)
+	cfg := config.Default()
+	cfg.Schedule.Timezone = "Asia/Tokyo"
+	require.NoError(t, config.Save(cfg))
+	entry, err := history.AppendEntry(history.Entry{Message: "out", Status: "published"})
+	require.NoError(t, err)
+
+	output := captureStdout(t, func() {
+		require.NoError(t, (&HistoryStatsCmd{Days: 7}).Run(&Globals{JSON: true}))
+	})
+	var s historyStats
+	require.NoError(t, json.Unmarshal([]byte(output), &s))
+	published, err := time.Parse(time.RFC3339, entry.PublishedAt)
+	require.NoError(t, err)
+	local := published.In(cfg.Schedule.Location())
+	assert.Equal(t, 1, s.Heatmap[(int(local.Weekday())+6)%7][local.Hour()], "bucketed in the schedule's zone")
+	assert.Equal(t, namedCount{local.Format(time.DateOnly), 1}, s.PerDay[len(s.PerDay)-1])
+}
@@ -0,0 +1,158 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/lvrach/slack-social-ai/internal/history"
+)
+
+// pagedEntries builds n published entries, oldest first, one hour apart.
+func pagedEntries(n int, start time.Time) []history.Entry {
+	entries := make([]history.Entry, n)
+	for i := range entries {
+		ts := start.Add(time.Duration(i) * time.Hour).UTC().Format(time.RFC3339)
+		entries[i] = history.Entry{
+			ID:          fmt.Sprintf("e%02d", i),
+			Message:     fmt.Sprintf("post %d", i),
+			Status:      "published",
+			CreatedAt:   ts,
+			PublishedAt: ts,
+		}
+	}
+	return entries
+}
+
+func TestPaginate(t *testing.T) {
+	entries := pagedEntries(5, time.Now())
+	slices.Reverse(entries)
+
+	page, err := paginate(entries, 2, 0, "")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"e04", "e03"}, entryIDs(page.Entries))
+	assert.Equal(t, pageInfo{Total: 5, Offset: 0, Limit: 2, HasMore: true, NextCursor: "e03"}, page.Pagination)
+
+	page, err = paginate(entries, 2, 0, "e03")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"e02", "e01"}, entryIDs(page.Entries))
+	assert.Equal(t, 2, page.Pagination.Offset)
+
+	page, err = paginate(entries, 2, 4, "")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"e00"}, entryIDs(page.Entries))
+	assert.False(t, page.Pagination.HasMore)
+	assert.Empty(t, page.Pagination.NextCursor)
+
+	page, err = paginate(entries, 0, 10, "")
+	require.NoError(t, err)
+	assert.Empty(t, page.Entries)
+
+	_, err = paginate(entries, 2, 0, "nope")
+	var cliErr *CLIError
+	require.True(t, asCLIError(err, &cliErr))
+	assert.Equal(t, "invalid_cursor", cliErr.Code)
+}
+
+func TestFilterByTime(t *testing.T) {
+	start := time.Date(2026, 3, 1, 10, 0, 0, 0, time.UTC)
+	entries := pagedEntries(4, start) // 10:00, 11:00, 12:00, 13:00
+
+	got := filterByTime(entries, start.Add(time.Hour), start.Add(3*time.Hour))
+	assert.Equal(t, []string{"e01", "e02"}, entryIDs(got), "since inclusive, until exclusive")
+
+	got = filterByTime(entries, time.Time{}, start.Add(time.Hour))
+	assert.Equal(t, []string{"e00"}, entryIDs(got))
+}
+
+func TestFilterByStatus(t *testing.T) {
+	entries := []history.Entry{
+		{ID: "a", Status: "published"},
+		{ID: "b", Status: "queued"},
+		{ID: "c", Status: "expired"},
+	}
+	assert.Equal(t, []string{"a", "c"}, entryIDs(filterByStatus(entries, []string{"Published", "expired"})))
+	assert.Len(t, filterByStatus(entries, nil), 3)
+}
+
+func TestHistoryList_Paginated_JSON(t *testing.T) {
+	withTempHome(t)
+	writeHistoryEntries(t, pagedEntries(5, time.Now().Add(-10*time.Hour)))
+
+	output := captureStdout(t, func() {
+		assert.NoError(t, (&HistoryListCmd{Limit: 3, PageInfo: true}).Run(&Globals{JSON: true}))
+	})
+	var page entryPage
+	require.NoError(t, json.Unmarshal([]byte(output), &page))
+	assert.Equal(t, []string{"e04", "e03", "e02"}, entryIDs(page.Entries))
+	assert.Equal(t, 5, page.Pagination.Total)
+	assert.Equal(t, "e02", page.Pagination.NextCursor)
+
+	output = captureStdout(t, func() {
+		assert.NoError(t, (&HistoryListCmd{Limit: 3, Cursor: page.Pagination.NextCursor, PageInfo: true}).Run(&Globals{JSON: true}))
+	})
+	require.NoError(t, json.Unmarshal([]byte(output), &page))
+	assert.Equal(t, []string{"e01", "e00"}, entryIDs(page.Entries))
+	assert.False(t, page.Pagination.HasMore)
+}
+
+func TestHistoryList_JSONArray(t *testing.T) {
+	withTempHome(t)
+	writeHistoryEntries(t, pagedEntries(3, time.Now().Add(-10*time.Hour)))
+
+	output := captureStdout(t, func() {
+		assert.NoError(t, (&HistoryListCmd{Since: "9h30m"}).Run(&Globals{JSON: true}))
+	})
+	var entries []history.Entry
+	require.NoError(t, json.Unmarshal([]byte(output), &entries), "without --page-info the output is a plain array")
+	assert.Equal(t, []string{"e02", "e01"}, entryIDs(entries))
+
+	// Paging does not change the shape; the last ID continues the listing.
+	output = captureStdout(t, func() {
+		assert.NoError(t, (&HistoryListCmd{Limit: 1}).Run(&Globals{JSON: true}))
+	})
+	require.NoError(t, json.Unmarshal([]byte(output), &entries))
+	assert.Equal(t, []string{"e02"}, entryIDs(entries))
+	output = captureStdout(t, func() {
+		assert.NoError(t, (&HistoryListCmd{Limit: 1, Cursor: entries[0].ID}).Run(&Globals{JSON: true}))
+	})
+	require.NoError(t, json.Unmarshal([]byte(output), &entries))
+	assert.Equal(t, []string{"e01"}, entryIDs(entries))
+}
+
+func TestHistoryList_Paginated_Human(t *testing.T) {
+	withTempHome(t)
+	writeHistoryEntries(t, pagedEntries(4, time.Now().Add(-10*time.Hour)))
+
+	output := captureStdout(t, func() {
+		assert.NoError(t, (&HistoryListCmd{Limit: 1, Status: []string{"published"}}).Run(&Globals{}))
+	})
+	assert.Contains(t, output, "post 3")
+	assert.NotContains(t, output, "post 2")
+	assert.Contains(t, output, "Showing 1 of 4. Next page: --cursor e03")
+}
+
+func TestHistoryList_ShowsDraftID(t *testing.T) {
+	withTempHome(t)
+
+	d, err := history.Append("needs review", "draft", time.Time{})
+	require.NoError(t, err)
+
+	output := captureStdout(t, func() {
+		assert.NoError(t, (&HistoryListCmd{}).Run(&Globals{}))
+	})
+	assert.Contains(t, output, "(id: "+d.ID+")")
+}
+
+func entryIDs(entries []history.Entry) []string {
+	ids := make([]string, len(entries))
+	for i, e := range entries {
+		ids[i] = e.ID
+	}
+	return ids
+}
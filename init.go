@@ -112,7 +112,7 @@ func (cmd *InitCmd) stepSchedule(globals *Globals) error {
 	}
 
 	// Save default schedule.
-	cfg := config.Config{Schedule: schedule.DefaultSchedule()}
+	cfg := config.Default()
 	if err := config.Save(cfg); err != nil {
 		return fmt.Errorf("save config: %w", err)
 	}
@@ -191,9 +191,8 @@ func (cmd *InitCmd) printSummary() {
 	// Schedule status.
 	if cfg, err := config.Load(); err == nil && config.Exists() {
 		sched := cfg.Schedule
-		fmt.Fprintf(os.Stdout, "  Schedule: %s %02d:00–%02d:00, every %s\n",
-			formatWeekdays(sched.Weekdays), sched.StartHour, sched.EndHour,
-			sched.PostEvery())
+		fmt.Fprintf(os.Stdout, "  Schedule: %s, every %s\n",
+			formatActiveHours(sched), sched.PostEvery())
 	} else {
 		fmt.Fprintln(os.Stdout, "  Schedule: not configured")
 	}
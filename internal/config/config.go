@@ -5,23 +5,103 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"time"
 
+	"github.com/lvrach/slack-social-ai/internal/paths"
 	"github.com/lvrach/slack-social-ai/internal/schedule"
 )
 
+// CurrentVersion is the config format written by this binary.
+//
+// Version history:
+//
+//	0: one start_hour/end_hour pair for every day in schedule.weekdays
+//	1: per-weekday windows with minute precision (schedule.windows)
+const CurrentVersion = 1
+
 // Config holds the application configuration.
 type Config struct {
+	// Version is the config format; see CurrentVersion. Load migrates
+	// older files and Save always writes CurrentVersion.
+	Version int `json:"version"`
+
 	Schedule schedule.Schedule `json:"schedule"`
+
+	// RequireApproval queues posts from non-interactive callers (agents,
+	// scripts) as drafts that a human must approve before publishing.
+	RequireApproval bool `json:"require_approval,omitempty"`
+
+	// DefaultTTL is applied to posts queued without --expires, e.g. "3d".
+	// Empty means posts never expire.
+	DefaultTTL string `json:"default_ttl,omitempty"`
+
+	// DuplicateThreshold is the similarity (0..1) at which post treats a
+	// message as a near-duplicate of a recent one. Zero uses the default.
+	DuplicateThreshold float64 `json:"duplicate_threshold,omitempty"`
+
+	// DuplicateAction is what post does with a near-duplicate:
+	// "refuse" (default), "warn", or "off".
+	DuplicateAction string `json:"duplicate_action,omitempty"`
+
+	// UndoRetentionDays is how long undo snapshots of removed entries are
+	// kept. Zero uses the default.
+	UndoRetentionDays int `json:"undo_retention_days,omitempty"`
+
+	// LockTimeoutSeconds is how long commands wait for another command to
+	// release the history lock. Zero uses the default.
+	LockTimeoutSeconds int `json:"lock_timeout_seconds,omitempty"`
+}
+
+// DefaultUndoRetentionDays is used when UndoRetentionDays is unset.
+const DefaultUndoRetentionDays = 7
+
+// DefaultDuplicateThreshold is used when DuplicateThreshold is unset.
+const DefaultDuplicateThreshold = 0.8
+
+// DuplicateLimit returns the configured near-duplicate threshold, or the default.
+func (c Config) DuplicateLimit() float64 {
+	if c.DuplicateThreshold <= 0 {
+		return DefaultDuplicateThreshold
+	}
+	return c.DuplicateThreshold
+}
+
+// UndoRetention returns how long undo snapshots are kept.
+func (c Config) UndoRetention() time.Duration {
+	days := c.UndoRetentionDays
+	if days <= 0 {
+		days = DefaultUndoRetentionDays
+	}
+	return time.Duration(days) * 24 * time.Hour
+}
+
+// LockTimeout returns how long commands wait for the history lock, or zero
+// for the history package default.
+func (c Config) LockTimeout() time.Duration {
+	if c.LockTimeoutSeconds <= 0 {
+		return 0
+	}
+	return time.Duration(c.LockTimeoutSeconds) * time.Second
+}
+
+// DuplicatePolicy returns the configured near-duplicate action, or "refuse".
+func (c Config) DuplicatePolicy() string {
+	if c.DuplicateAction == "" {
+		return "refuse"
+	}
+	return c.DuplicateAction
+}
+
+// Default returns the configuration used when no config file exists.
+func Default() Config {
+	return Config{Version: CurrentVersion, Schedule: schedule.DefaultSchedule()}
 }
 
 // configDir returns the config directory path.
 // Exported as a var for testing.
 var configDir = defaultConfigDir
 
-func defaultConfigDir() string {
-	home, _ := os.UserHomeDir()
-	return filepath.Join(home, ".config", "slack-social-ai")
-}
+func defaultConfigDir() string { return paths.ConfigDir() }
 
 func configPath() string {
 	return filepath.Join(configDir(), "config.json")
@@ -38,7 +118,7 @@ func Load() (Config, error) {
 	data, err := os.ReadFile(configPath())
 	if err != nil {
 		if os.IsNotExist(err) {
-			return Config{Schedule: schedule.DefaultSchedule()}, nil
+			return Default(), nil
 		}
 		return Config{}, err
 	}
@@ -47,12 +127,45 @@ func Load() (Config, error) {
 	if err := json.Unmarshal(data, &cfg); err != nil {
 		return Config{}, fmt.Errorf("parse config: %w", err)
 	}
+	if cfg.Version < 1 {
+		if err := migrateHours(data, &cfg); err != nil {
+			return Config{}, fmt.Errorf("migrate config: %w", err)
+		}
+	}
 
 	return cfg, nil
 }
 
-// Save writes the config to disk.
+// migrateHours converts a version 0 schedule, one hour range shared by the
+// listed weekdays, to per-weekday windows. A config without a version that
+// already has windows, or has no hour range, is kept as written.
+func migrateHours(data []byte, cfg *Config) error {
+	var legacy struct {
+		Schedule struct {
+			StartHour *int     `json:"start_hour"`
+			EndHour   *int     `json:"end_hour"`
+			Weekdays  []string `json:"weekdays"`
+		} `json:"schedule"`
+	}
+	if err := json.Unmarshal(data, &legacy); err != nil {
+		return err
+	}
+	old := legacy.Schedule
+	cfg.Version = 1
+	if old.StartHour == nil || old.EndHour == nil || len(cfg.Schedule.Windows) > 0 {
+		return nil
+	}
+	cfg.Schedule.Windows = map[string][]schedule.Window{}
+	if *old.StartHour < *old.EndHour {
+		cfg.Schedule.Windows = schedule.SameWindows(old.Weekdays,
+			schedule.Window{Start: schedule.At(*old.StartHour, 0), End: schedule.At(*old.EndHour, 0)})
+	}
+	return nil
+}
+
+// Save writes the config to disk in the current format.
 func Save(cfg Config) error {
+	cfg.Version = CurrentVersion
 	dir := configDir()
 	if err := os.MkdirAll(dir, 0o700); err != nil {
 		return err
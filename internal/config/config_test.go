@@ -22,9 +22,9 @@ func TestSaveLoad_RoundTrip(t *testing.T) {
 	original := Config{
 		Schedule: schedule.Schedule{
 			PostEveryMinutes: 60,
-			StartHour:        10,
-			EndHour:          20,
-			Weekdays:         []string{"mon", "wed", "fri"},
+			Windows: schedule.SameWindows([]string{"mon", "wed", "fri"},
+				schedule.Window{Start: schedule.At(10, 0), End: schedule.At(12, 30)},
+				schedule.Window{Start: schedule.At(14, 0), End: schedule.At(20, 0)}),
 		},
 	}
 
@@ -40,19 +40,11 @@ func TestSaveLoad_RoundTrip(t *testing.T) {
 	if loaded.Schedule.PostEveryMinutes != original.Schedule.PostEveryMinutes {
 		t.Errorf("PostEveryMinutes = %d, want %d", loaded.Schedule.PostEveryMinutes, original.Schedule.PostEveryMinutes)
 	}
-	if loaded.Schedule.StartHour != original.Schedule.StartHour {
-		t.Errorf("StartHour = %d, want %d", loaded.Schedule.StartHour, original.Schedule.StartHour)
+	if got, want := schedule.FormatWindows(loaded.Schedule.Windows), schedule.FormatWindows(original.Schedule.Windows); got != want {
+		t.Errorf("Windows = %q, want %q", got, want)
 	}
-	if loaded.Schedule.EndHour != original.Schedule.EndHour {
-		t.Errorf("EndHour = %d, want %d", loaded.Schedule.EndHour, original.Schedule.EndHour)
-	}
-	if len(loaded.Schedule.Weekdays) != len(original.Schedule.Weekdays) {
-		t.Fatalf("Weekdays len = %d, want %d", len(loaded.Schedule.Weekdays), len(original.Schedule.Weekdays))
-	}
-	for i, d := range loaded.Schedule.Weekdays {
-		if d != original.Schedule.Weekdays[i] {
-			t.Errorf("Weekdays[%d] = %q, want %q", i, d, original.Schedule.Weekdays[i])
-		}
+	if loaded.Version != CurrentVersion {
+		t.Errorf("Version = %d, want %d", loaded.Version, CurrentVersion)
 	}
 
 	// Verify file was written with correct permissions.
@@ -74,22 +66,62 @@ func TestLoad_Missing(t *testing.T) {
 	}
 
 	defaults := schedule.DefaultSchedule()
-	if cfg.Schedule.StartHour != defaults.StartHour {
-		t.Errorf("StartHour = %d, want %d", cfg.Schedule.StartHour, defaults.StartHour)
-	}
-	if cfg.Schedule.EndHour != defaults.EndHour {
-		t.Errorf("EndHour = %d, want %d", cfg.Schedule.EndHour, defaults.EndHour)
+	if got, want := schedule.FormatWindows(cfg.Schedule.Windows), schedule.FormatWindows(defaults.Windows); got != want {
+		t.Errorf("Windows = %q, want %q", got, want)
 	}
 	if cfg.Schedule.PostEveryMinutes != defaults.PostEveryMinutes {
 		t.Errorf("PostEveryMinutes = %d, want %d", cfg.Schedule.PostEveryMinutes, defaults.PostEveryMinutes)
 	}
-	if len(cfg.Schedule.Weekdays) != len(defaults.Weekdays) {
-		t.Fatalf("Weekdays len = %d, want %d", len(cfg.Schedule.Weekdays), len(defaults.Weekdays))
+}
+
+func TestLoad_MigratesHours(t *testing.T) {
+	withTempConfigDir(t)
+
+	// A version 0 config, from before per-weekday windows.
+	legacy := `{"schedule": {"post_every_minutes": 90, "start_hour": 10, "end_hour": 18, "weekdays": ["mon", "tue", "sat"]}, "require_approval": true}`
+	if err := os.MkdirAll(configDir(), 0o700); err != nil {
+		t.Fatalf("MkdirAll: %v", err)
 	}
-	for i, d := range cfg.Schedule.Weekdays {
-		if d != defaults.Weekdays[i] {
-			t.Errorf("Weekdays[%d] = %q, want %q", i, d, defaults.Weekdays[i])
-		}
+	if err := os.WriteFile(filepath.Join(configDir(), "config.json"), []byte(legacy), 0o600); err != nil {
+		t.Fatalf("WriteFile: %v", err)
+	}
+
+	cfg, err := Load()
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if got, want := schedule.FormatWindows(cfg.Schedule.Windows), "mon-tue 10:00-18:00; sat 10:00-18:00"; got != want {
+		t.Errorf("Windows = %q, want %q", got, want)
+	}
+	if cfg.Schedule.PostEveryMinutes != 90 || !cfg.RequireApproval {
+		t.Errorf("other settings lost: %+v", cfg)
+	}
+	if cfg.Version != CurrentVersion {
+		t.Errorf("Version = %d, want %d", cfg.Version, CurrentVersion)
+	}
+}
+
+func TestLoad_WindowsWithoutVersion(t *testing.T) {
+	withTempConfigDir(t)
+
+	// Hand-written with the current windows but no version field.
+	written := `{"schedule": {"post_every_minutes": 60, "windows": {"wed": [{"start": "08:30", "end": "12:00"}]}}}`
+	if err := os.MkdirAll(configDir(), 0o700); err != nil {
+		t.Fatalf("MkdirAll: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(configDir(), "config.json"), []byte(written), 0o600); err != nil {
+		t.Fatalf("WriteFile: %v", err)
+	}
+
+	cfg, err := Load()
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if got, want := schedule.FormatWindows(cfg.Schedule.Windows), "wed 08:30-12:00"; got != want {
+		t.Errorf("Windows = %q, want %q", got, want)
+	}
+	if cfg.Version != CurrentVersion {
+		t.Errorf("Version = %d, want %d", cfg.Version, CurrentVersion)
 	}
 }
 
@@ -110,3 +142,21 @@ func TestLoad_Corrupt(t *testing.T) {
 		t.Fatal("Load() with corrupt JSON should return error")
 	}
 }
+
+func TestDuplicateDefaults(t *testing.T) {
+	var cfg Config
+	if got := cfg.DuplicateLimit(); got != DefaultDuplicateThreshold {
+		t.Errorf("DuplicateLimit() = %v, want %v", got, DefaultDuplicateThreshold)
+	}
+	if got := cfg.DuplicatePolicy(); got != "refuse" {
+		t.Errorf("DuplicatePolicy() = %q, want %q", got, "refuse")
+	}
+
+	cfg = Config{DuplicateThreshold: 0.5, DuplicateAction: "warn"}
+	if got := cfg.DuplicateLimit(); got != 0.5 {
+		t.Errorf("DuplicateLimit() = %v, want 0.5", got)
+	}
+	if got := cfg.DuplicatePolicy(); got != "warn" {
+		t.Errorf("DuplicatePolicy() = %q, want %q", got, "warn")
+	}
+}
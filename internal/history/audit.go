@@ -0,0 +1,133 @@
+package history
+
+import (
+	"bufio"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// AuditEvent records one change to one history entry. Events are appended to
+// audit.jsonl and never rewritten, so they outlive the entries they describe.
+type AuditEvent struct {
+	Time    string `json:"ts"`     // RFC3339
+	Action  string `json:"action"` // e.g. "append", "claim", "remove", "evict"
+	EntryID string `json:"entry_id"`
+	Before  string `json:"before,omitempty"` // status before the change; empty when created
+	After   string `json:"after,omitempty"`  // status after the change; empty when deleted
+	Actor   string `json:"actor,omitempty"`  // agent or user that ran the command
+	Command string `json:"command,omitempty"`
+	Message string `json:"message,omitempty"` // first line of the entry, for identification
+	// Tags are recorded when an entry publishes, so that caps still count
+	// it after the entry is removed from history.
+	Tags []string `json:"tags,omitempty"`
+}
+
+// auditActor and auditCommand describe the running process. They are set
+// once by the CLI via SetAuditContext and stamped on every event.
+var auditActor, auditCommand string
+
+// SetAuditContext sets the actor and command recorded on audit events.
+func SetAuditContext(actor, command string) {
+	auditActor, auditCommand = actor, command
+}
+
+func auditPath() string { return filepath.Join(dataDir(), "audit.jsonl") }
+
+// auditPreviewLen caps the message excerpt stored on each event.
+const auditPreviewLen = 80
+
+// newEvent builds an audit event for a status transition of e.
+func newEvent(action string, e Entry, before, after string) AuditEvent {
+	ev := AuditEvent{
+		Action:  action,
+		EntryID: e.ID,
+		Before:  before,
+		After:   after,
+		Message: previewLine(e.Message),
+	}
+	if ev.published() {
+		ev.Tags = e.Tags
+	}
+	return ev
+}
+
+// published reports whether ev records an entry being published.
+func (ev AuditEvent) published() bool {
+	return ev.After == "published" && ev.Before != "published"
+}
+
+// previewLine returns the first line of msg, capped at auditPreviewLen runes.
+func previewLine(msg string) string {
+	for i, r := range msg {
+		if r == '\n' {
+			msg = msg[:i]
+			break
+		}
+	}
+	if r := []rune(msg); len(r) > auditPreviewLen {
+		msg = string(r[:auditPreviewLen-3]) + "..."
+	}
+	return msg
+}
+
+// save writes entries and then appends events to the audit log.
+// Must be called under withLock. Audit failures are ignored: the history
+// write has already happened and must not be reported as failed.
+func save(entries []Entry, events ...AuditEvent) error {
+	if err := atomicWrite(entries); err != nil {
+		return err
+	}
+	_ = appendAudit(events)
+	return nil
+}
+
+// appendAudit appends events to the audit log, one JSON object per line.
+func appendAudit(events []AuditEvent) error {
+	if len(events) == 0 {
+		return nil
+	}
+	f, err := os.OpenFile(auditPath(), os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o600)
+	if err != nil {
+		return err
+	}
+	now := time.Now().UTC().Format(time.RFC3339)
+	enc := json.NewEncoder(f)
+	for _, ev := range events {
+		if ev.Time == "" {
+			ev.Time = now
+		}
+		ev.Actor, ev.Command = auditActor, auditCommand
+		if err := enc.Encode(ev); err != nil {
+			_ = f.Close()
+			return err
+		}
+	}
+	return f.Close()
+}
+
+// ReadAudit returns all audit events, oldest first. Lines that fail to parse
+// (e.g. a write cut short by a crash) are skipped.
+func ReadAudit() ([]AuditEvent, error) {
+	f, err := os.Open(auditPath())
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	defer func() { _ = f.Close() }()
+
+	var events []AuditEvent
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		var ev AuditEvent
+		if err := json.Unmarshal(scanner.Bytes(), &ev); err != nil {
+			continue
+		}
+		events = append(events, ev)
+	}
+	return events, scanner.Err()
+}
@@ -0,0 +1,129 @@
+package history
+
+import (
+	"os"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func auditActions(t *testing.T) []string {
+	t.Helper()
+	events, err := ReadAudit()
+	require.NoError(t, err)
+	actions := make([]string, len(events))
+	for i, ev := range events {
+		actions[i] = ev.Action
+	}
+	return actions
+}
+
+func TestAudit_Lifecycle(t *testing.T) {
+	withTempDataDir(t)
+	SetAuditContext("tester", "publish")
+	t.Cleanup(func() { SetAuditContext("", "") })
+
+	e, err := Append("hello\nsecond line", "queued", time.Time{})
+	require.NoError(t, err)
+	_, err = ClaimNextReady()
+	require.NoError(t, err)
+	require.NoError(t, ResetToQueued(e.ID))
+	_, err = ClaimNextReady()
+	require.NoError(t, err)
+	require.NoError(t, MarkPublished(e.ID))
+	require.NoError(t, ClearPublished())
+
+	events, err := ReadAudit()
+	require.NoError(t, err)
+	require.Len(t, events, 6)
+
+	assert.Equal(t, []string{"append", "claim", "reset", "claim", "publish", "clear"}, auditActions(t))
+	for _, ev := range events {
+		assert.Equal(t, e.ID, ev.EntryID)
+		assert.Equal(t, "tester", ev.Actor)
+		assert.Equal(t, "publish", ev.Command)
+		assert.Equal(t, "hello", ev.Message, "only the first line is recorded")
+		assert.NotEmpty(t, ev.Time)
+	}
+	assert.Equal(t, "", events[0].Before)
+	assert.Equal(t, "queued", events[0].After)
+	assert.Equal(t, "published", events[5].Before)
+	assert.Equal(t, "", events[5].After)
+}
+
+func TestAudit_RemoveAndClearAll(t *testing.T) {
+	withTempDataDir(t)
+
+	a, err := Append("a", "queued", time.Time{})
+	require.NoError(t, err)
+	_, err = Append("b", "draft", time.Time{})
+	require.NoError(t, err)
+	_, err = Append("c", "queued", time.Time{})
+	require.NoError(t, err)
+
+	_, err = Remove(a.ID)
+	require.NoError(t, err)
+	require.NoError(t, ClearAll())
+
+	assert.Equal(t, []string{"append", "append", "append", "remove", "clear", "clear"}, auditActions(t))
+}
+
+func TestAudit_RecoverStuck(t *testing.T) {
+	withTempDataDir(t)
+
+	_, err := Append("stuck", "queued", time.Time{})
+	require.NoError(t, err)
+	_, err = ClaimNextReady()
+	require.NoError(t, err)
+	require.NoError(t, RecoverStuck(-time.Second))
+
+	assert.Equal(t, []string{"append", "claim", "recover"}, auditActions(t))
+}
+
+func TestAudit_Evict(t *testing.T) {
+	withTempDataDir(t)
+
+	for range maxEntries {
+		_, err := Append("published", "published", time.Time{})
+		require.NoError(t, err)
+	}
+	entries, err := Load()
+	require.NoError(t, err)
+	oldest := entries[0]
+
+	_, err = Append("one too many", "queued", time.Time{})
+	require.NoError(t, err)
+
+	events, err := ReadAudit()
+	require.NoError(t, err)
+	last := events[len(events)-1]
+	assert.Equal(t, "evict", last.Action)
+	assert.Equal(t, oldest.ID, last.EntryID)
+	assert.Equal(t, "published", last.Before)
+}
+
+func TestReadAudit_SkipsTornLines(t *testing.T) {
+	withTempDataDir(t)
+
+	_, err := Append("ok", "queued", time.Time{})
+	require.NoError(t, err)
+	f, err := os.OpenFile(auditPath(), os.O_APPEND|os.O_WRONLY, 0o600)
+	require.NoError(t, err)
+	_, err = f.WriteString(`{"ts":"2026-`)
+	require.NoError(t, err)
+	require.NoError(t, f.Close())
+
+	events, err := ReadAudit()
+	require.NoError(t, err)
+	assert.Len(t, events, 1)
+}
+
+func TestReadAudit_Missing(t *testing.T) {
+	withTempDataDir(t)
+
+	events, err := ReadAudit()
+	require.NoError(t, err)
+	assert.Empty(t, events)
+}
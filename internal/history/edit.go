@@ -0,0 +1,80 @@
+package history
+
+import (
+	"fmt"
+	"slices"
+	"time"
+)
+
+// Edit applies fn to a queued or draft entry under the history lock and
+// records the previous message, schedule, and tags as a Revision.
+//
+// expectedMessage guards against lost updates when the caller read the entry
+// earlier (e.g. before opening an editor): if it is non-empty and no longer
+// matches, Edit returns ErrConflict without calling fn. Entries currently
+// being published return ErrPublishing.
+func Edit(id, expectedMessage string, fn func(e *Entry) error) (Entry, error) {
+	var result Entry
+	err := withLock(func() error {
+		entries, err := loadFromDisk()
+		if err != nil {
+			return err
+		}
+
+		i := slices.IndexFunc(entries, func(e Entry) bool { return e.ID == id })
+		if i == -1 {
+			return fmt.Errorf("entry %q: %w", id, ErrNotFound)
+		}
+		switch entries[i].Status {
+		case "queued", "draft":
+		case "publishing":
+			return fmt.Errorf("entry %q: %w", id, ErrPublishing)
+		default:
+			return fmt.Errorf("entry %q (%s): %w", id, entries[i].Status, ErrNotQueued)
+		}
+		if expectedMessage != "" && entries[i].Message != expectedMessage {
+			return fmt.Errorf("entry %q: %w", id, ErrConflict)
+		}
+
+		before := entries[i]
+		edited := before
+		edited.Tags = slices.Clone(before.Tags)
+		if err := fn(&edited); err != nil {
+			return err
+		}
+
+		if edited.Message == before.Message &&
+			edited.ScheduledAt == before.ScheduledAt &&
+			slices.Equal(edited.Tags, before.Tags) {
+			result = before
+			return nil
+		}
+
+		now := time.Now().UTC().Format(time.RFC3339)
+		edited.Revisions = append(slices.Clone(before.Revisions), Revision{
+			Message:     before.Message,
+			ScheduledAt: before.ScheduledAt,
+			Tags:        before.Tags,
+			EditedAt:    now,
+		})
+		edited.UpdatedAt = now
+		entries[i] = edited
+		result = edited
+		return save(entries, newEvent("edit", edited, edited.Status, edited.Status))
+	})
+	return result, err
+}
+
+// Get returns the entry with the given ID.
+func Get(id string) (Entry, error) {
+	entries, err := Load()
+	if err != nil {
+		return Entry{}, err
+	}
+	for _, e := range entries {
+		if e.ID == id {
+			return e, nil
+		}
+	}
+	return Entry{}, fmt.Errorf("entry %q: %w", id, ErrNotFound)
+}
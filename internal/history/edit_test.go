@@ -0,0 +1,87 @@
+package history
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEdit_RecordsRevision(t *testing.T) {
+	withTempDataDir(t)
+
+	orig, err := AppendEntry(Entry{Message: "r/go\nold", Status: "queued", Tags: []string{"go"}})
+	require.NoError(t, err)
+
+	edited, err := Edit(orig.ID, orig.Message, func(e *Entry) error {
+		e.Message = "r/go\nnew"
+		e.ScheduledAt = "2026-03-02T10:00:00Z"
+		return nil
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "r/go\nnew", edited.Message)
+	require.Len(t, edited.Revisions, 1)
+	assert.Equal(t, "r/go\nold", edited.Revisions[0].Message)
+	assert.Empty(t, edited.Revisions[0].ScheduledAt)
+	assert.Equal(t, []string{"go"}, edited.Revisions[0].Tags)
+	assert.NotEmpty(t, edited.Revisions[0].EditedAt)
+
+	// Persisted and still in the same queue slot.
+	got, err := Get(orig.ID)
+	require.NoError(t, err)
+	assert.Equal(t, edited, got)
+}
+
+func TestEdit_NoChangeNoRevision(t *testing.T) {
+	withTempDataDir(t)
+
+	orig, err := Append("same", "queued", time.Time{})
+	require.NoError(t, err)
+
+	edited, err := Edit(orig.ID, "", func(*Entry) error { return nil })
+	require.NoError(t, err)
+	assert.Empty(t, edited.Revisions)
+}
+
+func TestEdit_RefusesPublishing(t *testing.T) {
+	withTempDataDir(t)
+
+	_, err := Append("in flight", "queued", time.Time{})
+	require.NoError(t, err)
+	claimed, err := ClaimNextReady()
+	require.NoError(t, err)
+
+	_, err = Edit(claimed.ID, "", func(e *Entry) error {
+		e.Message = "changed"
+		return nil
+	})
+	require.ErrorIs(t, err, ErrPublishing)
+}
+
+func TestEdit_RefusesPublished(t *testing.T) {
+	withTempDataDir(t)
+
+	e, err := Append("done", "published", time.Time{})
+	require.NoError(t, err)
+
+	_, err = Edit(e.ID, "", func(*Entry) error { return nil })
+	require.ErrorIs(t, err, ErrNotQueued)
+}
+
+func TestEdit_Conflict(t *testing.T) {
+	withTempDataDir(t)
+
+	e, err := Append("v1", "queued", time.Time{})
+	require.NoError(t, err)
+
+	_, err = Edit(e.ID, "stale", func(*Entry) error { return nil })
+	require.ErrorIs(t, err, ErrConflict)
+}
+
+func TestEdit_NotFound(t *testing.T) {
+	withTempDataDir(t)
+
+	_, err := Edit("missing", "", func(*Entry) error { return nil })
+	require.ErrorIs(t, err, ErrNotFound)
+}
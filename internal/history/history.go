@@ -2,59 +2,155 @@ package history
 
 import (
 	"crypto/rand"
-	"crypto/sha256"
+	"encoding/binary"
 	"encoding/hex"
-	"encoding/json"
+	"errors"
 	"fmt"
 	"os"
 	"path/filepath"
+	"slices"
+	"strings"
 	"time"
 
-	"github.com/gofrs/flock"
+	"github.com/lvrach/slack-social-ai/internal/paths"
 )
 
 const maxEntries = 200
 
+var (
+	// ErrNotFound is returned when an entry ID does not exist.
+	ErrNotFound = errors.New("entry not found")
+	// ErrNotQueued is returned when an operation requires a "queued" entry.
+	ErrNotQueued = errors.New("entry is not queued")
+	// ErrPublishing is returned when an entry is being published right now.
+	ErrPublishing = errors.New("entry is being published")
+	// ErrConflict is returned when an entry changed since the caller read it.
+	ErrConflict = errors.New("entry was modified concurrently")
+	// ErrNotDraft is returned when approving an entry that is not a draft.
+	ErrNotDraft = errors.New("entry is not a draft")
+	// ErrNotRecurring is returned when a recurrence operation targets a
+	// regular entry.
+	ErrNotRecurring = errors.New("entry is not a recurring post")
+	// ErrStrictSeries is returned when holding a part of a strict series
+	// would stop all publishing.
+	ErrStrictSeries = errors.New("part of a strict series")
+)
+
 // Entry represents a single history record with scheduling and status tracking.
 type Entry struct {
-	ID          string `json:"id"`
-	Message     string `json:"message"`
-	Status      string `json:"status"`                 // "queued" | "publishing" | "published" | "failed"
-	CreatedAt   string `json:"created_at"`             // RFC3339
-	ScheduledAt string `json:"scheduled_at,omitempty"` // RFC3339; empty = ready now
-	PublishedAt string `json:"published_at,omitempty"` // RFC3339; set when published
-	UpdatedAt   string `json:"updated_at,omitempty"`   // RFC3339; tracks last status change
+	ID          string     `json:"id"`
+	Message     string     `json:"message"`
+	Status      string     `json:"status"`                 // "draft" | "queued" | "publishing" | "published" | "expired" | "failed" | "recurring"
+	CreatedAt   string     `json:"created_at"`             // RFC3339
+	ScheduledAt string     `json:"scheduled_at,omitempty"` // RFC3339; empty = ready now
+	PublishedAt string     `json:"published_at,omitempty"` // RFC3339; set when published
+	UpdatedAt   string     `json:"updated_at,omitempty"`   // RFC3339; tracks last status change
+	ExpiresAt   string     `json:"expires_at,omitempty"`   // RFC3339; discarded if still unpublished by then
+	PredictedAt string     `json:"predicted_at,omitempty"` // RFC3339; predicted publish slot when queued
+	Tags        []string   `json:"tags,omitempty"`         // lowercase topic tags, e.g. ["go"] from "r/go"
+	Source      string     `json:"source,omitempty"`       // where the post came from, e.g. "claude session (...)"
+	Agent       string     `json:"agent,omitempty"`        // agent that queued the post, e.g. "claude"
+	SessionID   string     `json:"session_id,omitempty"`   // agent session that queued the post
+	Priority    int        `json:"priority,omitempty"`     // higher publishes first; 0 = normal
+	Held        bool       `json:"held,omitempty"`         // kept in place but skipped by ClaimNextReady
+	JitterSeed  uint32     `json:"jitter_seed,omitempty"`  // random; picks the entry's delay within a jittered slot
+	Revisions   []Revision `json:"revisions,omitempty"`    // previous versions, oldest first
+
+	Recurrence   *Recurrence `json:"recurrence,omitempty"`    // set on "recurring" templates
+	RecurrenceID string      `json:"recurrence_id,omitempty"` // template that spawned this entry
+	Series       *SeriesPart `json:"series,omitempty"`        // part of an ordered series
+}
+
+// Revision is a snapshot of an entry's editable fields before an edit.
+type Revision struct {
+	Message     string   `json:"message"`
+	ScheduledAt string   `json:"scheduled_at,omitempty"`
+	Tags        []string `json:"tags,omitempty"`
+	EditedAt    string   `json:"edited_at"` // RFC3339; when this version was replaced
+}
+
+// HasTag reports whether the entry carries the given tag (case-insensitive).
+func (e Entry) HasTag(tag string) bool {
+	for _, t := range e.Tags {
+		if strings.EqualFold(t, tag) {
+			return true
+		}
+	}
+	return false
+}
+
+// ExpiredAt reports whether the entry's time-to-live has run out at t.
+// Entries without an expiry never expire.
+func (e Entry) ExpiredAt(t time.Time) bool {
+	if e.ExpiresAt == "" {
+		return false
+	}
+	expires, err := time.Parse(time.RFC3339, e.ExpiresAt)
+	return err == nil && !t.Before(expires)
 }
 
-// legacyEntry is the old format used before the migration.
-type legacyEntry struct {
-	Timestamp string `json:"ts"`
-	Message   string `json:"message"`
+// Reschedule sets when e may publish, or clears its scheduled time if at is
+// zero. Expiry counts from the scheduled time, so moving it later moves
+// ExpiresAt later by as much: the entry keeps its time to live instead of
+// expiring before it is due.
+func (e *Entry) Reschedule(at time.Time) {
+	if at.IsZero() {
+		e.ScheduledAt = ""
+		return
+	}
+	if expires, err := time.Parse(time.RFC3339, e.ExpiresAt); err == nil {
+		// Expiry was counted from the scheduled time, or from creation if
+		// that was later.
+		from, _ := time.Parse(time.RFC3339, e.CreatedAt)
+		if scheduled, err := time.Parse(time.RFC3339, e.ScheduledAt); err == nil && scheduled.After(from) {
+			from = scheduled
+		}
+		if !from.IsZero() && at.After(from) {
+			e.ExpiresAt = expires.Add(at.Sub(from)).UTC().Format(time.RFC3339)
+		}
+	}
+	e.ScheduledAt = at.UTC().Format(time.RFC3339)
+}
+
+// FilterByTags returns entries carrying at least one of the given tags.
+// An empty tag list returns entries unchanged.
+func FilterByTags(entries []Entry, tags []string) []Entry {
+	if len(tags) == 0 {
+		return entries
+	}
+	var result []Entry
+	for _, e := range entries {
+		for _, t := range tags {
+			if e.HasTag(t) {
+				result = append(result, e)
+				break
+			}
+		}
+	}
+	return result
 }
 
 // dataDir is a var for test overrides.
 var dataDir = defaultDataDir
 
-func defaultDataDir() string {
-	home, _ := os.UserHomeDir()
-	return filepath.Join(home, ".local", "share", "slack-social-ai")
-}
+func defaultDataDir() string { return paths.DataDir() }
 
 func historyPath() string { return filepath.Join(dataDir(), "history.json") }
-func lockPath() string    { return filepath.Join(dataDir(), "history.lock") }
 
-// withLock acquires an exclusive file lock for the duration of fn.
-func withLock(fn func() error) error {
-	dir := dataDir()
-	if err := os.MkdirAll(dir, 0o700); err != nil {
-		return fmt.Errorf("create data dir: %w", err)
-	}
-	fileLock := flock.New(lockPath())
-	if err := fileLock.Lock(); err != nil {
-		return fmt.Errorf("acquire lock: %w", err)
+// MigratePaths moves files left at pre-XDG locations into place (see
+// paths.Migrate) under the history lock, so no other command reads or
+// writes history while it moves.
+func MigratePaths() ([]string, error) {
+	if !paths.Pending() {
+		return nil, nil
 	}
-	defer func() { _ = fileLock.Unlock() }()
-	return fn()
+	var moved []string
+	err := withLock(func() error {
+		var err error
+		moved, err = paths.Migrate()
+		return err
+	})
+	return moved, err
 }
 
 // generateID returns a random 8 hex-char identifier.
@@ -67,9 +163,18 @@ func generateID() string {
 	return hex.EncodeToString(b)
 }
 
-// Load reads the history file and returns all entries.
-// If old format is detected (entries with empty ID but non-empty message),
-// it runs migration under the file lock and writes back immediately.
+// generateSeed returns a random, non-zero Entry.JitterSeed.
+func generateSeed() uint32 {
+	b := make([]byte, 4)
+	if _, err := rand.Read(b); err != nil {
+		return uint32(time.Now().UnixNano()) | 1
+	}
+	return binary.BigEndian.Uint32(b) | 1
+}
+
+// Load reads the history file and returns all entries. Files in an older
+// format are upgraded through the migration registry and written back under
+// the file lock.
 func Load() ([]Entry, error) {
 	data, err := os.ReadFile(historyPath())
 	if err != nil {
@@ -79,98 +184,27 @@ func Load() ([]Entry, error) {
 		return nil, err
 	}
 
-	var entries []Entry
-	if err := json.Unmarshal(data, &entries); err != nil {
-		return nil, err
+	entries, version, err := decodeHistory(data)
+	if err != nil || version == CurrentVersion {
+		return entries, err
 	}
 
-	// Check if migration is needed: all entries have empty ID but non-empty message.
-	if needsMigration(entries, data) {
-		// Perform migration under the file lock to prevent concurrent writes.
-		var migrated []Entry
-		lockErr := withLock(func() error {
-			// Re-read under lock in case another process migrated first.
-			freshData, err := os.ReadFile(historyPath())
-			if err != nil {
-				return err
-			}
-			var freshEntries []Entry
-			if err := json.Unmarshal(freshData, &freshEntries); err != nil {
-				return err
-			}
-			if !needsMigration(freshEntries, freshData) {
-				// Already migrated by another process.
-				migrated = freshEntries
-				return nil
-			}
-			var migrateErr error
-			migrated, migrateErr = migrateFromLegacy(freshData)
-			if migrateErr != nil {
-				return migrateErr
-			}
-			return atomicWrite(migrated)
-		})
-		if lockErr != nil {
-			return nil, lockErr
-		}
-		return migrated, nil
-	}
-
-	return entries, nil
-}
-
-// needsMigration checks whether the data looks like legacy format.
-func needsMigration(entries []Entry, raw []byte) bool {
-	if len(entries) == 0 {
-		return false
-	}
-	// If all entries have empty ID but non-empty message, it's legacy format.
-	allEmptyID := true
-	for _, e := range entries {
-		if e.ID != "" {
-			allEmptyID = false
-			break
-		}
-	}
-	if !allEmptyID {
-		return false
-	}
-	// Verify it actually has the legacy "ts" field.
-	var legacy []legacyEntry
-	if err := json.Unmarshal(raw, &legacy); err != nil {
-		return false
-	}
-	for _, l := range legacy {
-		if l.Message != "" && l.Timestamp != "" {
-			return true
+	// Persist the upgrade so that every later read and write sees the
+	// current format. Re-read under the lock in case another process
+	// upgraded (or changed) the file first.
+	err = withLock(func() error {
+		if entries, err = loadFromDisk(); err != nil {
+			return err
 		}
-	}
-	return false
-}
-
-// migrateFromLegacy converts legacy entries to the new format.
-func migrateFromLegacy(raw []byte) ([]Entry, error) {
-	var legacy []legacyEntry
-	if err := json.Unmarshal(raw, &legacy); err != nil {
+		return atomicWrite(entries)
+	})
+	if err != nil {
 		return nil, err
 	}
-
-	entries := make([]Entry, 0, len(legacy))
-	for i, l := range legacy {
-		h := sha256.Sum256(fmt.Appendf(nil, "%s:%d:%s", l.Timestamp, i, l.Message))
-		id := hex.EncodeToString(h[:])[:8]
-		entries = append(entries, Entry{
-			ID:          id,
-			Message:     l.Message,
-			Status:      "published",
-			CreatedAt:   l.Timestamp,
-			PublishedAt: l.Timestamp,
-		})
-	}
 	return entries, nil
 }
 
-// loadFromDisk reads and unmarshals the history file without migration.
+// loadFromDisk reads the history file, upgrading older formats in memory.
 func loadFromDisk() ([]Entry, error) {
 	data, err := os.ReadFile(historyPath())
 	if err != nil {
@@ -179,27 +213,28 @@ func loadFromDisk() ([]Entry, error) {
 		}
 		return nil, err
 	}
-	var entries []Entry
-	if err := json.Unmarshal(data, &entries); err != nil {
-		return nil, err
-	}
-	return entries, nil
+	entries, _, err := decodeHistory(data)
+	return entries, err
 }
 
 // Append creates a new Entry and persists it.
 func Append(message, status string, scheduledAt time.Time) (Entry, error) {
-	entry := Entry{
-		ID:        generateID(),
-		Message:   message,
-		Status:    status,
-		CreatedAt: time.Now().UTC().Format(time.RFC3339),
-	}
-	if status == "published" {
-		entry.PublishedAt = entry.CreatedAt
-	}
+	entry := Entry{Message: message, Status: status}
 	if !scheduledAt.IsZero() {
 		entry.ScheduledAt = scheduledAt.UTC().Format(time.RFC3339)
 	}
+	return AppendEntry(entry)
+}
+
+// AppendEntry persists a caller-populated Entry. ID and CreatedAt are
+// assigned here; PublishedAt is set for entries appended as "published".
+func AppendEntry(entry Entry) (Entry, error) {
+	entry.ID = generateID()
+	entry.JitterSeed = generateSeed()
+	entry.CreatedAt = time.Now().UTC().Format(time.RFC3339)
+	if entry.Status == "published" {
+		entry.PublishedAt = entry.CreatedAt
+	}
 
 	var result Entry
 	err := withLock(func() error {
@@ -207,23 +242,72 @@ func Append(message, status string, scheduledAt time.Time) (Entry, error) {
 		if loadErr != nil {
 			return fmt.Errorf("load history: %w", loadErr)
 		}
-		// Migrate legacy entries if needed.
-		raw, _ := os.ReadFile(historyPath())
-		if raw != nil && needsMigration(entries, raw) {
-			migrated, migrateErr := migrateFromLegacy(raw)
-			if migrateErr != nil {
-				return fmt.Errorf("migrate history: %w", migrateErr)
-			}
-			entries = migrated
-		}
-		entries = append(entries, entry)
-		entries = enforceMaxEntries(entries)
+		kept, evicted := trimEntries(append(entries, entry))
 		result = entry
-		return atomicWrite(entries)
+		return save(kept, append([]AuditEvent{newEvent("append", entry, "", entry.Status)}, evicted...)...)
 	})
 	return result, err
 }
 
+// AppendEntries persists several caller-populated entries in one locked
+// write, so either all of them are added or none. IDs and timestamps are
+// assigned as in AppendEntry. Entries with a Series name become the next
+// parts of that series, in batch order.
+//
+// check, if non-nil, is called under the same lock with the current
+// entries before anything is added; an error from it is returned and
+// nothing is added.
+func AppendEntries(batch []Entry, check func(existing []Entry) error) ([]Entry, error) {
+	now := time.Now().UTC().Format(time.RFC3339)
+	added := make([]Entry, len(batch))
+	err := withLock(func() error {
+		entries, loadErr := loadFromDisk()
+		if loadErr != nil {
+			return fmt.Errorf("load history: %w", loadErr)
+		}
+		if check != nil {
+			if err := check(slices.Clone(entries)); err != nil {
+				return err
+			}
+		}
+		events := make([]AuditEvent, 0, len(batch))
+		for i, entry := range batch {
+			entry.ID = generateID()
+			entry.JitterSeed = generateSeed()
+			entry.CreatedAt = now
+			if entry.Status == "published" {
+				entry.PublishedAt = now
+			}
+			if entry.Series != nil {
+				part := nextSeriesPart(entries, entry.Series.Name)
+				entry.Series = &part
+			}
+			entries = append(entries, entry)
+			added[i] = entry
+			events = append(events, newEvent("append", entry, "", entry.Status))
+		}
+		kept, evicted := trimEntries(entries)
+		return save(kept, append(events, evicted...)...)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return added, nil
+}
+
+// trimEntries applies enforceMaxEntries and returns the kept entries with an
+// "evict" audit event for each dropped one.
+func trimEntries(entries []Entry) ([]Entry, []AuditEvent) {
+	kept := enforceMaxEntries(slices.Clone(entries))
+	var events []AuditEvent
+	for _, e := range entries {
+		if !slices.ContainsFunc(kept, func(k Entry) bool { return k.ID == e.ID }) {
+			events = append(events, newEvent("evict", e, e.Status, ""))
+		}
+	}
+	return kept, events
+}
+
 // enforceMaxEntries trims the entries slice to maxEntries.
 // It drops oldest published entries first, then oldest queued.
 func enforceMaxEntries(entries []Entry) []Entry {
@@ -269,10 +353,20 @@ func enforceMaxEntries(entries []Entry) []Entry {
 	return entries
 }
 
-// ClaimNextReady atomically claims the oldest ready-to-publish entry.
-// An entry is ready if status=="queued" and (scheduledAt is empty or <= now).
+// ClaimNextReady atomically claims the first ready-to-publish entry in
+// publish order (highest priority first, then oldest).
+// An entry is ready if status=="queued", it is not held, and (scheduledAt
+// is empty or <= now).
+// Queued entries past their expiry are marked "expired" along the way.
 // Returns nil, nil if nothing is ready.
 func ClaimNextReady() (*Entry, error) {
+	return ClaimNextReadyExcept(nil)
+}
+
+// ClaimNextReadyExcept is ClaimNextReady passing over entries for which
+// skip, if non-nil, returns true, such as entries over a tag cap. Later
+// parts of a skipped entry's series are not ready either.
+func ClaimNextReadyExcept(skip func(Entry) bool) (*Entry, error) {
 	var result *Entry
 	err := withLock(func() error {
 		entries, loadErr := loadFromDisk()
@@ -281,31 +375,87 @@ func ClaimNextReady() (*Entry, error) {
 		}
 
 		now := time.Now().UTC()
-		for i, e := range entries {
-			if e.Status != "queued" {
-				continue
-			}
-			if e.ScheduledAt != "" {
-				scheduled, parseErr := time.Parse(time.RFC3339, e.ScheduledAt)
-				if parseErr != nil {
-					continue
-				}
-				if scheduled.After(now) {
-					continue
-				}
-			}
-			// Found a ready entry.
-			entries[i].Status = "publishing"
+		var events []AuditEvent
+		i := nextReady(entries, now, func(i int) {
+			events = append(events, newEvent("expire", entries[i], entries[i].Status, "expired"))
+			entries[i].Status = "expired"
 			entries[i].UpdatedAt = now.Format(time.RFC3339)
-			claimed := entries[i]
-			result = &claimed
-			return atomicWrite(entries)
+		}, skip)
+		if i == -1 {
+			if len(events) > 0 {
+				return save(entries, events...)
+			}
+			return nil
 		}
-		return nil
+		e := entries[i]
+		entries[i].Status = "publishing"
+		entries[i].UpdatedAt = now.Format(time.RFC3339)
+		claimed := entries[i]
+		result = &claimed
+		return save(entries, append(events, newEvent("claim", e, e.Status, "publishing"))...)
 	})
 	return result, err
 }
 
+// NextReady returns the entry ClaimNextReady would claim now, without
+// claiming it, or nil if none is ready.
+func NextReady() (*Entry, error) {
+	return NextReadyExcept(nil)
+}
+
+// NextReadyExcept returns the entry ClaimNextReadyExcept(skip) would claim
+// now, without claiming it, or nil if none is ready.
+func NextReadyExcept(skip func(Entry) bool) (*Entry, error) {
+	entries, err := Load()
+	if err != nil {
+		return nil, err
+	}
+	i := nextReady(entries, time.Now().UTC(), nil, skip)
+	if i == -1 {
+		return nil, nil
+	}
+	return &entries[i], nil
+}
+
+// nextReady returns the index of the entry to publish next at now, or -1.
+// Expired entries are skipped, and passed to expired if it is non-nil, as
+// are entries for which skip, if non-nil, returns true.
+func nextReady(entries []Entry, now time.Time, expired func(i int), skip func(Entry) bool) int {
+	states := seriesStates(entries)
+	for _, i := range claimOrder(entries, states) {
+		e := entries[i]
+		if e.Status != "queued" {
+			continue
+		}
+		if e.ExpiredAt(now) {
+			if expired != nil {
+				expired(i)
+			}
+			continue
+		}
+		if e.Held {
+			continue
+		}
+		if e.ScheduledAt != "" {
+			scheduled, parseErr := time.Parse(time.RFC3339, e.ScheduledAt)
+			if parseErr != nil {
+				continue
+			}
+			if scheduled.After(now) {
+				continue
+			}
+		}
+		if !seriesReady(entries, i, states, now) {
+			continue
+		}
+		if skip != nil && skip(e) {
+			continue
+		}
+		return i
+	}
+	return -1
+}
+
 // MarkPublished sets an entry's status to "published" with a publishedAt timestamp.
 func MarkPublished(id string) error {
 	return withLock(func() error {
@@ -319,7 +469,7 @@ func MarkPublished(id string) error {
 				entries[i].Status = "published"
 				entries[i].PublishedAt = now
 				entries[i].UpdatedAt = now
-				return atomicWrite(entries)
+				return save(entries, newEvent("publish", e, e.Status, "published"))
 			}
 		}
 		return fmt.Errorf("entry %q not found", id)
@@ -338,14 +488,14 @@ func ResetToQueued(id string) error {
 			if e.ID == id {
 				entries[i].Status = "queued"
 				entries[i].UpdatedAt = now
-				return atomicWrite(entries)
+				return save(entries, newEvent("reset", e, e.Status, "queued"))
 			}
 		}
 		return fmt.Errorf("entry %q not found", id)
 	})
 }
 
-// Remove deletes an entry by ID. Returns (found, error).
+// Remove deletes an entry by ID, keeping an undo snapshot. Returns (found, error).
 func Remove(id string) (bool, error) {
 	found := false
 	err := withLock(func() error {
@@ -355,9 +505,12 @@ func Remove(id string) (bool, error) {
 		}
 		for i, e := range entries {
 			if e.ID == id {
+				if err := recordSnapshot("remove", entries, func(x Entry) bool { return x.ID == id }); err != nil {
+					return err
+				}
 				entries = append(entries[:i], entries[i+1:]...)
 				found = true
-				return atomicWrite(entries)
+				return save(entries, newEvent("remove", e, e.Status, ""))
 			}
 		}
 		return nil
@@ -365,45 +518,106 @@ func Remove(id string) (bool, error) {
 	return found, err
 }
 
-// ClearPublished removes all entries with status "published".
+// ClearPublished removes all entries with status "published", keeping an undo snapshot.
 func ClearPublished() error {
 	return withLock(func() error {
 		entries, err := loadFromDisk()
 		if err != nil {
 			return err
 		}
+		if err := recordSnapshot("clear", entries, func(e Entry) bool { return e.Status == "published" }); err != nil {
+			return err
+		}
 		filtered := make([]Entry, 0, len(entries))
+		var events []AuditEvent
 		for _, e := range entries {
 			if e.Status != "published" {
 				filtered = append(filtered, e)
+				continue
 			}
+			events = append(events, newEvent("clear", e, e.Status, ""))
 		}
-		return atomicWrite(filtered)
+		return save(filtered, events...)
 	})
 }
 
-// ClearAll removes all entries.
+// ClearAll removes all entries, keeping an undo snapshot.
 func ClearAll() error {
 	return withLock(func() error {
-		return atomicWrite([]Entry{})
+		entries, err := loadFromDisk()
+		if err != nil {
+			return err
+		}
+		if err := recordSnapshot("clear-all", entries, func(Entry) bool { return true }); err != nil {
+			return err
+		}
+		events := make([]AuditEvent, 0, len(entries))
+		for _, e := range entries {
+			events = append(events, newEvent("clear", e, e.Status, ""))
+		}
+		return save([]Entry{}, events...)
 	})
 }
 
-// Queued returns entries with status "queued" or "publishing".
+// Queued returns entries with status "queued" or "publishing" in publish order.
 func Queued() ([]Entry, error) {
+	entries, err := Load()
+	if err != nil {
+		return nil, err
+	}
+	var result []Entry
+	for _, i := range queueIndices(entries) {
+		result = append(result, entries[i])
+	}
+	return result, nil
+}
+
+// Drafts returns entries with status "draft", oldest first.
+func Drafts() ([]Entry, error) {
 	entries, err := Load()
 	if err != nil {
 		return nil, err
 	}
 	var result []Entry
 	for _, e := range entries {
-		if e.Status == "queued" || e.Status == "publishing" {
+		if e.Status == "draft" {
 			result = append(result, e)
 		}
 	}
 	return result, nil
 }
 
+// Approve moves drafts into the queue so ClaimNextReady can pick them up.
+// The drafts are approved in one locked write: if any ID is missing or not
+// a draft, none of them is approved.
+func Approve(ids ...string) error {
+	return withLock(func() error {
+		entries, err := loadFromDisk()
+		if err != nil {
+			return err
+		}
+		now := time.Now().UTC().Format(time.RFC3339)
+		events := make([]AuditEvent, 0, len(ids))
+		for _, id := range ids {
+			i := slices.IndexFunc(entries, func(e Entry) bool { return e.ID == id })
+			if i == -1 {
+				return fmt.Errorf("entry %q: %w", id, ErrNotFound)
+			}
+			e := entries[i]
+			if e.Status != "draft" {
+				return fmt.Errorf("entry %q (%s): %w", id, e.Status, ErrNotDraft)
+			}
+			entries[i].Status = "queued"
+			entries[i].UpdatedAt = now
+			events = append(events, newEvent("approve", e, e.Status, "queued"))
+		}
+		if len(events) == 0 {
+			return nil
+		}
+		return save(entries, events...)
+	})
+}
+
 // Published returns entries with status "published".
 func Published() ([]Entry, error) {
 	entries, err := Load()
@@ -419,6 +633,40 @@ func Published() ([]Entry, error) {
 	return result, nil
 }
 
+// PublishedRecord returns every post known to have published: the
+// published entries in history, plus an entry rebuilt from the audit log
+// for each published post since removed from history (by `history --clear`,
+// --remove, or eviction). Rebuilt entries carry only their ID, tags, status
+// and publish time. Caps count these, so deleting history does not reset
+// them.
+func PublishedRecord() ([]Entry, error) {
+	entries, err := Load()
+	if err != nil {
+		return nil, err
+	}
+	events, err := ReadAudit()
+	if err != nil {
+		return nil, err
+	}
+
+	known := make(map[string]bool, len(entries))
+	var result []Entry
+	for _, e := range entries {
+		known[e.ID] = true
+		if e.Status == "published" {
+			result = append(result, e)
+		}
+	}
+	for _, ev := range events {
+		if !ev.published() || known[ev.EntryID] {
+			continue
+		}
+		known[ev.EntryID] = true
+		result = append(result, Entry{ID: ev.EntryID, Tags: ev.Tags, Status: "published", PublishedAt: ev.Time})
+	}
+	return result, nil
+}
+
 // LastPublishedTime returns the most recent publishedAt timestamp among published entries.
 // Returns zero time if no entries are published.
 func LastPublishedTime() (time.Time, error) {
@@ -451,7 +699,7 @@ func RecoverStuck(timeout time.Duration) error {
 			return err
 		}
 		now := time.Now().UTC()
-		changed := false
+		var events []AuditEvent
 		for i, e := range entries {
 			if e.Status != "publishing" {
 				continue
@@ -466,11 +714,11 @@ func RecoverStuck(timeout time.Duration) error {
 			if now.Sub(updated) > timeout {
 				entries[i].Status = "queued"
 				entries[i].UpdatedAt = now.Format(time.RFC3339)
-				changed = true
+				events = append(events, newEvent("recover", e, e.Status, "queued"))
 			}
 		}
-		if changed {
-			return atomicWrite(entries)
+		if len(events) > 0 {
+			return save(entries, events...)
 		}
 		return nil
 	})
@@ -481,13 +729,19 @@ func atomicWrite(entries []Entry) error {
 	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
 		return err
 	}
-	data, err := json.MarshalIndent(entries, "", "  ")
+	data, err := encodeHistory(entries)
 	if err != nil {
 		return err
 	}
 	tmp := path + ".tmp"
-	if err := os.WriteFile(tmp, data, 0o600); err != nil {
+	if err := writeFileSync(tmp, data); err != nil {
+		return err
+	}
+	if err := os.Rename(tmp, path); err != nil {
 		return err
 	}
-	return os.Rename(tmp, path)
+	syncDir(filepath.Dir(path))
+	// Backups are a safety net; a failed backup must not fail the write.
+	_ = writeBackup(data)
+	return nil
 }
@@ -2,6 +2,7 @@ package history
 
 import (
 	"encoding/json"
+	"errors"
 	"os"
 	"path/filepath"
 	"testing"
@@ -480,3 +481,264 @@ func writeEntries(t *testing.T, entries []Entry) {
 		t.Fatal(err)
 	}
 }
+
+func TestAppendEntry_Tags(t *testing.T) {
+	withTempDataDir(t)
+
+	entry, err := AppendEntry(Entry{Message: "r/go\nbody", Status: "queued", Tags: []string{"go", "til"}})
+	require.NoError(t, err)
+	assert.NotEmpty(t, entry.ID)
+	assert.NotEmpty(t, entry.CreatedAt)
+
+	entries, err := Load()
+	require.NoError(t, err)
+	require.Len(t, entries, 1)
+	assert.Equal(t, []string{"go", "til"}, entries[0].Tags)
+}
+
+func TestFilterByTags(t *testing.T) {
+	entries := []Entry{
+		{ID: "a", Tags: []string{"go"}},
+		{ID: "b", Tags: []string{"ai", "python"}},
+		{ID: "c"},
+	}
+
+	assert.Len(t, FilterByTags(entries, nil), 3)
+
+	got := FilterByTags(entries, []string{"python", "go"})
+	require.Len(t, got, 2)
+	assert.Equal(t, "a", got[0].ID)
+	assert.Equal(t, "b", got[1].ID)
+
+	assert.True(t, entries[1].HasTag("AI"))
+	assert.False(t, entries[2].HasTag("go"))
+}
+
+func TestClaimNextReady_SkipsDrafts(t *testing.T) {
+	withTempDataDir(t)
+
+	draft, err := Append("needs review", "draft", time.Time{})
+	require.NoError(t, err)
+
+	claimed, err := ClaimNextReady()
+	require.NoError(t, err)
+	assert.Nil(t, claimed, "drafts must not be claimed")
+
+	require.NoError(t, Approve(draft.ID))
+
+	claimed, err = ClaimNextReady()
+	require.NoError(t, err)
+	require.NotNil(t, claimed)
+	assert.Equal(t, draft.ID, claimed.ID)
+}
+
+func TestApprove_Errors(t *testing.T) {
+	withTempDataDir(t)
+
+	queued, err := Append("already queued", "queued", time.Time{})
+	require.NoError(t, err)
+
+	require.ErrorIs(t, Approve(queued.ID), ErrNotDraft)
+	require.ErrorIs(t, Approve("missing"), ErrNotFound)
+}
+
+func TestApprove_BatchIsAllOrNothing(t *testing.T) {
+	withTempDataDir(t)
+
+	d1, err := Append("one", "draft", time.Time{})
+	require.NoError(t, err)
+	d2, err := Append("two", "draft", time.Time{})
+	require.NoError(t, err)
+
+	require.ErrorIs(t, Approve(d1.ID, "missing", d2.ID), ErrNotFound)
+	drafts, err := Drafts()
+	require.NoError(t, err)
+	assert.Len(t, drafts, 2, "a failed batch must not approve any draft")
+
+	require.NoError(t, Approve(d1.ID, d2.ID))
+	drafts, err = Drafts()
+	require.NoError(t, err)
+	assert.Empty(t, drafts)
+}
+
+func TestDrafts(t *testing.T) {
+	withTempDataDir(t)
+
+	_, err := Append("queued", "queued", time.Time{})
+	require.NoError(t, err)
+	d, err := Append("draft", "draft", time.Time{})
+	require.NoError(t, err)
+
+	drafts, err := Drafts()
+	require.NoError(t, err)
+	require.Len(t, drafts, 1)
+	assert.Equal(t, d.ID, drafts[0].ID)
+
+	queued, err := Queued()
+	require.NoError(t, err)
+	assert.Len(t, queued, 1, "drafts are not part of the queue")
+}
+
+func TestClaimNextReady_MarksExpired(t *testing.T) {
+	withTempDataDir(t)
+
+	past := time.Now().Add(-time.Hour).UTC().Format(time.RFC3339)
+	future := time.Now().Add(time.Hour).UTC().Format(time.RFC3339)
+	stale, err := AppendEntry(Entry{Message: "stale", Status: "queued", ExpiresAt: past})
+	require.NoError(t, err)
+	fresh, err := AppendEntry(Entry{Message: "fresh", Status: "queued", ExpiresAt: future})
+	require.NoError(t, err)
+
+	claimed, err := ClaimNextReady()
+	require.NoError(t, err)
+	require.NotNil(t, claimed)
+	assert.Equal(t, fresh.ID, claimed.ID)
+
+	got, err := Get(stale.ID)
+	require.NoError(t, err)
+	assert.Equal(t, "expired", got.Status)
+	assert.NotEmpty(t, got.UpdatedAt)
+}
+
+func TestClaimNextReady_OnlyExpired(t *testing.T) {
+	withTempDataDir(t)
+
+	past := time.Now().Add(-time.Minute).UTC().Format(time.RFC3339)
+	stale, err := AppendEntry(Entry{Message: "stale", Status: "queued", ExpiresAt: past})
+	require.NoError(t, err)
+
+	claimed, err := ClaimNextReady()
+	require.NoError(t, err)
+	assert.Nil(t, claimed)
+
+	got, err := Get(stale.ID)
+	require.NoError(t, err)
+	assert.Equal(t, "expired", got.Status, "expiry must be persisted even when nothing is claimed")
+}
+
+func TestEntry_ExpiredAt(t *testing.T) {
+	now := time.Date(2026, 3, 2, 12, 0, 0, 0, time.UTC)
+	e := Entry{ExpiresAt: now.Format(time.RFC3339)}
+
+	assert.False(t, e.ExpiredAt(now.Add(-time.Second)))
+	assert.True(t, e.ExpiredAt(now))
+	assert.False(t, Entry{}.ExpiredAt(now), "entries without expiry never expire")
+}
+
+func TestAppendEntries(t *testing.T) {
+	withTempDataDir(t)
+	_, err := AppendSeriesPart(Entry{Message: "existing part", Status: "queued"}, "dive", func(s *SeriesPart) { s.Gap = "10m0s" })
+	require.NoError(t, err)
+
+	added, err := AppendEntries([]Entry{
+		{Message: "one", Status: "queued"},
+		{Message: "two", Status: "draft", Series: &SeriesPart{Name: "dive"}},
+		{Message: "three", Status: "queued", Series: &SeriesPart{Name: "dive"}},
+	}, nil)
+	require.NoError(t, err)
+	require.Len(t, added, 3)
+	for _, e := range added {
+		assert.NotEmpty(t, e.ID)
+		assert.NotEmpty(t, e.CreatedAt)
+	}
+	assert.Equal(t, SeriesPart{Name: "dive", Part: 2, Gap: "10m0s"}, *added[1].Series)
+	assert.Equal(t, 3, added[2].Series.Part)
+
+	entries, err := Load()
+	require.NoError(t, err)
+	assert.Len(t, entries, 4)
+
+	events, err := ReadAudit()
+	require.NoError(t, err)
+	assert.Len(t, events, 4, "one append event per entry")
+
+	// A failed check adds nothing.
+	errRejected := errors.New("rejected")
+	_, err = AppendEntries([]Entry{{Message: "four", Status: "queued"}}, func(existing []Entry) error {
+		assert.Len(t, existing, 4)
+		return errRejected
+	})
+	require.ErrorIs(t, err, errRejected)
+	entries, err = Load()
+	require.NoError(t, err)
+	assert.Len(t, entries, 4)
+}
+
+func TestAppend_AssignsJitterSeed(t *testing.T) {
+	withTempDataDir(t)
+
+	a, err := Append("first", "queued", time.Time{})
+	require.NoError(t, err)
+	b, err := Append("second", "queued", time.Time{})
+	require.NoError(t, err)
+	assert.NotZero(t, a.JitterSeed)
+	assert.NotEqual(t, a.JitterSeed, b.JitterSeed)
+}
+
+func TestNextReady(t *testing.T) {
+	withTempDataDir(t)
+	writeEntries(t, []Entry{
+		{ID: "old", Message: "stale", Status: "queued", ExpiresAt: "2020-01-01T00:00:00Z"},
+		{ID: "a", Message: "first", Status: "queued"},
+	})
+
+	next, err := NextReady()
+	require.NoError(t, err)
+	require.NotNil(t, next)
+	assert.Equal(t, "a", next.ID)
+
+	// Peeking changes nothing; claiming takes the same entry.
+	entries, err := Load()
+	require.NoError(t, err)
+	assert.Equal(t, "queued", entries[0].Status)
+	claimed, err := ClaimNextReady()
+	require.NoError(t, err)
+	assert.Equal(t, next.ID, claimed.ID)
+
+	next, err = NextReady()
+	require.NoError(t, err)
+	assert.Nil(t, next)
+}
+
+func TestNextReadyExcept(t *testing.T) {
+	withTempDataDir(t)
+	writeEntries(t, []Entry{
+		{ID: "a", Message: "promo", Status: "queued", Tags: []string{"promo"}},
+		{ID: "b", Message: "plain", Status: "queued"},
+	})
+	skip := func(e Entry) bool { return e.HasTag("promo") }
+
+	next, err := NextReadyExcept(skip)
+	require.NoError(t, err)
+	require.NotNil(t, next)
+	assert.Equal(t, "b", next.ID)
+
+	claimed, err := ClaimNextReadyExcept(skip)
+	require.NoError(t, err)
+	require.NotNil(t, claimed)
+	assert.Equal(t, "b", claimed.ID)
+
+	claimed, err = ClaimNextReadyExcept(skip)
+	require.NoError(t, err)
+	assert.Nil(t, claimed, "only skipped entries left")
+}
+
+func TestPublishedRecord(t *testing.T) {
+	withTempDataDir(t)
+	gone, err := AppendEntry(Entry{Message: "gone", Status: "queued", Tags: []string{"promo"}})
+	require.NoError(t, err)
+	claimed, err := ClaimNextReady()
+	require.NoError(t, err)
+	require.NoError(t, MarkPublished(claimed.ID))
+	require.NoError(t, ClearPublished())
+	kept, err := AppendEntry(Entry{Message: "kept", Status: "published"})
+	require.NoError(t, err)
+
+	record, err := PublishedRecord()
+	require.NoError(t, err)
+	require.Len(t, record, 2)
+	assert.Equal(t, gone.ID, record[1].ID, "cleared entries still count")
+	assert.Equal(t, []string{"promo"}, record[1].Tags)
+	assert.NotEmpty(t, record[1].PublishedAt)
+	assert.Equal(t, kept.ID, record[0].ID)
+}
@@ -0,0 +1,84 @@
+package history
+
+import (
+	"fmt"
+	"slices"
+	"time"
+)
+
+// Hold keeps a queued entry in its place in the queue but stops
+// ClaimNextReady from publishing it until Release. Holding a held entry is
+// a no-op.
+//
+// Nothing publishes between the parts of a strict series, so a held part
+// would stop all publishing once the series reaches it. Only the first part
+// of a strict series that has not started can be held; other parts return
+// ErrStrictSeries.
+func Hold(id string) (Entry, error) {
+	return updateQueued(id, "hold", false, func(entries []Entry, i int) error {
+		e := &entries[i]
+		if e.Series != nil && e.Series.Strict && !e.Held {
+			st := seriesStates(entries)[e.Series.Name]
+			if st.next != i || !st.lastPublished.IsZero() {
+				return fmt.Errorf("entry %q: %w %q", id, ErrStrictSeries, e.Series.Name)
+			}
+		}
+		e.Held = true
+		return nil
+	})
+}
+
+// Release lets a held entry publish again. Releasing an entry that is not
+// held is a no-op.
+func Release(id string) (Entry, error) {
+	return updateQueued(id, "release", false, func(entries []Entry, i int) error {
+		entries[i].Held = false
+		return nil
+	})
+}
+
+// Snooze reschedules a queued or draft entry to until, moving its expiry
+// along (see Entry.Reschedule). It does not release a held entry.
+func Snooze(id string, until time.Time) (Entry, error) {
+	return updateQueued(id, "snooze", true, func(entries []Entry, i int) error {
+		entries[i].Reschedule(until)
+		return nil
+	})
+}
+
+// updateQueued applies fn to a queued entry, entries[i] (or a draft, with
+// drafts set), under the history lock, recording an audit event if
+// anything changed. An error from fn leaves history unchanged.
+func updateQueued(id, action string, drafts bool, fn func(entries []Entry, i int) error) (Entry, error) {
+	var result Entry
+	err := withLock(func() error {
+		entries, err := loadFromDisk()
+		if err != nil {
+			return err
+		}
+		i := slices.IndexFunc(entries, func(e Entry) bool { return e.ID == id })
+		if i == -1 {
+			return fmt.Errorf("entry %q: %w", id, ErrNotFound)
+		}
+		switch status := entries[i].Status; {
+		case status == "queued", status == "draft" && drafts:
+		case status == "publishing":
+			return fmt.Errorf("entry %q: %w", id, ErrPublishing)
+		default:
+			return fmt.Errorf("entry %q (%s): %w", id, status, ErrNotQueued)
+		}
+
+		before := entries[i]
+		if err := fn(entries, i); err != nil {
+			return err
+		}
+		result = entries[i]
+		if result.Held == before.Held && result.ScheduledAt == before.ScheduledAt {
+			return nil
+		}
+		entries[i].UpdatedAt = time.Now().UTC().Format(time.RFC3339)
+		result = entries[i]
+		return save(entries, newEvent(action, result, result.Status, result.Status))
+	})
+	return result, err
+}
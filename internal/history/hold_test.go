@@ -0,0 +1,131 @@
+package history
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHoldAndRelease(t *testing.T) {
+	withTempDataDir(t)
+	writeEntries(t, []Entry{
+		{ID: "a", Message: "first", Status: "queued", Priority: 5},
+		{ID: "b", Message: "second", Status: "queued"},
+	})
+
+	held, err := Hold("a")
+	require.NoError(t, err)
+	assert.True(t, held.Held)
+
+	claimed, err := ClaimNextReady()
+	require.NoError(t, err)
+	require.NotNil(t, claimed)
+	assert.Equal(t, "b", claimed.ID, "held entries are skipped")
+
+	queued, err := Queued()
+	require.NoError(t, err)
+	require.Len(t, queued, 2)
+	assert.Equal(t, "a", SortQueue(queued)[0].ID, "held entries keep their position")
+
+	released, err := Release("a")
+	require.NoError(t, err)
+	assert.False(t, released.Held)
+	require.NoError(t, MarkPublished("b"))
+	claimed, err = ClaimNextReady()
+	require.NoError(t, err)
+	require.NotNil(t, claimed)
+	assert.Equal(t, "a", claimed.ID)
+
+	events, err := ReadAudit()
+	require.NoError(t, err)
+	var actions []string
+	for _, ev := range events {
+		actions = append(actions, ev.Action)
+	}
+	assert.Subset(t, actions, []string{"hold", "release"})
+}
+
+func TestHold_Errors(t *testing.T) {
+	withTempDataDir(t)
+	writeEntries(t, []Entry{
+		{ID: "p", Message: "out", Status: "published"},
+		{ID: "d", Message: "draft", Status: "draft"},
+	})
+
+	_, err := Hold("missing")
+	require.ErrorIs(t, err, ErrNotFound)
+	_, err = Hold("p")
+	require.ErrorIs(t, err, ErrNotQueued)
+	_, err = Hold("d")
+	require.ErrorIs(t, err, ErrNotQueued)
+}
+
+func TestSnooze_MovesExpiry(t *testing.T) {
+	withTempDataDir(t)
+	writeEntries(t, []Entry{{
+		ID: "a", Message: "one day to live", Status: "queued",
+		CreatedAt: "2026-03-02T09:00:00Z", ExpiresAt: "2026-03-03T09:00:00Z",
+	}})
+
+	// Snoozed three days past its expiry: it keeps its day to live.
+	e, err := Snooze("a", time.Date(2026, 3, 5, 9, 0, 0, 0, time.UTC))
+	require.NoError(t, err)
+	assert.Equal(t, "2026-03-06T09:00:00Z", e.ExpiresAt)
+
+	// Snoozed again, counting from the new scheduled time.
+	e, err = Snooze("a", time.Date(2026, 3, 5, 15, 0, 0, 0, time.UTC))
+	require.NoError(t, err)
+	assert.Equal(t, "2026-03-06T15:00:00Z", e.ExpiresAt)
+}
+
+func TestSnooze(t *testing.T) {
+	withTempDataDir(t)
+	writeEntries(t, []Entry{
+		{ID: "a", Message: "first", Status: "queued", Held: true},
+		{ID: "d", Message: "draft", Status: "draft"},
+	})
+
+	until := time.Now().Add(2 * time.Hour)
+	e, err := Snooze("a", until)
+	require.NoError(t, err)
+	assert.Equal(t, until.UTC().Format(time.RFC3339), e.ScheduledAt)
+	assert.True(t, e.Held, "snoozing does not release")
+
+	_, err = Snooze("d", until)
+	require.NoError(t, err, "drafts can be snoozed")
+
+	claimed, err := ClaimNextReady()
+	require.NoError(t, err)
+	assert.Nil(t, claimed)
+}
+
+func TestHold_StrictSeries(t *testing.T) {
+	withTempDataDir(t)
+	strict := func(part int) *SeriesPart { return &SeriesPart{Name: "launch", Part: part, Strict: true} }
+	writeEntries(t, []Entry{
+		{ID: "s1", Message: "one", Status: "queued", Series: strict(1)},
+		{ID: "s2", Message: "two", Status: "queued", Series: strict(2)},
+		{ID: "x", Message: "other", Status: "queued"},
+	})
+
+	_, err := Hold("s2")
+	require.ErrorIs(t, err, ErrStrictSeries, "a later part would stop publishing once the series starts")
+	_, err = Hold("s1")
+	require.NoError(t, err, "the first part may wait before the series starts")
+
+	// Once the series has started, its next part cannot be held.
+	_, err = Release("s1")
+	require.NoError(t, err)
+	claimed, err := ClaimNextReady()
+	require.NoError(t, err)
+	require.Equal(t, "s1", claimed.ID)
+	require.NoError(t, MarkPublished("s1"))
+	_, err = Hold("s2")
+	require.ErrorIs(t, err, ErrStrictSeries)
+
+	got, err := Get("s2")
+	require.NoError(t, err)
+	assert.False(t, got.Held)
+}
@@ -0,0 +1,205 @@
+package history
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"syscall"
+	"time"
+
+	"github.com/gofrs/flock"
+)
+
+// DefaultLockTimeout is how long a command waits for another process to
+// release the history lock before giving up.
+const DefaultLockTimeout = 10 * time.Second
+
+// lockRetryDelay is how often a waiting command retries the lock.
+const lockRetryDelay = 50 * time.Millisecond
+
+// lockTimeout is set once by the CLI via SetLockTimeout.
+var lockTimeout = DefaultLockTimeout
+
+// SetLockTimeout sets how long history operations wait for the lock.
+// Non-positive values restore the default.
+func SetLockTimeout(d time.Duration) {
+	if d <= 0 {
+		d = DefaultLockTimeout
+	}
+	lockTimeout = d
+}
+
+// ErrLockTimeout is returned when the history lock could not be acquired in
+// time. The returned error is a *LockTimeoutError naming the holder.
+var ErrLockTimeout = errors.New("timed out waiting for history lock")
+
+// LockHolder identifies the process holding the history lock, as recorded in
+// the sidecar file next to the lock.
+type LockHolder struct {
+	PID      int    `json:"pid"`
+	Command  string `json:"command,omitempty"`
+	Host     string `json:"host,omitempty"`
+	Acquired string `json:"acquired_at"`
+}
+
+// LockTimeoutError reports a lock acquisition that timed out. Holder is nil
+// when the holder did not record itself (e.g. an older binary).
+type LockTimeoutError struct {
+	Holder *LockHolder
+	Waited time.Duration
+}
+
+func (e *LockTimeoutError) Error() string {
+	if e.Holder == nil {
+		return fmt.Sprintf("%s after %s", ErrLockTimeout, e.Waited)
+	}
+	return fmt.Sprintf("%s after %s: held by PID %d (%s) since %s",
+		ErrLockTimeout, e.Waited, e.Holder.PID, e.Holder.Command, e.Holder.Acquired)
+}
+
+func (e *LockTimeoutError) Unwrap() error { return ErrLockTimeout }
+
+func lockPath() string       { return filepath.Join(dataDir(), "history.lock") }
+func lockHolderPath() string { return filepath.Join(dataDir(), "history.lock.json") }
+
+// withLock acquires an exclusive file lock for the duration of fn, waiting at
+// most lockTimeout for other processes to release it.
+func withLock(fn func() error) error {
+	dir := dataDir()
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return fmt.Errorf("create data dir: %w", err)
+	}
+	fileLock := flock.New(lockPath())
+	ctx, cancel := context.WithTimeout(context.Background(), lockTimeout)
+	defer cancel()
+	locked, err := fileLock.TryLockContext(ctx, lockRetryDelay)
+	if !locked {
+		if err == nil || errors.Is(err, context.DeadlineExceeded) {
+			holder, _ := readLockHolder()
+			return &LockTimeoutError{Holder: holder, Waited: lockTimeout}
+		}
+		return fmt.Errorf("acquire lock: %w", err)
+	}
+	defer func() { _ = fileLock.Unlock() }()
+
+	// The holder record is diagnostics only; failing to write it must not
+	// block the operation.
+	_ = writeLockHolder()
+	defer func() { _ = os.Remove(lockHolderPath()) }()
+	return fn()
+}
+
+func writeLockHolder() error {
+	host, _ := os.Hostname()
+	command := "slack-social-ai"
+	if auditCommand != "" {
+		command += " " + auditCommand
+	}
+	data, err := json.Marshal(LockHolder{
+		PID:      os.Getpid(),
+		Command:  command,
+		Host:     host,
+		Acquired: time.Now().UTC().Format(time.RFC3339),
+	})
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(lockHolderPath(), data, 0o600)
+}
+
+// readLockHolder returns the recorded lock holder, or nil if none is recorded.
+func readLockHolder() (*LockHolder, error) {
+	data, err := os.ReadFile(lockHolderPath())
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	var holder LockHolder
+	if err := json.Unmarshal(data, &holder); err != nil {
+		return nil, fmt.Errorf("parse lock holder: %w", err)
+	}
+	return &holder, nil
+}
+
+// LockState describes the history lock for diagnostics.
+type LockState struct {
+	// Held is true if another process holds the lock right now.
+	Held bool
+	// Holder is the recorded holder, if any.
+	Holder *LockHolder
+	// Stale is true if the holder record is left over from a process that is
+	// gone: either the lock is free, or it is held but the recorded process
+	// no longer runs on this host.
+	Stale bool
+}
+
+// InspectLock reports whether the history lock is held and by whom, without
+// waiting for it.
+func InspectLock() (LockState, error) {
+	return inspectLock(false)
+}
+
+// ClearStaleLock removes the leftover holder record of a stale lock. It
+// never removes the lock file: a lock that is held is held by a live
+// process, such as a child that inherited the recorded holder's file
+// descriptor, and unlinking it would let a second writer in alongside that
+// process. It does nothing unless the lock is stale, and returns the state
+// observed before clearing.
+func ClearStaleLock() (LockState, error) {
+	return inspectLock(true)
+}
+
+func inspectLock(clear bool) (LockState, error) {
+	var state LockState
+	if err := os.MkdirAll(dataDir(), 0o700); err != nil {
+		return state, fmt.Errorf("create data dir: %w", err)
+	}
+	fileLock := flock.New(lockPath())
+	locked, err := fileLock.TryLock()
+	if err != nil {
+		return state, fmt.Errorf("probe lock: %w", err)
+	}
+	if locked {
+		// Nobody holds the lock, so any holder record is left over.
+		defer func() { _ = fileLock.Unlock() }()
+	}
+
+	holder, err := readLockHolder()
+	if err != nil {
+		return state, err
+	}
+	state.Holder = holder
+	state.Held = !locked
+	state.Stale = holder != nil && (locked || holderGone(*holder))
+	if !clear || !state.Stale {
+		return state, nil
+	}
+
+	if err := os.Remove(lockHolderPath()); err != nil && !os.IsNotExist(err) {
+		return state, fmt.Errorf("remove lock holder: %w", err)
+	}
+	return state, nil
+}
+
+// holderGone reports whether the recorded holder process no longer exists.
+// Holders on other hosts (e.g. a shared network home) cannot be checked and
+// are assumed alive.
+func holderGone(holder LockHolder) bool {
+	if host, _ := os.Hostname(); holder.Host != "" && holder.Host != host {
+		return false
+	}
+	if holder.PID <= 0 {
+		return true
+	}
+	proc, err := os.FindProcess(holder.PID)
+	if err != nil {
+		return true
+	}
+	err = proc.Signal(syscall.Signal(0))
+	return err != nil && !errors.Is(err, syscall.EPERM)
+}
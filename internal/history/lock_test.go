@@ -1,10 +1,14 @@
 package history
 
 import (
+	"encoding/json"
+	"os"
+	"os/exec"
 	"sync"
 	"testing"
 	"time"
 
+	"github.com/gofrs/flock"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
@@ -122,3 +126,141 @@ func TestConcurrentClaimAndAppend(t *testing.T) {
 	assert.LessOrEqual(t, totalClaimed, numEntries+appenders,
 		"claimed more entries than exist: %d > %d", totalClaimed, numEntries+appenders)
 }
+
+// holdLock takes the history lock from a separate handle, as another process
+// would, and records holder as its owner.
+func holdLock(t *testing.T, holder *LockHolder) {
+	t.Helper()
+	fileLock := flock.New(lockPath())
+	locked, err := fileLock.TryLock()
+	require.NoError(t, err)
+	require.True(t, locked)
+	t.Cleanup(func() { _ = fileLock.Unlock() })
+	if holder != nil {
+		writeHolderFile(t, *holder)
+	}
+}
+
+func writeHolderFile(t *testing.T, holder LockHolder) {
+	t.Helper()
+	data, err := json.Marshal(holder)
+	require.NoError(t, err)
+	require.NoError(t, os.WriteFile(lockHolderPath(), data, 0o600))
+}
+
+func withLockTimeout(t *testing.T, d time.Duration) {
+	t.Helper()
+	SetLockTimeout(d)
+	t.Cleanup(func() { SetLockTimeout(0) })
+}
+
+// exitedPID returns the PID of a process that has already exited.
+func exitedPID(t *testing.T) int {
+	t.Helper()
+	cmd := exec.Command("true")
+	require.NoError(t, cmd.Run())
+	return cmd.Process.Pid
+}
+
+func TestWithLock_Timeout(t *testing.T) {
+	withTempDataDir(t)
+	withLockTimeout(t, 100*time.Millisecond)
+	holdLock(t, &LockHolder{PID: 4242, Command: "slack-social-ai queue inspect", Acquired: "2026-03-01T10:00:00Z"})
+
+	_, err := Append("msg", "queued", time.Time{})
+	require.ErrorIs(t, err, ErrLockTimeout)
+
+	var lockErr *LockTimeoutError
+	require.ErrorAs(t, err, &lockErr)
+	require.NotNil(t, lockErr.Holder)
+	assert.Equal(t, 4242, lockErr.Holder.PID)
+	assert.Equal(t, 100*time.Millisecond, lockErr.Waited)
+	assert.Contains(t, err.Error(), "queue inspect")
+}
+
+func TestWithLock_TimeoutUnknownHolder(t *testing.T) {
+	withTempDataDir(t)
+	withLockTimeout(t, 50*time.Millisecond)
+	holdLock(t, nil)
+
+	_, err := Remove("deadbeef")
+	var lockErr *LockTimeoutError
+	require.ErrorAs(t, err, &lockErr)
+	assert.Nil(t, lockErr.Holder)
+}
+
+func TestWithLock_RecordsHolder(t *testing.T) {
+	withTempDataDir(t)
+	SetAuditContext("tester", "queue edit")
+	t.Cleanup(func() { SetAuditContext("", "") })
+
+	var during *LockHolder
+	require.NoError(t, withLock(func() error {
+		var err error
+		during, err = readLockHolder()
+		return err
+	}))
+
+	require.NotNil(t, during)
+	assert.Equal(t, os.Getpid(), during.PID)
+	assert.Equal(t, "slack-social-ai queue edit", during.Command)
+	assert.NotEmpty(t, during.Acquired)
+
+	after, err := readLockHolder()
+	require.NoError(t, err)
+	assert.Nil(t, after, "holder record should be removed on release")
+}
+
+func TestInspectLock_Free(t *testing.T) {
+	withTempDataDir(t)
+
+	state, err := InspectLock()
+	require.NoError(t, err)
+	assert.Equal(t, LockState{}, state)
+}
+
+func TestInspectLock_HeldByLiveProcess(t *testing.T) {
+	withTempDataDir(t)
+	host, _ := os.Hostname()
+	holdLock(t, &LockHolder{PID: os.Getpid(), Host: host, Command: "slack-social-ai publish"})
+
+	state, err := ClearStaleLock()
+	require.NoError(t, err)
+	assert.True(t, state.Held)
+	assert.False(t, state.Stale)
+	assert.FileExists(t, lockHolderPath(), "live holder must not be cleared")
+}
+
+func TestClearStaleLock_LeftoverRecord(t *testing.T) {
+	withTempDataDir(t)
+	require.NoError(t, os.WriteFile(lockPath(), nil, 0o600))
+	writeHolderFile(t, LockHolder{PID: exitedPID(t), Command: "slack-social-ai post"})
+
+	state, err := ClearStaleLock()
+	require.NoError(t, err)
+	assert.False(t, state.Held)
+	assert.True(t, state.Stale)
+	assert.NoFileExists(t, lockHolderPath())
+
+	state, err = InspectLock()
+	require.NoError(t, err)
+	assert.False(t, state.Stale)
+}
+
+func TestClearStaleLock_HeldByExitedProcess(t *testing.T) {
+	withTempDataDir(t)
+	withLockTimeout(t, 100*time.Millisecond)
+	host, _ := os.Hostname()
+	holdLock(t, &LockHolder{PID: exitedPID(t), Host: host, Command: "slack-social-ai queue inspect"})
+
+	state, err := ClearStaleLock()
+	require.NoError(t, err)
+	assert.True(t, state.Held)
+	assert.True(t, state.Stale)
+	assert.NoFileExists(t, lockHolderPath())
+	assert.FileExists(t, lockPath(), "a held lock must not be unlinked")
+
+	// Whoever still holds the lock keeps it.
+	_, err = Append("after clear", "queued", time.Time{})
+	require.ErrorIs(t, err, ErrLockTimeout)
+}
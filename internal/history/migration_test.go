@@ -5,6 +5,7 @@ import (
 	"os"
 	"path/filepath"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -90,9 +91,13 @@ func TestMigration_WritesBack(t *testing.T) {
 	data, err := os.ReadFile(historyPath())
 	require.NoError(t, err)
 
-	var entries []Entry
-	err = json.Unmarshal(data, &entries)
-	require.NoError(t, err)
+	var file struct {
+		Version int     `json:"version"`
+		Entries []Entry `json:"entries"`
+	}
+	require.NoError(t, json.Unmarshal(data, &file))
+	assert.Equal(t, CurrentVersion, file.Version)
+	entries := file.Entries
 	require.Len(t, entries, 1)
 
 	assert.NotEmpty(t, entries[0].ID)
@@ -100,6 +105,148 @@ func TestMigration_WritesBack(t *testing.T) {
 	assert.Equal(t, "migrated post", entries[0].Message)
 }
 
+func TestMigration_UpgradesBareArrayToEnvelope(t *testing.T) {
+	withTempDataDir(t)
+	writeEntries(t, []Entry{{ID: "abcdef01", Message: "m", Status: "queued", CreatedAt: "2025-06-01T10:00:00Z"}})
+
+	entries, err := Load()
+	require.NoError(t, err)
+	require.Len(t, entries, 1)
+
+	data, err := os.ReadFile(historyPath())
+	require.NoError(t, err)
+	_, version, err := decodeHistory(data)
+	require.NoError(t, err)
+	assert.Equal(t, CurrentVersion, version)
+}
+
+func TestMigrations_Registry(t *testing.T) {
+	require.Len(t, migrations, CurrentVersion, "one migration per version step")
+	for i, m := range migrations {
+		assert.Equal(t, i, m.From, "migrations must be ordered and contiguous")
+		assert.NotEmpty(t, m.Name)
+	}
+}
+
+func TestMigration_V0ToV1(t *testing.T) {
+	raw := json.RawMessage(`[{"ts": "2025-01-01T10:00:00Z", "message": "old"}]`)
+
+	out, err := migrations[0].Apply(raw)
+	require.NoError(t, err)
+
+	var entries []map[string]string
+	require.NoError(t, json.Unmarshal(out, &entries))
+	require.Len(t, entries, 1)
+	assert.Len(t, entries[0]["id"], 8)
+	assert.Equal(t, "old", entries[0]["message"])
+	assert.Equal(t, "published", entries[0]["status"])
+	assert.Equal(t, "2025-01-01T10:00:00Z", entries[0]["created_at"])
+	assert.Equal(t, "2025-01-01T10:00:00Z", entries[0]["published_at"])
+
+	// IDs are stable across repeated migrations of the same file.
+	again, err := migrations[0].Apply(raw)
+	require.NoError(t, err)
+	assert.JSONEq(t, string(out), string(again))
+}
+
+func TestMigration_V1ToV2(t *testing.T) {
+	raw := json.RawMessage(`[{"id": "abcdef01", "message": "m", "status": "queued", "created_at": "2025-06-01T10:00:00Z"}]`)
+
+	out, err := migrations[1].Apply(raw)
+	require.NoError(t, err)
+	assert.JSONEq(t, string(raw), string(out))
+}
+
+func TestMigration_FieldAdditions(t *testing.T) {
+	raw := json.RawMessage(`[{"id": "abcdef01", "message": "m", "status": "published"}]`)
+
+	// v2→v3 (recurring), v3→v4 (series), and v4→v5 (held) only add fields.
+	for _, m := range migrations[2:5] {
+		out, err := m.Apply(raw)
+		require.NoError(t, err, m.Name)
+		assert.JSONEq(t, string(raw), string(out), m.Name)
+	}
+}
+
+func TestDecodeHistory_Versions(t *testing.T) {
+	tests := []struct {
+		name    string
+		data    string
+		version int
+		ids     []string
+	}{
+		{"v0 bare legacy array", `[{"ts": "2025-01-01T10:00:00Z", "message": "old"}]`, 0, nil},
+		{"v1 bare array", `[{"id": "abcdef01", "message": "m", "status": "queued"}]`, 1, []string{"abcdef01"}},
+		{"v1 empty array", `[]`, 1, []string{}},
+		{"v2 envelope", `{"version": 2, "entries": [{"id": "abcdef02", "message": "m", "status": "queued"}]}`, 2, []string{"abcdef02"}},
+		{"v2 envelope without entries", `{"version": 2}`, 2, []string{}},
+		{"v3 envelope", `{"version": 3, "entries": [{"id": "abcdef03", "message": "m", "status": "recurring", "recurrence": {"every": "mon 10:00"}}]}`, 3, []string{"abcdef03"}},
+		{"v4 envelope", `{"version": 4, "entries": [{"id": "abcdef04", "message": "m", "status": "queued", "series": {"name": "dive", "part": 1}}]}`, 4, []string{"abcdef04"}},
+		{"v5 envelope", `{"version": 5, "entries": [{"id": "abcdef05", "message": "m", "status": "queued", "held": true}]}`, 5, []string{"abcdef05"}},
+		{"v6 envelope", `{"version": 6, "entries": [{"id": "abcdef06", "message": "m", "status": "queued", "jitter_seed": 7}]}`, 6, []string{"abcdef06"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			entries, version, err := decodeHistory([]byte(tt.data))
+			require.NoError(t, err)
+			assert.Equal(t, tt.version, version)
+			if tt.ids != nil {
+				ids := []string{}
+				for _, e := range entries {
+					ids = append(ids, e.ID)
+				}
+				assert.Equal(t, tt.ids, ids)
+			}
+		})
+	}
+}
+
+func TestDecodeHistory_Invalid(t *testing.T) {
+	for _, data := range []string{`{"entries": []}`, `{"version": 0, "entries": []}`, `"text"`, ``} {
+		_, _, err := decodeHistory([]byte(data))
+		assert.ErrorIs(t, err, ErrCorrupt, "data %q", data)
+	}
+}
+
+func TestEncodeHistory_RoundTrip(t *testing.T) {
+	data, err := encodeHistory(nil)
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"version": 6, "entries": []}`, string(data))
+
+	in := []Entry{{ID: "abcdef01", Message: "m", Status: "queued", CreatedAt: "2025-06-01T10:00:00Z"}}
+	data, err = encodeHistory(in)
+	require.NoError(t, err)
+	out, version, err := decodeHistory(data)
+	require.NoError(t, err)
+	assert.Equal(t, CurrentVersion, version)
+	assert.Equal(t, in, out)
+}
+
+func TestMigration_RefusesDowngrade(t *testing.T) {
+	withTempDataDir(t)
+	path := historyPath()
+	require.NoError(t, os.MkdirAll(filepath.Dir(path), 0o700))
+	future := []byte(`{"version": 99, "entries": [{"id": "abcdef01", "message": "m", "status": "queued", "new_field": 1}]}`)
+	require.NoError(t, os.WriteFile(path, future, 0o600))
+
+	_, err := Load()
+	require.ErrorIs(t, err, ErrNewerVersion)
+	var versionErr *VersionError
+	require.ErrorAs(t, err, &versionErr)
+	assert.Equal(t, 99, versionErr.Found)
+	assert.Equal(t, CurrentVersion, versionErr.Supported)
+	assert.NotErrorIs(t, err, ErrCorrupt)
+
+	_, err = Append("new", "queued", time.Time{})
+	require.ErrorIs(t, err, ErrNewerVersion)
+	_, err = Repair()
+	require.ErrorIs(t, err, ErrNewerVersion)
+
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.Equal(t, future, data, "a newer file must never be rewritten")
+}
+
 func writeLegacy(t *testing.T, entries []legacyEntry) {
 	t.Helper()
 	path := historyPath()
@@ -114,3 +261,19 @@ func writeLegacy(t *testing.T, entries []legacyEntry) {
 		t.Fatal(err)
 	}
 }
+
+func TestMigration_JitterSeeds(t *testing.T) {
+	data := []byte(`{"version": 5, "entries": [
+		{"id": "abcdef01", "message": "m", "status": "queued"},
+		{"id": "abcdef02", "message": "m", "status": "queued", "jitter_seed": 7}
+	]}`)
+
+	first, _, err := decodeHistory(data)
+	require.NoError(t, err)
+	second, _, err := decodeHistory(data)
+	require.NoError(t, err)
+
+	assert.NotZero(t, first[0].JitterSeed)
+	assert.Equal(t, first[0].JitterSeed, second[0].JitterSeed, "seeds are derived, not random")
+	assert.Equal(t, uint32(7), first[1].JitterSeed, "existing seeds are kept")
+}
@@ -0,0 +1,121 @@
+package history
+
+import (
+	"fmt"
+	"slices"
+	"time"
+)
+
+// isPending reports whether an entry is still waiting in the queue.
+func isPending(e Entry) bool {
+	return e.Status == "queued" || e.Status == "publishing"
+}
+
+// queueIndices returns the indices of pending entries in publish order:
+// higher priority first, then file order.
+func queueIndices(entries []Entry) []int {
+	var idx []int
+	for i, e := range entries {
+		if isPending(e) {
+			idx = append(idx, i)
+		}
+	}
+	slices.SortStableFunc(idx, func(a, b int) int {
+		return entries[b].Priority - entries[a].Priority
+	})
+	return idx
+}
+
+// SortQueue returns a copy of entries in publish order: higher priority
+// first, ties broken by their original order.
+func SortQueue(entries []Entry) []Entry {
+	sorted := slices.Clone(entries)
+	slices.SortStableFunc(sorted, func(a, b Entry) int {
+		return b.Priority - a.Priority
+	})
+	return sorted
+}
+
+// MoveTo moves a queued entry to a 0-based position in the publish order.
+// Positions past either end are clamped. Like MoveBefore and MoveAfter, it
+// gives the entry its new neighbour's priority; callers report the change.
+func MoveTo(id string, pos int) error {
+	return move(id, func(order []Entry) (int, error) {
+		return min(max(pos, 0), len(order)), nil
+	})
+}
+
+// MoveBefore moves a queued entry directly in front of another pending entry.
+func MoveBefore(id, beforeID string) error {
+	return move(id, func(order []Entry) (int, error) {
+		for i, e := range order {
+			if e.ID == beforeID {
+				return i, nil
+			}
+		}
+		return 0, fmt.Errorf("entry %q: %w", beforeID, ErrNotFound)
+	})
+}
+
+// MoveAfter moves a queued entry directly behind another pending entry.
+func MoveAfter(id, afterID string) error {
+	return move(id, func(order []Entry) (int, error) {
+		for i, e := range order {
+			if e.ID == afterID {
+				return i + 1, nil
+			}
+		}
+		return 0, fmt.Errorf("entry %q: %w", afterID, ErrNotFound)
+	})
+}
+
+// move removes id from the publish order and reinserts it at the position
+// chosen by target (an index into the order without id). The moved entry
+// adopts its new neighbour's priority and is placed next to it in the file,
+// so the stable priority sort reproduces the requested order.
+func move(id string, target func(order []Entry) (int, error)) error {
+	return withLock(func() error {
+		entries, err := loadFromDisk()
+		if err != nil {
+			return err
+		}
+
+		src := slices.IndexFunc(entries, func(e Entry) bool { return e.ID == id })
+		if src == -1 {
+			return fmt.Errorf("entry %q: %w", id, ErrNotFound)
+		}
+		if entries[src].Status != "queued" {
+			return fmt.Errorf("entry %q (%s): %w", id, entries[src].Status, ErrNotQueued)
+		}
+
+		moved := entries[src]
+		entries = slices.Delete(entries, src, src+1)
+
+		order := make([]Entry, 0, len(entries))
+		for _, i := range queueIndices(entries) {
+			order = append(order, entries[i])
+		}
+		pos, err := target(order)
+		if err != nil {
+			return err
+		}
+
+		// Anchor on the entry before the new slot, or the one after it when
+		// moving to the top. An empty queue keeps the original slot.
+		insertAt := src
+		switch {
+		case pos > 0:
+			anchor := order[pos-1]
+			moved.Priority = anchor.Priority
+			insertAt = slices.IndexFunc(entries, func(e Entry) bool { return e.ID == anchor.ID }) + 1
+		case len(order) > 0:
+			anchor := order[0]
+			moved.Priority = anchor.Priority
+			insertAt = slices.IndexFunc(entries, func(e Entry) bool { return e.ID == anchor.ID })
+		}
+
+		moved.UpdatedAt = time.Now().UTC().Format(time.RFC3339)
+		entries = slices.Insert(entries, insertAt, moved)
+		return save(entries, newEvent("move", moved, moved.Status, moved.Status))
+	})
+}
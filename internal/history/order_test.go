@@ -0,0 +1,141 @@
+package history
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// queuedIDs returns the IDs of pending entries in publish order.
+func queuedIDs(t *testing.T) []string {
+	t.Helper()
+	queued, err := Queued()
+	require.NoError(t, err)
+	ids := make([]string, len(queued))
+	for i, e := range queued {
+		ids[i] = e.ID
+	}
+	return ids
+}
+
+// appendQueued appends n queued entries and returns their IDs.
+func appendQueued(t *testing.T, n int) []string {
+	t.Helper()
+	ids := make([]string, n)
+	for i := range n {
+		e, err := Append("msg", "queued", time.Time{})
+		require.NoError(t, err)
+		ids[i] = e.ID
+	}
+	return ids
+}
+
+func TestClaimNextReady_Priority(t *testing.T) {
+	withTempDataDir(t)
+
+	_, err := Append("filler", "queued", time.Time{})
+	require.NoError(t, err)
+	urgent, err := AppendEntry(Entry{Message: "urgent", Status: "queued", Priority: 5})
+	require.NoError(t, err)
+
+	claimed, err := ClaimNextReady()
+	require.NoError(t, err)
+	require.NotNil(t, claimed)
+	assert.Equal(t, urgent.ID, claimed.ID)
+}
+
+func TestQueued_PublishOrder(t *testing.T) {
+	withTempDataDir(t)
+
+	low, err := AppendEntry(Entry{Message: "low", Status: "queued", Priority: -1})
+	require.NoError(t, err)
+	normal, err := Append("normal", "queued", time.Time{})
+	require.NoError(t, err)
+	high, err := AppendEntry(Entry{Message: "high", Status: "queued", Priority: 2})
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{high.ID, normal.ID, low.ID}, queuedIDs(t))
+}
+
+func TestMoveTo(t *testing.T) {
+	withTempDataDir(t)
+	ids := appendQueued(t, 4)
+
+	require.NoError(t, MoveTo(ids[3], 0))
+	assert.Equal(t, []string{ids[3], ids[0], ids[1], ids[2]}, queuedIDs(t))
+
+	require.NoError(t, MoveTo(ids[3], 99))
+	assert.Equal(t, []string{ids[0], ids[1], ids[2], ids[3]}, queuedIDs(t))
+
+	require.NoError(t, MoveTo(ids[0], 2))
+	assert.Equal(t, []string{ids[1], ids[2], ids[0], ids[3]}, queuedIDs(t))
+}
+
+func TestMoveTo_AcrossPriorities(t *testing.T) {
+	withTempDataDir(t)
+
+	high, err := AppendEntry(Entry{Message: "high", Status: "queued", Priority: 3})
+	require.NoError(t, err)
+	normal, err := Append("normal", "queued", time.Time{})
+	require.NoError(t, err)
+
+	// Moving the normal entry to the top adopts the high priority.
+	require.NoError(t, MoveTo(normal.ID, 0))
+	assert.Equal(t, []string{normal.ID, high.ID}, queuedIDs(t))
+
+	claimed, err := ClaimNextReady()
+	require.NoError(t, err)
+	require.NotNil(t, claimed)
+	assert.Equal(t, normal.ID, claimed.ID)
+	assert.Equal(t, 3, claimed.Priority)
+}
+
+func TestMoveBefore(t *testing.T) {
+	withTempDataDir(t)
+	ids := appendQueued(t, 3)
+
+	require.NoError(t, MoveBefore(ids[2], ids[1]))
+	assert.Equal(t, []string{ids[0], ids[2], ids[1]}, queuedIDs(t))
+}
+
+func TestMoveAfter(t *testing.T) {
+	withTempDataDir(t)
+	ids := appendQueued(t, 3)
+
+	require.NoError(t, MoveAfter(ids[0], ids[1]))
+	assert.Equal(t, []string{ids[1], ids[0], ids[2]}, queuedIDs(t))
+	require.NoError(t, MoveAfter(ids[1], ids[2]))
+	assert.Equal(t, []string{ids[0], ids[2], ids[1]}, queuedIDs(t))
+	require.ErrorIs(t, MoveAfter(ids[0], "missing"), ErrNotFound)
+}
+
+func TestMove_Errors(t *testing.T) {
+	withTempDataDir(t)
+	ids := appendQueued(t, 2)
+
+	require.ErrorIs(t, MoveTo("missing", 0), ErrNotFound)
+	require.ErrorIs(t, MoveBefore(ids[0], "missing"), ErrNotFound)
+
+	claimed, err := ClaimNextReady()
+	require.NoError(t, err)
+	require.ErrorIs(t, MoveTo(claimed.ID, 1), ErrNotQueued)
+}
+
+func TestMove_KeepsPublishedEntries(t *testing.T) {
+	withTempDataDir(t)
+
+	_, err := Append("done", "published", time.Time{})
+	require.NoError(t, err)
+	ids := appendQueued(t, 2)
+
+	require.NoError(t, MoveTo(ids[1], 0))
+
+	entries, err := Load()
+	require.NoError(t, err)
+	assert.Len(t, entries, 3)
+	published, err := Published()
+	require.NoError(t, err)
+	assert.Len(t, published, 1)
+}
@@ -0,0 +1,196 @@
+package history
+
+import (
+	"fmt"
+	"hash/fnv"
+	"slices"
+	"time"
+
+	"github.com/lvrach/slack-social-ai/internal/recur"
+)
+
+// Recurrence is the schedule of a "recurring" template entry. The template's
+// message may use recur.Variables; each spawned instance is rendered for its
+// occurrence.
+type Recurrence struct {
+	Every       string `json:"every"`                  // recur.Parse spec, e.g. "mon 10:00" or "0 10 * * 1"
+	NextAt      string `json:"next_at,omitempty"`      // RFC3339; next occurrence, empty = never again
+	Paused      bool   `json:"paused,omitempty"`       // no instances spawn while paused
+	Count       int    `json:"count,omitempty"`        // instances spawned so far
+	ExpireAfter string `json:"expire_after,omitempty"` // Go duration; instance TTL, empty = never
+	Draft       bool   `json:"draft,omitempty"`        // spawn drafts that need approval
+}
+
+// Recurrences returns the recurring templates, oldest first.
+func Recurrences() ([]Entry, error) {
+	entries, err := Load()
+	if err != nil {
+		return nil, err
+	}
+	var result []Entry
+	for _, e := range entries {
+		if e.Status == "recurring" {
+			result = append(result, e)
+		}
+	}
+	return result, nil
+}
+
+// PauseRecurrence stops a template from spawning instances until resumed.
+func PauseRecurrence(id string) (Entry, error) {
+	return updateRecurrence(id, "pause", func(r *Recurrence) error {
+		r.Paused = true
+		return nil
+	})
+}
+
+// ResumeRecurrence restarts a paused template. Occurrences missed while it
+// was paused are skipped: the next one is the first after now.
+func ResumeRecurrence(id string, now time.Time) (Entry, error) {
+	return updateRecurrence(id, "resume", func(r *Recurrence) error {
+		rule, err := recur.Parse(r.Every)
+		if err != nil {
+			return err
+		}
+		r.Paused = false
+		r.NextAt = formatOccurrence(rule.Next(now))
+		return nil
+	})
+}
+
+func updateRecurrence(id, action string, fn func(r *Recurrence) error) (Entry, error) {
+	var result Entry
+	err := withLock(func() error {
+		entries, err := loadFromDisk()
+		if err != nil {
+			return err
+		}
+		i := slices.IndexFunc(entries, func(e Entry) bool { return e.ID == id })
+		if i == -1 {
+			return fmt.Errorf("entry %q: %w", id, ErrNotFound)
+		}
+		if entries[i].Status != "recurring" || entries[i].Recurrence == nil {
+			return fmt.Errorf("entry %q (%s): %w", id, entries[i].Status, ErrNotRecurring)
+		}
+		rec := *entries[i].Recurrence
+		if err := fn(&rec); err != nil {
+			return err
+		}
+		entries[i].Recurrence = &rec
+		entries[i].UpdatedAt = time.Now().UTC().Format(time.RFC3339)
+		result = entries[i]
+		return save(entries, newEvent(action, result, result.Status, result.Status))
+	})
+	return result, err
+}
+
+// SpawnDue creates an entry for every unpaused template whose next occurrence
+// is at or before now, and advances the template to its first occurrence
+// after now. A template that missed several occurrences (e.g. the machine
+// was asleep) spawns one instance, for the latest of them. It returns the
+// spawned entries.
+func SpawnDue(now time.Time) ([]Entry, error) {
+	var spawned []Entry
+	err := withLock(func() error {
+		entries, err := loadFromDisk()
+		if err != nil {
+			return err
+		}
+		var events []AuditEvent
+		for i, tmpl := range entries {
+			occurrence, rule, ok := dueOccurrence(tmpl, now)
+			if !ok {
+				continue
+			}
+			instance := spawnInstance(tmpl, occurrence, now)
+			rec := *tmpl.Recurrence
+			rec.Count++
+			rec.NextAt = formatOccurrence(rule.Next(now))
+			entries[i].Recurrence = &rec
+			entries[i].UpdatedAt = now.UTC().Format(time.RFC3339)
+
+			spawned = append(spawned, instance)
+			events = append(events, newEvent("spawn", instance, "", instance.Status))
+		}
+		if len(spawned) == 0 {
+			return nil
+		}
+		entries, evicted := trimEntries(append(entries, spawned...))
+		return save(entries, append(events, evicted...)...)
+	})
+	return spawned, err
+}
+
+// dueOccurrence returns the latest occurrence of a template at or before
+// now, if one is due.
+func dueOccurrence(tmpl Entry, now time.Time) (time.Time, recur.Rule, bool) {
+	rec := tmpl.Recurrence
+	if tmpl.Status != "recurring" || rec == nil || rec.Paused || rec.NextAt == "" {
+		return time.Time{}, recur.Rule{}, false
+	}
+	next, err := time.Parse(time.RFC3339, rec.NextAt)
+	if err != nil || next.After(now) {
+		return time.Time{}, recur.Rule{}, false
+	}
+	rule, err := recur.Parse(rec.Every)
+	if err != nil {
+		return time.Time{}, recur.Rule{}, false
+	}
+	occurrence := next.In(now.Location())
+	for {
+		later := rule.Next(occurrence)
+		if later.IsZero() || later.After(now) {
+			return occurrence, rule, true
+		}
+		occurrence = later
+	}
+}
+
+// InstanceSeed returns the JitterSeed of the template's next instance.
+// It is derived rather than random so predictions of the instance agree
+// with the entry spawned.
+func InstanceSeed(tmpl Entry) uint32 {
+	count := 0
+	if tmpl.Recurrence != nil {
+		count = tmpl.Recurrence.Count
+	}
+	h := fnv.New32a()
+	fmt.Fprintf(h, "%s:%d:%d", tmpl.ID, tmpl.JitterSeed, count+1)
+	return h.Sum32() | 1
+}
+
+// spawnInstance builds the entry a template spawns for an occurrence.
+func spawnInstance(tmpl Entry, occurrence, now time.Time) Entry {
+	rec := tmpl.Recurrence
+	status := "queued"
+	if rec.Draft {
+		status = "draft"
+	}
+	instance := Entry{
+		ID:           generateID(),
+		Message:      recur.Render(tmpl.Message, occurrence, rec.Count+1),
+		Status:       status,
+		CreatedAt:    now.UTC().Format(time.RFC3339),
+		ScheduledAt:  occurrence.UTC().Format(time.RFC3339),
+		Tags:         slices.Clone(tmpl.Tags),
+		Source:       tmpl.Source,
+		Agent:        tmpl.Agent,
+		SessionID:    tmpl.SessionID,
+		Priority:     tmpl.Priority,
+		RecurrenceID: tmpl.ID,
+		JitterSeed:   InstanceSeed(tmpl),
+	}
+	if ttl, err := time.ParseDuration(rec.ExpireAfter); err == nil && ttl > 0 {
+		instance.ExpiresAt = now.Add(ttl).UTC().Format(time.RFC3339)
+	}
+	return instance
+}
+
+// formatOccurrence formats t for Recurrence.NextAt; the zero time (a rule
+// that never occurs again) becomes "".
+func formatOccurrence(t time.Time) string {
+	if t.IsZero() {
+		return ""
+	}
+	return t.UTC().Format(time.RFC3339)
+}
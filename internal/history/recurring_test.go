@@ -0,0 +1,134 @@
+package history
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func appendTemplate(t *testing.T, message, every, nextAt string) Entry {
+	t.Helper()
+	tmpl, err := AppendEntry(Entry{
+		Message:    message,
+		Status:     "recurring",
+		Tags:       []string{"retro"},
+		Priority:   2,
+		Agent:      "claude",
+		Recurrence: &Recurrence{Every: every, NextAt: nextAt, ExpireAfter: "24h"},
+	})
+	require.NoError(t, err)
+	return tmpl
+}
+
+func TestSpawnDue_SpawnsRenderedInstance(t *testing.T) {
+	withTempDataDir(t)
+	tmpl := appendTemplate(t, "Week {{week}} ({{date}}): what did we learn? #{{n}}", "mon 10:00", "2026-03-02T10:00:00Z")
+
+	now := time.Date(2026, 3, 2, 10, 5, 0, 0, time.UTC)
+	spawned, err := SpawnDue(now)
+	require.NoError(t, err)
+	require.Len(t, spawned, 1)
+
+	got := spawned[0]
+	assert.Equal(t, "Week 10 (2026-03-02): what did we learn? #1", got.Message)
+	assert.Equal(t, "queued", got.Status)
+	assert.Equal(t, "2026-03-02T10:00:00Z", got.ScheduledAt)
+	assert.Equal(t, "2026-03-03T10:05:00Z", got.ExpiresAt)
+	assert.Equal(t, tmpl.ID, got.RecurrenceID)
+	assert.Equal(t, []string{"retro"}, got.Tags)
+	assert.Equal(t, 2, got.Priority)
+	assert.Equal(t, "claude", got.Agent)
+	assert.Equal(t, InstanceSeed(tmpl), got.JitterSeed, "predictable jitter seed")
+
+	queued, err := Queued()
+	require.NoError(t, err)
+	require.Len(t, queued, 1)
+	assert.Equal(t, got.ID, queued[0].ID)
+
+	updated, err := Get(tmpl.ID)
+	require.NoError(t, err)
+	assert.Equal(t, "recurring", updated.Status)
+	assert.Equal(t, 1, updated.Recurrence.Count)
+	assert.Equal(t, "2026-03-09T10:00:00Z", updated.Recurrence.NextAt)
+
+	// Not due again until next week.
+	spawned, err = SpawnDue(now.Add(time.Hour))
+	require.NoError(t, err)
+	assert.Empty(t, spawned)
+}
+
+func TestSpawnDue_SkipsMissedOccurrences(t *testing.T) {
+	withTempDataDir(t)
+	tmpl := appendTemplate(t, "{{date}}", "mon 10:00", "2026-03-02T10:00:00Z")
+
+	// Three Mondays passed while the machine was off.
+	now := time.Date(2026, 3, 18, 9, 0, 0, 0, time.UTC)
+	spawned, err := SpawnDue(now)
+	require.NoError(t, err)
+	require.Len(t, spawned, 1)
+	assert.Equal(t, "2026-03-16", spawned[0].Message)
+
+	updated, err := Get(tmpl.ID)
+	require.NoError(t, err)
+	assert.Equal(t, "2026-03-23T10:00:00Z", updated.Recurrence.NextAt)
+}
+
+func TestSpawnDue_DraftTemplate(t *testing.T) {
+	withTempDataDir(t)
+	_, err := AppendEntry(Entry{
+		Message:    "fun friday",
+		Status:     "recurring",
+		Recurrence: &Recurrence{Every: "fri 16:00", NextAt: "2026-03-06T16:00:00Z", Draft: true},
+	})
+	require.NoError(t, err)
+
+	spawned, err := SpawnDue(time.Date(2026, 3, 6, 16, 0, 0, 0, time.UTC))
+	require.NoError(t, err)
+	require.Len(t, spawned, 1)
+	assert.Equal(t, "draft", spawned[0].Status)
+	assert.Empty(t, spawned[0].ExpiresAt)
+}
+
+func TestPauseResumeRecurrence(t *testing.T) {
+	withTempDataDir(t)
+	tmpl := appendTemplate(t, "m", "mon 10:00", "2026-03-02T10:00:00Z")
+
+	paused, err := PauseRecurrence(tmpl.ID)
+	require.NoError(t, err)
+	assert.True(t, paused.Recurrence.Paused)
+
+	spawned, err := SpawnDue(time.Date(2026, 3, 2, 11, 0, 0, 0, time.UTC))
+	require.NoError(t, err)
+	assert.Empty(t, spawned, "paused templates do not spawn")
+
+	resumed, err := ResumeRecurrence(tmpl.ID, time.Date(2026, 3, 4, 0, 0, 0, 0, time.UTC))
+	require.NoError(t, err)
+	assert.False(t, resumed.Recurrence.Paused)
+	assert.Equal(t, "2026-03-09T10:00:00Z", resumed.Recurrence.NextAt, "missed occurrences are skipped")
+}
+
+func TestPauseRecurrence_Errors(t *testing.T) {
+	withTempDataDir(t)
+	plain, err := Append("plain", "queued", time.Time{})
+	require.NoError(t, err)
+
+	_, err = PauseRecurrence(plain.ID)
+	require.ErrorIs(t, err, ErrNotRecurring)
+
+	_, err = PauseRecurrence("missing")
+	require.ErrorIs(t, err, ErrNotFound)
+}
+
+func TestRecurrences(t *testing.T) {
+	withTempDataDir(t)
+	tmpl := appendTemplate(t, "m", "daily 09:00", "2026-03-02T09:00:00Z")
+	_, err := Append("plain", "queued", time.Time{})
+	require.NoError(t, err)
+
+	got, err := Recurrences()
+	require.NoError(t, err)
+	require.Len(t, got, 1)
+	assert.Equal(t, tmpl.ID, got[0].ID)
+}
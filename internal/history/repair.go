@@ -0,0 +1,407 @@
+package history
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strings"
+	"time"
+)
+
+// ErrCorrupt is returned when history.json cannot be parsed. Repair recovers
+// what it can.
+var ErrCorrupt = errors.New("history file is corrupt")
+
+// CorruptError reports an unparseable history file.
+type CorruptError struct {
+	Path string
+	Err  error
+}
+
+func (e *CorruptError) Error() string {
+	return fmt.Sprintf("%s (%s): %v", ErrCorrupt, e.Path, e.Err)
+}
+
+func (e *CorruptError) Is(target error) bool { return target == ErrCorrupt }
+func (e *CorruptError) Unwrap() error        { return e.Err }
+
+// maxBackups is how many rolling backups of history.json are kept.
+const maxBackups = 10
+
+// backupTimeFormat sorts lexically in time order.
+const backupTimeFormat = "20060102T150405.000000000Z"
+
+func backupDir() string { return filepath.Join(dataDir(), "backups") }
+
+// Backup is one rolling copy of history.json. Its file name carries the time
+// it was taken and a checksum of its contents.
+type Backup struct {
+	Name    string    `json:"name"`
+	Time    time.Time `json:"time"`
+	Valid   bool      `json:"valid"` // checksum matches and contents parse
+	Entries int       `json:"entries"`
+}
+
+func checksum(data []byte) string {
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:8])
+}
+
+// writeBackup stores data as the newest backup and prunes old ones. It skips
+// the write when the newest backup already has the same contents.
+func writeBackup(data []byte) error {
+	dir := backupDir()
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return err
+	}
+	names, err := backupNames()
+	if err != nil {
+		return err
+	}
+	sum := checksum(data)
+	if len(names) > 0 && strings.HasSuffix(names[len(names)-1], "-"+sum+".json") {
+		return nil
+	}
+
+	name := fmt.Sprintf("history-%s-%s.json", time.Now().UTC().Format(backupTimeFormat), sum)
+	if err := writeFileSync(filepath.Join(dir, name), data); err != nil {
+		return err
+	}
+	syncDir(dir)
+
+	names = append(names, name)
+	for len(names) > maxBackups {
+		_ = os.Remove(filepath.Join(dir, names[0]))
+		names = names[1:]
+	}
+	return nil
+}
+
+// backupNames lists backup file names, oldest first.
+func backupNames() ([]string, error) {
+	dirEntries, err := os.ReadDir(backupDir())
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	var names []string
+	for _, de := range dirEntries {
+		if _, _, ok := parseBackupName(de.Name()); ok {
+			names = append(names, de.Name())
+		}
+	}
+	slices.Sort(names)
+	return names, nil
+}
+
+// parseBackupName splits "history-<time>-<checksum>.json".
+func parseBackupName(name string) (time.Time, string, bool) {
+	rest, ok := strings.CutPrefix(name, "history-")
+	if !ok {
+		return time.Time{}, "", false
+	}
+	rest, ok = strings.CutSuffix(rest, ".json")
+	if !ok {
+		return time.Time{}, "", false
+	}
+	stamp, sum, ok := strings.Cut(rest, "-")
+	if !ok {
+		return time.Time{}, "", false
+	}
+	t, err := time.Parse(backupTimeFormat, stamp)
+	if err != nil {
+		return time.Time{}, "", false
+	}
+	return t, sum, true
+}
+
+// readBackup verifies a backup's checksum and parses it.
+func readBackup(name string) ([]Entry, error) {
+	_, sum, ok := parseBackupName(name)
+	if !ok {
+		return nil, fmt.Errorf("backup %s: unrecognized name", name)
+	}
+	data, err := os.ReadFile(filepath.Join(backupDir(), name)) //nolint:gosec // name listed from our backup dir
+	if err != nil {
+		return nil, err
+	}
+	if checksum(data) != sum {
+		return nil, fmt.Errorf("backup %s: checksum mismatch", name)
+	}
+	entries, _, err := decodeHistory(data)
+	if err != nil {
+		return nil, fmt.Errorf("backup %s: %w", name, err)
+	}
+	return entries, nil
+}
+
+// Backups lists the rolling backups, newest first.
+func Backups() ([]Backup, error) {
+	names, err := backupNames()
+	if err != nil {
+		return nil, err
+	}
+	backups := make([]Backup, 0, len(names))
+	for _, name := range slices.Backward(names) {
+		t, _, _ := parseBackupName(name)
+		b := Backup{Name: name, Time: t}
+		if entries, err := readBackup(name); err == nil {
+			b.Valid, b.Entries = true, len(entries)
+		}
+		backups = append(backups, b)
+	}
+	return backups, nil
+}
+
+// LostFragment describes part of a damaged history file that could not be
+// recovered, either from the file itself or from a backup.
+type LostFragment struct {
+	ID      string `json:"id,omitempty"`
+	Preview string `json:"preview,omitempty"`
+	Bytes   int    `json:"bytes"`
+}
+
+// RepairReport summarizes what Repair did.
+type RepairReport struct {
+	// Healthy is true if the history file parsed and nothing was changed.
+	Healthy bool `json:"healthy"`
+	// Salvaged is how many entries were parsed from the damaged file.
+	Salvaged int `json:"salvaged"`
+	// Restored lists IDs recovered only from the backup.
+	Restored []string `json:"restored,omitempty"`
+	// Backup is the backup merged in, if any.
+	Backup string `json:"backup,omitempty"`
+	// Lost lists damaged fragments whose entries could not be recovered.
+	Lost []LostFragment `json:"lost,omitempty"`
+	// CorruptCopy is where the damaged file was preserved.
+	CorruptCopy string `json:"corrupt_copy,omitempty"`
+	// Entries is the number of entries in the repaired history.
+	Entries int `json:"entries"`
+}
+
+// Repair rebuilds a corrupt history file from the entries that still parse
+// and the newest backup that passes its checksum. Entries from the damaged
+// file win over their backup copies; entries only in the backup are restored
+// in their backup position. The damaged file is kept next to history.json.
+func Repair() (RepairReport, error) {
+	var report RepairReport
+	err := withLock(func() error {
+		data, err := os.ReadFile(historyPath())
+		if err != nil {
+			if os.IsNotExist(err) {
+				report.Healthy = true
+				return nil
+			}
+			return err
+		}
+		entries, _, err := decodeHistory(data)
+		switch {
+		case err == nil:
+			report.Healthy, report.Entries = true, len(entries)
+			return nil
+		case !errors.Is(err, ErrCorrupt):
+			// A newer format is not damage; rewriting it would lose data.
+			return err
+		}
+
+		salvaged, damaged := salvageEntries(data)
+		report.Salvaged = len(salvaged)
+
+		var backup []Entry
+		names, err := backupNames()
+		if err != nil {
+			return err
+		}
+		for _, name := range slices.Backward(names) {
+			if entries, err := readBackup(name); err == nil {
+				backup, report.Backup = entries, name
+				break
+			}
+		}
+
+		repaired, restored := mergeRecovered(salvaged, backup)
+		report.Restored = restored
+		report.Entries = len(repaired)
+
+		recovered := make(map[string]bool, len(repaired))
+		for _, e := range repaired {
+			recovered[e.ID] = true
+		}
+		for _, frag := range damaged {
+			lost := describeFragment(frag)
+			if lost.ID == "" || !recovered[lost.ID] {
+				report.Lost = append(report.Lost, lost)
+			}
+		}
+
+		report.CorruptCopy = fmt.Sprintf("%s.corrupt-%s", historyPath(), time.Now().UTC().Format("20060102T150405Z"))
+		if err := writeFileSync(report.CorruptCopy, data); err != nil {
+			return fmt.Errorf("preserve damaged file: %w", err)
+		}
+
+		return save(repaired, AuditEvent{
+			Action: "repair",
+			Message: fmt.Sprintf("salvaged %d, restored %d, lost %d",
+				report.Salvaged, len(report.Restored), len(report.Lost)),
+		})
+	})
+	return report, err
+}
+
+// mergeRecovered combines entries salvaged from the damaged file with the
+// backup. It returns the merged entries and the IDs taken from the backup.
+func mergeRecovered(salvaged, backup []Entry) ([]Entry, []string) {
+	current := make(map[string]Entry, len(salvaged))
+	for _, e := range salvaged {
+		current[e.ID] = e
+	}
+	merged := make([]Entry, 0, len(salvaged)+len(backup))
+	seen := make(map[string]bool, len(salvaged)+len(backup))
+	var restored []string
+	for _, e := range backup {
+		if c, ok := current[e.ID]; ok {
+			e = c
+		} else {
+			restored = append(restored, e.ID)
+		}
+		merged = append(merged, e)
+		seen[e.ID] = true
+	}
+	for _, e := range salvaged {
+		if !seen[e.ID] {
+			merged = append(merged, e)
+			seen[e.ID] = true
+		}
+	}
+	return merged, restored
+}
+
+// salvageEntries scans a damaged history file for top-level objects and returns
+// those that parse as entries, plus the raw fragments that do not (including
+// an unterminated trailing object).
+func salvageEntries(data []byte) ([]Entry, [][]byte) {
+	// In an envelope, scan only the entries array.
+	if bytes.HasPrefix(bytes.TrimSpace(data), []byte("{")) {
+		if i := bytes.Index(data, []byte(`"entries"`)); i != -1 {
+			data = data[i:]
+			if j := bytes.IndexByte(data, '['); j != -1 {
+				data = data[j:]
+			}
+		}
+	}
+	var (
+		entries []Entry
+		damaged [][]byte
+		depth   int
+		start   = -1
+		inStr   bool
+		escaped bool
+	)
+	for i, c := range data {
+		if inStr {
+			switch {
+			case escaped:
+				escaped = false
+			case c == '\\':
+				escaped = true
+			case c == '"':
+				inStr = false
+			}
+			continue
+		}
+		switch c {
+		case '"':
+			inStr = true
+		case '[':
+			depth++
+		case '{':
+			if start == -1 && depth <= 1 {
+				start = i
+				depth = 1
+			}
+			depth++
+		case '}', ']':
+			if depth > 0 {
+				depth--
+			}
+			if c == '}' && start != -1 && depth == 1 {
+				frag := data[start : i+1]
+				var e Entry
+				if err := json.Unmarshal(frag, &e); err == nil && e.ID != "" {
+					entries = append(entries, e)
+				} else {
+					damaged = append(damaged, frag)
+				}
+				start = -1
+			}
+		}
+	}
+	if start != -1 {
+		damaged = append(damaged, data[start:])
+	}
+	return entries, damaged
+}
+
+var (
+	fragmentID      = regexp.MustCompile(`"id"\s*:\s*"([^"\\]*)"`)
+	fragmentMessage = regexp.MustCompile(`"message"\s*:\s*"((?:[^"\\]|\\.)*)`)
+)
+
+// describeFragment extracts whatever identifies a damaged entry.
+func describeFragment(frag []byte) LostFragment {
+	lost := LostFragment{Bytes: len(frag)}
+	if m := fragmentID.FindSubmatch(frag); m != nil {
+		lost.ID = string(m[1])
+	}
+	if m := fragmentMessage.FindSubmatch(frag); m != nil {
+		msg := string(m[1])
+		if unquoted, err := unquoteJSON(m[1]); err == nil {
+			msg = unquoted
+		}
+		lost.Preview = previewLine(msg)
+	}
+	return lost
+}
+
+func unquoteJSON(raw []byte) (string, error) {
+	var s string
+	err := json.Unmarshal(bytes.Join([][]byte{[]byte(`"`), raw, []byte(`"`)}, nil), &s)
+	return s, err
+}
+
+// writeFileSync writes data to path and flushes it to stable storage.
+func writeFileSync(path string, data []byte) error {
+	f, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0o600) //nolint:gosec // path built from our data dir
+	if err != nil {
+		return err
+	}
+	if _, err := f.Write(data); err != nil {
+		_ = f.Close()
+		return err
+	}
+	if err := f.Sync(); err != nil {
+		_ = f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+// syncDir flushes a directory so that renames and new files in it survive a
+// crash. Not every platform supports this; errors are ignored.
+func syncDir(dir string) {
+	d, err := os.Open(dir) //nolint:gosec // path built from our data dir
+	if err != nil {
+		return
+	}
+	_ = d.Sync()
+	_ = d.Close()
+}
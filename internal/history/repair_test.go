@@ -0,0 +1,195 @@
+package history
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func appendMessages(t *testing.T, messages ...string) []Entry {
+	t.Helper()
+	entries := make([]Entry, 0, len(messages))
+	for _, m := range messages {
+		e, err := Append(m, "queued", time.Time{})
+		require.NoError(t, err)
+		entries = append(entries, e)
+	}
+	return entries
+}
+
+// truncateHistory cuts history.json just after the first occurrence of marker.
+func truncateHistory(t *testing.T, marker string) {
+	t.Helper()
+	data, err := os.ReadFile(historyPath())
+	require.NoError(t, err)
+	i := strings.Index(string(data), marker)
+	require.NotEqual(t, -1, i)
+	require.NoError(t, os.WriteFile(historyPath(), data[:i+len(marker)], 0o600))
+}
+
+func TestAtomicWrite_Backups(t *testing.T) {
+	withTempDataDir(t)
+
+	appendMessages(t, "one", "two")
+	names, err := backupNames()
+	require.NoError(t, err)
+	require.Len(t, names, 2)
+
+	// The newest backup matches the current file.
+	current, err := os.ReadFile(historyPath())
+	require.NoError(t, err)
+	assert.Contains(t, names[1], "-"+checksum(current)+".json")
+
+	// Rewriting identical contents does not add a backup.
+	entries, err := loadFromDisk()
+	require.NoError(t, err)
+	require.NoError(t, atomicWrite(entries))
+	names, err = backupNames()
+	require.NoError(t, err)
+	assert.Len(t, names, 2)
+
+	backups, err := Backups()
+	require.NoError(t, err)
+	require.Len(t, backups, 2)
+	assert.True(t, backups[0].Valid)
+	assert.Equal(t, 2, backups[0].Entries, "newest first")
+}
+
+func TestAtomicWrite_PrunesBackups(t *testing.T) {
+	withTempDataDir(t)
+
+	for i := range maxBackups + 3 {
+		appendMessages(t, strings.Repeat("x", i+1))
+	}
+	names, err := backupNames()
+	require.NoError(t, err)
+	assert.Len(t, names, maxBackups)
+}
+
+func TestLoad_Corrupt(t *testing.T) {
+	withTempDataDir(t)
+	require.NoError(t, os.MkdirAll(dataDir(), 0o700))
+	require.NoError(t, os.WriteFile(historyPath(), []byte(`[{"id": "abc`), 0o600))
+
+	_, err := Load()
+	require.ErrorIs(t, err, ErrCorrupt)
+	var corrupt *CorruptError
+	require.ErrorAs(t, err, &corrupt)
+	assert.Equal(t, historyPath(), corrupt.Path)
+
+	_, err = Append("new", "queued", time.Time{})
+	require.ErrorIs(t, err, ErrCorrupt, "mutations must not overwrite a corrupt file")
+}
+
+func TestRepair_Healthy(t *testing.T) {
+	withTempDataDir(t)
+	appendMessages(t, "one")
+
+	report, err := Repair()
+	require.NoError(t, err)
+	assert.True(t, report.Healthy)
+	assert.Equal(t, 1, report.Entries)
+}
+
+func TestRepair_TruncatedWithBackup(t *testing.T) {
+	withTempDataDir(t)
+	added := appendMessages(t, "first", "second", "third")
+	truncateHistory(t, `"message": "third"`)
+
+	report, err := Repair()
+	require.NoError(t, err)
+	assert.False(t, report.Healthy)
+	assert.Equal(t, 2, report.Salvaged)
+	assert.Equal(t, []string{added[2].ID}, report.Restored)
+	assert.NotEmpty(t, report.Backup)
+	assert.Empty(t, report.Lost, "the damaged entry was recovered from the backup")
+	assert.FileExists(t, report.CorruptCopy)
+
+	entries, err := Load()
+	require.NoError(t, err)
+	require.Len(t, entries, 3)
+	for i, e := range entries {
+		assert.Equal(t, added[i].ID, e.ID)
+	}
+
+	events, err := ReadAudit()
+	require.NoError(t, err)
+	assert.Equal(t, "repair", events[len(events)-1].Action)
+}
+
+func TestRepair_SalvagedEntriesWinOverBackup(t *testing.T) {
+	withTempDataDir(t)
+	added := appendMessages(t, "first", "second")
+	require.NoError(t, os.RemoveAll(backupDir()))
+	appendMessages(t, "third")
+
+	// Hand-edit the first entry, then break the file.
+	data, err := os.ReadFile(historyPath())
+	require.NoError(t, err)
+	edited := strings.Replace(string(data), `"message": "first"`, `"message": "first, edited"`, 1)
+	require.NoError(t, os.WriteFile(historyPath(), []byte(edited+"garbage]"), 0o600))
+
+	report, err := Repair()
+	require.NoError(t, err)
+	assert.Equal(t, 3, report.Salvaged)
+	assert.Empty(t, report.Restored)
+
+	e, err := Get(added[0].ID)
+	require.NoError(t, err)
+	assert.Equal(t, "first, edited", e.Message)
+}
+
+func TestRepair_NoBackupReportsLost(t *testing.T) {
+	withTempDataDir(t)
+	added := appendMessages(t, "first", "second\nmore lines")
+	require.NoError(t, os.RemoveAll(backupDir()))
+	truncateHistory(t, `"status"`) // cut inside the first entry after its message
+
+	report, err := Repair()
+	require.NoError(t, err)
+	assert.Empty(t, report.Backup)
+	assert.Equal(t, 0, report.Salvaged)
+	require.Len(t, report.Lost, 1)
+	assert.Equal(t, added[0].ID, report.Lost[0].ID)
+	assert.Equal(t, "first", report.Lost[0].Preview)
+}
+
+func TestRepair_SkipsTamperedBackup(t *testing.T) {
+	withTempDataDir(t)
+	appendMessages(t, "first", "second")
+	names, err := backupNames()
+	require.NoError(t, err)
+	newest := filepath.Join(backupDir(), names[len(names)-1])
+	require.NoError(t, os.WriteFile(newest, []byte("[]"), 0o600))
+	require.NoError(t, os.WriteFile(historyPath(), []byte("{"), 0o600))
+
+	report, err := Repair()
+	require.NoError(t, err)
+	assert.Equal(t, names[0], report.Backup)
+	assert.Equal(t, 1, report.Entries)
+}
+
+func TestSalvageEntries(t *testing.T) {
+	data := []byte(`[
+  {"id": "aaaa0001", "message": "braces { and } in \"text\"", "status": "queued"},
+  {"id": "aaaa0002", "message": "broken", "status": },
+  {"id": "aaaa0003", "message": "ok", "status": "published"},
+  {"id": "aaaa0004", "message": "cut off`)
+
+	entries, damaged := salvageEntries(data)
+	require.Len(t, entries, 2)
+	assert.Equal(t, "aaaa0001", entries[0].ID)
+	assert.Equal(t, `braces { and } in "text"`, entries[0].Message)
+	assert.Equal(t, "aaaa0003", entries[1].ID)
+
+	require.Len(t, damaged, 2)
+	assert.Equal(t, "aaaa0002", describeFragment(damaged[0]).ID)
+	lost := describeFragment(damaged[1])
+	assert.Equal(t, "aaaa0004", lost.ID)
+	assert.Equal(t, "cut off", lost.Preview)
+}
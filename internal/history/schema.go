@@ -0,0 +1,219 @@
+package history
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"hash/fnv"
+)
+
+// CurrentVersion is the history file format written by this binary.
+//
+// Version history:
+//
+//	0: bare array of {"ts", "message"} objects (the original post log)
+//	1: bare array of entries with IDs and statuses
+//	2: {"version": 2, "entries": [...]} envelope
+//	3: recurring templates ("recurring" status, recurrence fields)
+//	4: post series (series field)
+//	5: held entries (held field)
+//	6: jitter seeds (jitter_seed field)
+//
+// To change the format, bump CurrentVersion and append a migration from the
+// previous version to the registry below.
+const CurrentVersion = 6
+
+// migration upgrades the raw entries array from version From to From+1.
+type migration struct {
+	From  int
+	Name  string
+	Apply func(entries json.RawMessage) (json.RawMessage, error)
+}
+
+// migrations is the ordered upgrade path; migrations[i].From must equal i.
+var migrations = []migration{
+	{From: 0, Name: "assign IDs and statuses to legacy post log entries", Apply: migrateLegacyEntries},
+	{From: 1, Name: "wrap entries in a versioned envelope", Apply: unchanged},
+	// Older binaries would drop the new fields on write, so adding them
+	// bumps the version even though existing entries need no change.
+	{From: 2, Name: "add recurring templates", Apply: unchanged},
+	{From: 3, Name: "add post series", Apply: unchanged},
+	{From: 4, Name: "add held entries", Apply: unchanged},
+	{From: 5, Name: "assign jitter seeds", Apply: migrateJitterSeeds},
+}
+
+// unchanged is a migration whose format change needs no rewrite of existing
+// entries (the version bump alone is the point).
+func unchanged(entries json.RawMessage) (json.RawMessage, error) { return entries, nil }
+
+// ErrNewerVersion is returned when the history file was written by a newer
+// release. Older binaries refuse to read or rewrite it rather than silently
+// dropping fields they do not know about.
+var ErrNewerVersion = errors.New("history file was written by a newer version of slack-social-ai")
+
+// VersionError reports a history file format newer than CurrentVersion.
+type VersionError struct {
+	Found     int
+	Supported int
+}
+
+func (e *VersionError) Error() string {
+	return fmt.Sprintf("%s (format %d, this binary supports up to %d); upgrade slack-social-ai",
+		ErrNewerVersion, e.Found, e.Supported)
+}
+
+func (e *VersionError) Is(target error) bool { return target == ErrNewerVersion }
+
+// historyFile is the on-disk envelope.
+type historyFile struct {
+	Version int             `json:"version"`
+	Entries json.RawMessage `json:"entries"`
+}
+
+// encodeHistory renders entries in the current format.
+func encodeHistory(entries []Entry) ([]byte, error) {
+	if entries == nil {
+		entries = []Entry{}
+	}
+	raw, err := json.Marshal(entries)
+	if err != nil {
+		return nil, err
+	}
+	return json.MarshalIndent(historyFile{Version: CurrentVersion, Entries: raw}, "", "  ")
+}
+
+// decodeHistory parses a history file of any supported version, applying
+// migrations in order. It returns the entries and the version found on disk.
+// Unparseable files yield a *CorruptError; newer versions a *VersionError.
+func decodeHistory(data []byte) ([]Entry, int, error) {
+	version, raw, err := splitVersion(data)
+	if err != nil {
+		return nil, 0, &CorruptError{Path: historyPath(), Err: err}
+	}
+	if version > CurrentVersion {
+		return nil, version, &VersionError{Found: version, Supported: CurrentVersion}
+	}
+	raw, err = migrate(raw, version)
+	if err != nil {
+		return nil, version, &CorruptError{Path: historyPath(), Err: err}
+	}
+	var entries []Entry
+	if err := json.Unmarshal(raw, &entries); err != nil {
+		return nil, version, &CorruptError{Path: historyPath(), Err: err}
+	}
+	return entries, version, nil
+}
+
+// splitVersion returns the format version and raw entries array of a
+// history file. Bare arrays predate the envelope and are version 0 or 1.
+func splitVersion(data []byte) (int, json.RawMessage, error) {
+	data = bytes.TrimSpace(data)
+	if bytes.HasPrefix(data, []byte("[")) {
+		legacy, err := isLegacyArray(data)
+		if err != nil {
+			return 0, nil, err
+		}
+		if legacy {
+			return 0, data, nil
+		}
+		return 1, data, nil
+	}
+
+	var file historyFile
+	if err := json.Unmarshal(data, &file); err != nil {
+		return 0, nil, err
+	}
+	if file.Version < 1 {
+		return 0, nil, fmt.Errorf("missing or invalid format version %d", file.Version)
+	}
+	if len(file.Entries) == 0 {
+		file.Entries = json.RawMessage("[]")
+	}
+	return file.Version, file.Entries, nil
+}
+
+// migrate applies the registered migrations from version up to CurrentVersion.
+func migrate(raw json.RawMessage, version int) (json.RawMessage, error) {
+	for _, m := range migrations[version:] {
+		var err error
+		if raw, err = m.Apply(raw); err != nil {
+			return nil, fmt.Errorf("migrate from version %d (%s): %w", m.From, m.Name, err)
+		}
+	}
+	return raw, nil
+}
+
+// isLegacyArray reports whether a bare array is the version 0 post log:
+// entries carry "ts" but none has an "id". This is the only format that is
+// detected rather than declared, since it predates the version field.
+func isLegacyArray(data []byte) (bool, error) {
+	var objects []map[string]json.RawMessage
+	if err := json.Unmarshal(data, &objects); err != nil {
+		return false, err
+	}
+	hasTS := false
+	for _, o := range objects {
+		if _, ok := o["id"]; ok {
+			return false, nil
+		}
+		if _, ok := o["ts"]; ok {
+			hasTS = true
+		}
+	}
+	return hasTS, nil
+}
+
+// legacyEntry is a version 0 post log record.
+type legacyEntry struct {
+	Timestamp string `json:"ts"`
+	Message   string `json:"message"`
+}
+
+// migrateLegacyEntries (0 → 1) turns post log records into published
+// entries with IDs derived from their contents, so repeated reads of an
+// unmigrated file agree on IDs.
+func migrateLegacyEntries(raw json.RawMessage) (json.RawMessage, error) {
+	var legacy []legacyEntry
+	if err := json.Unmarshal(raw, &legacy); err != nil {
+		return nil, err
+	}
+	out := make([]map[string]string, 0, len(legacy))
+	for i, l := range legacy {
+		h := sha256.Sum256(fmt.Appendf(nil, "%s:%d:%s", l.Timestamp, i, l.Message))
+		out = append(out, map[string]string{
+			"id":           hex.EncodeToString(h[:])[:8],
+			"message":      l.Message,
+			"status":       "published",
+			"created_at":   l.Timestamp,
+			"published_at": l.Timestamp,
+		})
+	}
+	return json.Marshal(out)
+}
+
+// migrateJitterSeeds (5 → 6) gives every entry a jitter seed derived from
+// its ID, so repeated reads of an unmigrated file agree on seeds and hence
+// on predicted publish times.
+func migrateJitterSeeds(raw json.RawMessage) (json.RawMessage, error) {
+	var objects []map[string]json.RawMessage
+	if err := json.Unmarshal(raw, &objects); err != nil {
+		return nil, err
+	}
+	for _, o := range objects {
+		if _, ok := o["jitter_seed"]; ok {
+			continue
+		}
+		var id string
+		if err := json.Unmarshal(o["id"], &id); err != nil {
+			return nil, fmt.Errorf("entry id: %w", err)
+		}
+		h := fnv.New32a()
+		h.Write([]byte(id))
+		seed, _ := json.Marshal(h.Sum32() | 1)
+		o["jitter_seed"] = seed
+	}
+	return json.Marshal(objects)
+}
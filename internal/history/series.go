@@ -0,0 +1,340 @@
+package history
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"time"
+)
+
+// ErrNoSeries is returned when no entries belong to the named series.
+var ErrNoSeries = errors.New("series not found")
+
+// SeriesPart places an entry in a series: messages that publish as one
+// ordered unit. Parts publish in Part order, at least Gap apart. The
+// settings (Gap, Strict, ThreadTS) are kept the same on every unpublished
+// part of a series.
+type SeriesPart struct {
+	Name     string `json:"name"`
+	Part     int    `json:"part"`                // 1-based
+	Gap      string `json:"gap,omitempty"`       // Go duration between parts; empty = next publish run
+	Strict   bool   `json:"strict,omitempty"`    // no other posts publish between parts
+	ThreadTS string `json:"thread_ts,omitempty"` // Slack message the parts reply to
+}
+
+// GapDuration returns the minimum time between parts.
+func (s SeriesPart) GapDuration() time.Duration {
+	d, err := time.ParseDuration(s.Gap)
+	if err != nil {
+		return 0
+	}
+	return d
+}
+
+// isUnfinished reports whether a series part has yet to publish. Expired
+// and failed parts are skipped so they do not hold up the rest.
+func isUnfinished(e Entry) bool {
+	return e.Status == "draft" || isPending(e)
+}
+
+// seriesProgress is the publishing state of one series.
+type seriesProgress struct {
+	settings      SeriesPart
+	next          int       // index of the lowest unfinished part, -1 if none
+	lastPublished time.Time // publish time of the latest published part
+}
+
+// readyAt returns when the next part may publish: one gap after the
+// previous part, and not before its own scheduled time.
+func (p seriesProgress) readyAt(entries []Entry) time.Time {
+	ready := p.lastPublished
+	if !ready.IsZero() {
+		ready = ready.Add(p.settings.GapDuration())
+	}
+	if p.next != -1 {
+		if scheduled, err := time.Parse(time.RFC3339, entries[p.next].ScheduledAt); err == nil && scheduled.After(ready) {
+			ready = scheduled
+		}
+	}
+	return ready
+}
+
+// inProgress reports whether the series has published a part and its next
+// part is queued.
+func (p seriesProgress) inProgress(entries []Entry) bool {
+	return !p.lastPublished.IsZero() && p.next != -1 && isPending(entries[p.next])
+}
+
+// seriesStates returns the progress of every series in entries.
+func seriesStates(entries []Entry) map[string]*seriesProgress {
+	states := make(map[string]*seriesProgress)
+	for i, e := range entries {
+		if e.Series == nil {
+			continue
+		}
+		st, ok := states[e.Series.Name]
+		if !ok {
+			st = &seriesProgress{next: -1}
+			states[e.Series.Name] = st
+		}
+		switch {
+		case isUnfinished(e):
+			if st.next == -1 || e.Series.Part < entries[st.next].Series.Part {
+				st.next = i
+				st.settings = *e.Series
+			}
+		case e.Status == "published":
+			if t, err := time.Parse(time.RFC3339, e.PublishedAt); err == nil && t.After(st.lastPublished) {
+				st.lastPublished = t
+			}
+		}
+	}
+	return states
+}
+
+// activeSeries returns the in-progress series whose last part published
+// most recently, or nil.
+func activeSeries(entries []Entry, states map[string]*seriesProgress) *seriesProgress {
+	var active *seriesProgress
+	for _, st := range states {
+		if st.inProgress(entries) && (active == nil || st.lastPublished.After(active.lastPublished)) {
+			active = st
+		}
+	}
+	return active
+}
+
+// claimOrder returns the indices of pending entries in the order
+// ClaimNextReady considers them. The next part of a series in progress goes
+// first; a strict series in progress is the only candidate.
+func claimOrder(entries []Entry, states map[string]*seriesProgress) []int {
+	order := queueIndices(entries)
+	active := activeSeries(entries, states)
+	if active == nil {
+		return order
+	}
+	if active.settings.Strict {
+		return []int{active.next}
+	}
+	return append([]int{active.next}, slices.DeleteFunc(order, func(i int) bool { return i == active.next })...)
+}
+
+// seriesReady reports whether the entry at i may publish at now as far as
+// its series is concerned: it must be the series' next part and the gap
+// since the previous part must have passed.
+func seriesReady(entries []Entry, i int, states map[string]*seriesProgress, now time.Time) bool {
+	e := entries[i]
+	if e.Series == nil {
+		return true
+	}
+	st := states[e.Series.Name]
+	return st.next == i && !now.Before(st.readyAt(entries))
+}
+
+// ActiveSeries describes the series currently being published.
+type ActiveSeries struct {
+	Name    string
+	Next    Entry     // the next part to publish
+	ReadyAt time.Time // when the gap since the previous part has passed
+	Strict  bool
+}
+
+// CurrentSeries returns the series that has published a part and still has
+// its next part queued, if any. Between parts, the series gap takes the
+// place of the schedule's post_every spacing.
+func CurrentSeries() (ActiveSeries, bool, error) {
+	entries, err := Load()
+	if err != nil {
+		return ActiveSeries{}, false, err
+	}
+	active := activeSeries(entries, seriesStates(entries))
+	if active == nil {
+		return ActiveSeries{}, false, nil
+	}
+	return ActiveSeries{
+		Name:    active.settings.Name,
+		Next:    entries[active.next],
+		ReadyAt: active.readyAt(entries),
+		Strict:  active.settings.Strict,
+	}, true, nil
+}
+
+// AppendSeriesPart appends entry as the next part of the named series,
+// starting the series if needed. The new part inherits the series settings
+// from the latest part; update, if non-nil, then changes them for every
+// unpublished part.
+func AppendSeriesPart(entry Entry, name string, update func(s *SeriesPart)) (Entry, error) {
+	entry.ID = generateID()
+	entry.JitterSeed = generateSeed()
+	entry.CreatedAt = time.Now().UTC().Format(time.RFC3339)
+
+	var result Entry
+	err := withLock(func() error {
+		entries, err := loadFromDisk()
+		if err != nil {
+			return fmt.Errorf("load history: %w", err)
+		}
+		part := nextSeriesPart(entries, name)
+		events := []AuditEvent{newEvent("append", entry, "", entry.Status)}
+		if update != nil {
+			n := part.Part
+			update(&part)
+			part.Name, part.Part = name, n
+			events = append(events, updateSeriesParts(entries, name, update)...)
+		}
+		entry.Series = &part
+		entries = append(entries, entry)
+
+		kept, evicted := trimEntries(entries)
+		result = entry
+		return save(kept, append(events, evicted...)...)
+	})
+	return result, err
+}
+
+// nextSeriesPart returns the next part of the named series, with the
+// settings of its latest part.
+func nextSeriesPart(entries []Entry, name string) SeriesPart {
+	part := SeriesPart{Name: name, Part: 1}
+	for _, e := range entries {
+		if e.Series != nil && e.Series.Name == name && e.Series.Part >= part.Part {
+			part = *e.Series
+			part.Part++
+		}
+	}
+	return part
+}
+
+// UpdateSeries changes the settings of every unpublished part of a series
+// and returns how many parts changed.
+func UpdateSeries(name string, update func(s *SeriesPart)) (int, error) {
+	n := 0
+	err := withLock(func() error {
+		entries, err := loadFromDisk()
+		if err != nil {
+			return err
+		}
+		if !slices.ContainsFunc(entries, func(e Entry) bool { return e.Series != nil && e.Series.Name == name }) {
+			return fmt.Errorf("series %q: %w", name, ErrNoSeries)
+		}
+		events := updateSeriesParts(entries, name, update)
+		n = len(events)
+		if n == 0 {
+			return nil
+		}
+		return save(entries, events...)
+	})
+	return n, err
+}
+
+// updateSeriesParts applies update to the unfinished parts of a series in
+// place and returns an audit event per changed part. Name and Part are
+// not changed.
+func updateSeriesParts(entries []Entry, name string, update func(s *SeriesPart)) []AuditEvent {
+	var events []AuditEvent
+	now := time.Now().UTC().Format(time.RFC3339)
+	for i, e := range entries {
+		if e.Series == nil || e.Series.Name != name || !isUnfinished(e) {
+			continue
+		}
+		s := *e.Series
+		update(&s)
+		s.Name, s.Part = e.Series.Name, e.Series.Part
+		if s == *e.Series {
+			continue
+		}
+		entries[i].Series = &s
+		entries[i].UpdatedAt = now
+		events = append(events, newEvent("series", entries[i], e.Status, e.Status))
+	}
+	return events
+}
+
+// SeriesSummary describes one series for listings. Settings are those of
+// the latest part, which every unpublished part shares.
+type SeriesSummary struct {
+	Name      string
+	Gap       string
+	Strict    bool
+	ThreadTS  string
+	Parts     int
+	Published int
+	Pending   int     // queued, publishing, or draft
+	Entries   []Entry // all parts, in part order
+}
+
+// Series returns every series, in order of first appearance.
+func Series() ([]SeriesSummary, error) {
+	entries, err := Load()
+	if err != nil {
+		return nil, err
+	}
+	var result []SeriesSummary
+	index := make(map[string]int)
+	for _, e := range entries {
+		if e.Series == nil {
+			continue
+		}
+		i, ok := index[e.Series.Name]
+		if !ok {
+			i = len(result)
+			index[e.Series.Name] = i
+			result = append(result, SeriesSummary{Name: e.Series.Name})
+		}
+		s := &result[i]
+		s.Entries = append(s.Entries, e)
+		s.Parts++
+		switch {
+		case e.Status == "published":
+			s.Published++
+		case isUnfinished(e):
+			s.Pending++
+		}
+	}
+	for i := range result {
+		s := &result[i]
+		slices.SortStableFunc(s.Entries, func(a, b Entry) int { return a.Series.Part - b.Series.Part })
+		latest := *s.Entries[len(s.Entries)-1].Series
+		s.Gap, s.Strict, s.ThreadTS = latest.Gap, latest.Strict, latest.ThreadTS
+	}
+	return result, nil
+}
+
+// RemoveSeries deletes the unpublished parts of a series, keeping an undo
+// snapshot, and returns how many were removed. Published parts stay in
+// history.
+func RemoveSeries(name string) (int, error) {
+	n := 0
+	err := withLock(func() error {
+		entries, err := loadFromDisk()
+		if err != nil {
+			return err
+		}
+		inSeries := func(e Entry) bool { return e.Series != nil && e.Series.Name == name }
+		if !slices.ContainsFunc(entries, inSeries) {
+			return fmt.Errorf("series %q: %w", name, ErrNoSeries)
+		}
+		removed := func(e Entry) bool { return inSeries(e) && isUnfinished(e) }
+		if slices.ContainsFunc(entries, func(e Entry) bool { return removed(e) && e.Status == "publishing" }) {
+			return fmt.Errorf("series %q: %w", name, ErrPublishing)
+		}
+		if !slices.ContainsFunc(entries, removed) {
+			return nil
+		}
+		if err := recordSnapshot("remove", entries, removed); err != nil {
+			return err
+		}
+		kept := make([]Entry, 0, len(entries))
+		var events []AuditEvent
+		for _, e := range entries {
+			if removed(e) {
+				events = append(events, newEvent("remove", e, e.Status, ""))
+				continue
+			}
+			kept = append(kept, e)
+		}
+		n = len(events)
+		return save(kept, events...)
+	})
+	return n, err
+}
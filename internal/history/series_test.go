@@ -0,0 +1,170 @@
+package history
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAppendSeriesPart_NumbersAndInherits(t *testing.T) {
+	withTempDataDir(t)
+
+	p1, err := AppendSeriesPart(Entry{Message: "one", Status: "queued"}, "dive", func(s *SeriesPart) {
+		s.Gap, s.Strict = "30m", true
+	})
+	require.NoError(t, err)
+	assert.Equal(t, SeriesPart{Name: "dive", Part: 1, Gap: "30m", Strict: true}, *p1.Series)
+
+	p2, err := AppendSeriesPart(Entry{Message: "two", Status: "queued"}, "dive", nil)
+	require.NoError(t, err)
+	assert.Equal(t, SeriesPart{Name: "dive", Part: 2, Gap: "30m", Strict: true}, *p2.Series, "settings are inherited")
+
+	// Changing a setting on a later part updates the earlier unpublished ones.
+	_, err = AppendSeriesPart(Entry{Message: "three", Status: "queued"}, "dive", func(s *SeriesPart) { s.Gap = "1h" })
+	require.NoError(t, err)
+	got, err := Get(p1.ID)
+	require.NoError(t, err)
+	assert.Equal(t, "1h", got.Series.Gap)
+	assert.Equal(t, 1, got.Series.Part)
+
+	other, err := AppendSeriesPart(Entry{Message: "solo", Status: "queued"}, "other", nil)
+	require.NoError(t, err)
+	assert.Equal(t, 1, other.Series.Part)
+}
+
+func TestClaimNextReady_SeriesInOrder(t *testing.T) {
+	withTempDataDir(t)
+	writeEntries(t, []Entry{
+		{ID: "p2", Message: "part 2", Status: "queued", Priority: 5, Series: &SeriesPart{Name: "dive", Part: 2}},
+		{ID: "p1", Message: "part 1", Status: "queued", Series: &SeriesPart{Name: "dive", Part: 1}},
+	})
+
+	first, err := ClaimNextReady()
+	require.NoError(t, err)
+	require.NotNil(t, first)
+	assert.Equal(t, "p1", first.ID, "part 2 waits for part 1 despite its priority")
+
+	next, err := ClaimNextReady()
+	require.NoError(t, err)
+	assert.Nil(t, next, "part 2 waits while part 1 is publishing")
+
+	require.NoError(t, MarkPublished("p1"))
+	next, err = ClaimNextReady()
+	require.NoError(t, err)
+	require.NotNil(t, next)
+	assert.Equal(t, "p2", next.ID)
+}
+
+func TestClaimNextReady_SeriesGap(t *testing.T) {
+	withTempDataDir(t)
+	justNow := time.Now().UTC().Add(-time.Minute).Format(time.RFC3339)
+	writeEntries(t, []Entry{
+		{ID: "p1", Message: "part 1", Status: "published", PublishedAt: justNow, Series: &SeriesPart{Name: "dive", Part: 1, Gap: "30m"}},
+		{ID: "p2", Message: "part 2", Status: "queued", Series: &SeriesPart{Name: "dive", Part: 2, Gap: "30m"}},
+		{ID: "x", Message: "unrelated", Status: "queued"},
+	})
+
+	active, ok, err := CurrentSeries()
+	require.NoError(t, err)
+	require.True(t, ok)
+	assert.Equal(t, "p2", active.Next.ID)
+	assert.WithinDuration(t, time.Now().Add(29*time.Minute), active.ReadyAt, time.Minute)
+
+	// Not strict: another post may go out while the series waits out its gap.
+	claimed, err := ClaimNextReady()
+	require.NoError(t, err)
+	require.NotNil(t, claimed)
+	assert.Equal(t, "x", claimed.ID)
+}
+
+func TestClaimNextReady_StrictSeries(t *testing.T) {
+	withTempDataDir(t)
+	long := time.Now().UTC().Add(-2 * time.Hour).Format(time.RFC3339)
+	recent := time.Now().UTC().Add(-time.Minute).Format(time.RFC3339)
+	writeEntries(t, []Entry{
+		{ID: "x", Message: "unrelated", Status: "queued", Priority: 9},
+		{ID: "p1", Message: "part 1", Status: "published", PublishedAt: recent, Series: &SeriesPart{Name: "dive", Part: 1, Gap: "30m", Strict: true}},
+		{ID: "p2", Message: "part 2", Status: "queued", Series: &SeriesPart{Name: "dive", Part: 2, Gap: "30m", Strict: true}},
+	})
+
+	claimed, err := ClaimNextReady()
+	require.NoError(t, err)
+	assert.Nil(t, claimed, "strict series holds the queue during its gap")
+
+	writeEntries(t, []Entry{
+		{ID: "x", Message: "unrelated", Status: "queued", Priority: 9},
+		{ID: "p1", Message: "part 1", Status: "published", PublishedAt: long, Series: &SeriesPart{Name: "dive", Part: 1, Gap: "30m", Strict: true}},
+		{ID: "p2", Message: "part 2", Status: "queued", Series: &SeriesPart{Name: "dive", Part: 2, Gap: "30m", Strict: true}},
+	})
+	claimed, err = ClaimNextReady()
+	require.NoError(t, err)
+	require.NotNil(t, claimed)
+	assert.Equal(t, "p2", claimed.ID, "the series continues before higher-priority posts")
+}
+
+func TestClaimNextReady_SeriesSkipsExpiredPart(t *testing.T) {
+	withTempDataDir(t)
+	writeEntries(t, []Entry{
+		{ID: "p1", Message: "part 1", Status: "expired", Series: &SeriesPart{Name: "dive", Part: 1}},
+		{ID: "p2", Message: "part 2", Status: "queued", Series: &SeriesPart{Name: "dive", Part: 2}},
+	})
+
+	claimed, err := ClaimNextReady()
+	require.NoError(t, err)
+	require.NotNil(t, claimed)
+	assert.Equal(t, "p2", claimed.ID)
+}
+
+func TestSeriesAndRemoveSeries(t *testing.T) {
+	withTempDataDir(t)
+	writeEntries(t, []Entry{
+		{ID: "p1", Message: "part 1", Status: "published", PublishedAt: "2026-03-02T10:00:00Z", Series: &SeriesPart{Name: "dive", Part: 1}},
+		{ID: "p3", Message: "part 3", Status: "draft", Series: &SeriesPart{Name: "dive", Part: 3, Strict: true}},
+		{ID: "p2", Message: "part 2", Status: "queued", Series: &SeriesPart{Name: "dive", Part: 2, Strict: true}},
+		{ID: "x", Message: "unrelated", Status: "queued"},
+	})
+
+	series, err := Series()
+	require.NoError(t, err)
+	require.Len(t, series, 1)
+	s := series[0]
+	assert.Equal(t, "dive", s.Name)
+	assert.Equal(t, 3, s.Parts)
+	assert.Equal(t, 1, s.Published)
+	assert.Equal(t, 2, s.Pending)
+	assert.True(t, s.Strict)
+	assert.Equal(t, []string{"p1", "p2", "p3"}, []string{s.Entries[0].ID, s.Entries[1].ID, s.Entries[2].ID})
+
+	n, err := RemoveSeries("dive")
+	require.NoError(t, err)
+	assert.Equal(t, 2, n)
+
+	entries, err := Load()
+	require.NoError(t, err)
+	require.Len(t, entries, 2)
+	assert.Equal(t, "p1", entries[0].ID, "published parts stay in history")
+
+	_, err = RemoveSeries("missing")
+	require.ErrorIs(t, err, ErrNoSeries)
+}
+
+func TestUpdateSeries(t *testing.T) {
+	withTempDataDir(t)
+	writeEntries(t, []Entry{
+		{ID: "p1", Message: "part 1", Status: "published", Series: &SeriesPart{Name: "dive", Part: 1}},
+		{ID: "p2", Message: "part 2", Status: "queued", Series: &SeriesPart{Name: "dive", Part: 2}},
+	})
+
+	n, err := UpdateSeries("dive", func(s *SeriesPart) { s.ThreadTS = "1712345678.123456" })
+	require.NoError(t, err)
+	assert.Equal(t, 1, n, "published parts are not changed")
+
+	got, err := Get("p2")
+	require.NoError(t, err)
+	assert.Equal(t, "1712345678.123456", got.Series.ThreadTS)
+
+	_, err = UpdateSeries("missing", func(*SeriesPart) {})
+	require.ErrorIs(t, err, ErrNoSeries)
+}
@@ -0,0 +1,183 @@
+package history
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"time"
+)
+
+// maxSnapshots caps how many undo snapshots are kept, oldest dropped first.
+const maxSnapshots = 50
+
+// ErrNoSnapshot is returned when there is nothing (left) to undo.
+var ErrNoSnapshot = errors.New("no undo snapshot")
+
+// Snapshot holds the entries removed by one destructive operation so that
+// Undo can put them back.
+type Snapshot struct {
+	ID        string          `json:"id"`
+	Operation string          `json:"operation"`  // "remove" | "clear" | "clear-all"
+	CreatedAt string          `json:"created_at"` // RFC3339
+	Entries   []SnapshotEntry `json:"entries"`
+}
+
+// SnapshotEntry is a removed entry and its index in the history file at the
+// time, which is what determines its queue position among equal priorities.
+type SnapshotEntry struct {
+	Entry Entry `json:"entry"`
+	Index int   `json:"index"`
+}
+
+func undoPath() string { return filepath.Join(dataDir(), "undo.json") }
+
+func loadSnapshots() ([]Snapshot, error) {
+	data, err := os.ReadFile(undoPath())
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	var snaps []Snapshot
+	if err := json.Unmarshal(data, &snaps); err != nil {
+		return nil, fmt.Errorf("parse undo snapshots: %w", err)
+	}
+	return snaps, nil
+}
+
+func writeSnapshots(snaps []Snapshot) error {
+	data, err := json.MarshalIndent(snaps, "", "  ")
+	if err != nil {
+		return err
+	}
+	tmp := undoPath() + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o600); err != nil {
+		return err
+	}
+	return os.Rename(tmp, undoPath())
+}
+
+// recordSnapshot stores the entries about to be removed by op.
+// Must be called under withLock, before the history file is rewritten,
+// so a destructive operation never happens without its snapshot.
+func recordSnapshot(op string, entries []Entry, removed func(Entry) bool) error {
+	snap := Snapshot{
+		ID:        generateID(),
+		Operation: op,
+		CreatedAt: time.Now().UTC().Format(time.RFC3339),
+	}
+	for i, e := range entries {
+		if removed(e) {
+			snap.Entries = append(snap.Entries, SnapshotEntry{Entry: e, Index: i})
+		}
+	}
+	if len(snap.Entries) == 0 {
+		return nil
+	}
+
+	snaps, err := loadSnapshots()
+	if err != nil {
+		return err
+	}
+	snaps = append(snaps, snap)
+	if len(snaps) > maxSnapshots {
+		snaps = snaps[len(snaps)-maxSnapshots:]
+	}
+	if err := writeSnapshots(snaps); err != nil {
+		return fmt.Errorf("write undo snapshot: %w", err)
+	}
+	return nil
+}
+
+// pruneSnapshots drops snapshots older than maxAge. It reports whether
+// anything was dropped.
+func pruneSnapshots(snaps []Snapshot, maxAge time.Duration, now time.Time) ([]Snapshot, bool) {
+	kept := slices.DeleteFunc(slices.Clone(snaps), func(s Snapshot) bool {
+		created, err := time.Parse(time.RFC3339, s.CreatedAt)
+		return err != nil || now.Sub(created) > maxAge
+	})
+	return kept, len(kept) != len(snaps)
+}
+
+// Snapshots returns undo snapshots younger than maxAge, newest first.
+// Expired snapshots are deleted.
+func Snapshots(maxAge time.Duration) ([]Snapshot, error) {
+	var result []Snapshot
+	err := withLock(func() error {
+		snaps, err := loadSnapshots()
+		if err != nil {
+			return err
+		}
+		kept, pruned := pruneSnapshots(snaps, maxAge, time.Now().UTC())
+		if pruned {
+			if err := writeSnapshots(kept); err != nil {
+				return err
+			}
+		}
+		result = kept
+		return nil
+	})
+	slices.Reverse(result)
+	return result, err
+}
+
+// Undo restores the entries of a snapshot (the most recent one when id is
+// empty) at their original positions and deletes the snapshot. Entries whose
+// ID is already back in history are skipped and returned separately.
+// Entries removed mid-publish are restored as "queued". The restored history
+// is trimmed to maxEntries, evicting the oldest published entries first.
+func Undo(id string, maxAge time.Duration) (snap Snapshot, skipped []string, err error) {
+	err = withLock(func() error {
+		snaps, err := loadSnapshots()
+		if err != nil {
+			return err
+		}
+		snaps, _ = pruneSnapshots(snaps, maxAge, time.Now().UTC())
+
+		i := len(snaps) - 1
+		if id != "" {
+			i = slices.IndexFunc(snaps, func(s Snapshot) bool { return s.ID == id })
+		}
+		if i < 0 {
+			if id != "" {
+				return fmt.Errorf("snapshot %q: %w", id, ErrNoSnapshot)
+			}
+			return ErrNoSnapshot
+		}
+		snap = snaps[i]
+
+		entries, err := loadFromDisk()
+		if err != nil {
+			return err
+		}
+		var events []AuditEvent
+		for _, se := range snap.Entries {
+			e := se.Entry
+			if slices.ContainsFunc(entries, func(x Entry) bool { return x.ID == e.ID }) {
+				skipped = append(skipped, e.ID)
+				continue
+			}
+			before := e.Status
+			if e.Status == "publishing" {
+				e.Status = "queued"
+			}
+			entries = slices.Insert(entries, min(se.Index, len(entries)), e)
+			events = append(events, newEvent("restore", e, before, e.Status))
+		}
+
+		// Restored entries count against maxEntries like any other write.
+		kept, evicted := trimEntries(entries)
+
+		// Restore first: if dropping the snapshot then fails, a repeated
+		// undo skips the entries that are already back.
+		if err := save(kept, append(events, evicted...)...); err != nil {
+			return err
+		}
+		return writeSnapshots(slices.Delete(snaps, i, i+1))
+	})
+	return snap, skipped, err
+}
@@ -0,0 +1,214 @@
+package history
+
+import (
+	"os"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const week = 7 * 24 * time.Hour
+
+func TestUndo_Remove_RestoresPosition(t *testing.T) {
+	withTempDataDir(t)
+
+	a, err := Append("a", "queued", time.Time{})
+	require.NoError(t, err)
+	b, err := Append("b", "queued", time.Time{})
+	require.NoError(t, err)
+	c, err := Append("c", "queued", time.Time{})
+	require.NoError(t, err)
+
+	found, err := Remove(b.ID)
+	require.NoError(t, err)
+	require.True(t, found)
+
+	snap, skipped, err := Undo("", week)
+	require.NoError(t, err)
+	assert.Empty(t, skipped)
+	assert.Equal(t, "remove", snap.Operation)
+
+	queued, err := Queued()
+	require.NoError(t, err)
+	require.Len(t, queued, 3)
+	assert.Equal(t, []string{a.ID, b.ID, c.ID}, []string{queued[0].ID, queued[1].ID, queued[2].ID})
+
+	_, _, err = Undo("", week)
+	require.ErrorIs(t, err, ErrNoSnapshot, "snapshot is consumed")
+}
+
+func TestUndo_ClearAll(t *testing.T) {
+	withTempDataDir(t)
+
+	for _, status := range []string{"published", "queued", "draft"} {
+		_, err := Append(status, status, time.Time{})
+		require.NoError(t, err)
+	}
+	before, err := Load()
+	require.NoError(t, err)
+
+	require.NoError(t, ClearAll())
+	_, _, err = Undo("", week)
+	require.NoError(t, err)
+
+	after, err := Load()
+	require.NoError(t, err)
+	assert.Equal(t, before, after)
+}
+
+func TestUndo_ClearPublished_KeepsNewEntries(t *testing.T) {
+	withTempDataDir(t)
+
+	pub, err := Append("old", "published", time.Time{})
+	require.NoError(t, err)
+	q, err := Append("queued", "queued", time.Time{})
+	require.NoError(t, err)
+
+	require.NoError(t, ClearPublished())
+	added, err := Append("added later", "queued", time.Time{})
+	require.NoError(t, err)
+
+	_, _, err = Undo("", week)
+	require.NoError(t, err)
+
+	entries, err := Load()
+	require.NoError(t, err)
+	require.Len(t, entries, 3)
+	assert.Equal(t, pub.ID, entries[0].ID)
+	assert.Equal(t, q.ID, entries[1].ID)
+	assert.Equal(t, added.ID, entries[2].ID)
+}
+
+func TestUndo_ByID_And_Skipped(t *testing.T) {
+	withTempDataDir(t)
+
+	a, err := Append("a", "queued", time.Time{})
+	require.NoError(t, err)
+	b, err := Append("b", "queued", time.Time{})
+	require.NoError(t, err)
+
+	_, err = Remove(a.ID)
+	require.NoError(t, err)
+	require.NoError(t, ClearAll()) // snapshot holds only b
+
+	snaps, err := Snapshots(week)
+	require.NoError(t, err)
+	require.Len(t, snaps, 2)
+	assert.Equal(t, "clear-all", snaps[0].Operation, "newest first")
+
+	// Restore the older remove snapshot by ID.
+	snap, _, err := Undo(snaps[1].ID, week)
+	require.NoError(t, err)
+	assert.Equal(t, a.ID, snap.Entries[0].Entry.ID)
+
+	// Restoring b twice: second time it is skipped.
+	require.NoError(t, appendRaw(t, b))
+	_, skipped, err := Undo("", week)
+	require.NoError(t, err)
+	assert.Equal(t, []string{b.ID}, skipped)
+
+	_, _, err = Undo("missing", week)
+	require.ErrorIs(t, err, ErrNoSnapshot)
+}
+
+// appendRaw writes e to history as-is, keeping its ID.
+func appendRaw(t *testing.T, e Entry) error {
+	t.Helper()
+	return withLock(func() error {
+		entries, err := loadFromDisk()
+		if err != nil {
+			return err
+		}
+		return atomicWrite(append(entries, e))
+	})
+}
+
+func TestUndo_TrimsToMaxEntries(t *testing.T) {
+	withTempDataDir(t)
+
+	for range maxEntries - 1 {
+		_, err := Append("published", "published", time.Time{})
+		require.NoError(t, err)
+	}
+	q, err := Append("queued", "queued", time.Time{})
+	require.NoError(t, err)
+	found, err := Remove(q.ID)
+	require.NoError(t, err)
+	require.True(t, found)
+	_, err = Append("newer", "published", time.Time{})
+	require.NoError(t, err)
+
+	_, _, err = Undo("", week)
+	require.NoError(t, err)
+
+	entries, err := Load()
+	require.NoError(t, err)
+	assert.Len(t, entries, maxEntries)
+	queued, err := Queued()
+	require.NoError(t, err)
+	require.Len(t, queued, 1)
+	assert.Equal(t, q.ID, queued[0].ID)
+
+	events, err := ReadAudit()
+	require.NoError(t, err)
+	assert.Equal(t, "evict", events[len(events)-1].Action)
+}
+
+func TestUndo_PublishingRestoredAsQueued(t *testing.T) {
+	withTempDataDir(t)
+
+	e, err := Append("in flight", "queued", time.Time{})
+	require.NoError(t, err)
+	_, err = ClaimNextReady()
+	require.NoError(t, err)
+	_, err = Remove(e.ID)
+	require.NoError(t, err)
+
+	_, _, err = Undo("", week)
+	require.NoError(t, err)
+	got, err := Get(e.ID)
+	require.NoError(t, err)
+	assert.Equal(t, "queued", got.Status)
+}
+
+func TestSnapshots_Expire(t *testing.T) {
+	withTempDataDir(t)
+
+	e, err := Append("a", "queued", time.Time{})
+	require.NoError(t, err)
+	_, err = Remove(e.ID)
+	require.NoError(t, err)
+
+	snaps, err := Snapshots(week)
+	require.NoError(t, err)
+	require.Len(t, snaps, 1)
+
+	// Backdate the snapshot past the retention window.
+	snaps[0].CreatedAt = time.Now().Add(-8 * 24 * time.Hour).UTC().Format(time.RFC3339)
+	require.NoError(t, writeSnapshots(snaps))
+
+	snaps, err = Snapshots(week)
+	require.NoError(t, err)
+	assert.Empty(t, snaps)
+	_, _, err = Undo("", week)
+	require.ErrorIs(t, err, ErrNoSnapshot)
+
+	data, err := os.ReadFile(undoPath())
+	require.NoError(t, err)
+	assert.JSONEq(t, "[]", string(data), "expired snapshots are deleted")
+}
+
+func TestRemove_NotFound_NoSnapshot(t *testing.T) {
+	withTempDataDir(t)
+
+	found, err := Remove("missing")
+	require.NoError(t, err)
+	assert.False(t, found)
+	require.NoError(t, ClearAll()) // empty history: nothing to snapshot
+
+	snaps, err := Snapshots(week)
+	require.NoError(t, err)
+	assert.Empty(t, snaps)
+}
@@ -4,6 +4,8 @@ import (
 	"errors"
 
 	gokeyring "github.com/zalando/go-keyring"
+
+	"github.com/lvrach/slack-social-ai/internal/paths"
 )
 
 // ErrNotFound is returned when no webhook URL is stored.
@@ -19,17 +21,26 @@ func IsNotFound(err error) bool {
 	return errors.Is(err, gokeyring.ErrNotFound)
 }
 
+// account returns the keychain account for the selected instance, so each
+// --profile/--home keeps its own webhook.
+func account() string {
+	if inst := paths.Instance(); inst != "" {
+		return userName + "@" + inst
+	}
+	return userName
+}
+
 // Get retrieves the stored webhook URL from the system keychain.
 func Get() (string, error) {
-	return gokeyring.Get(serviceName, userName)
+	return gokeyring.Get(serviceName, account())
 }
 
 // Set stores the webhook URL in the system keychain.
 func Set(url string) error {
-	return gokeyring.Set(serviceName, userName, url)
+	return gokeyring.Set(serviceName, account(), url)
 }
 
 // Delete removes the webhook URL from the system keychain.
 func Delete() error {
-	return gokeyring.Delete(serviceName, userName)
+	return gokeyring.Delete(serviceName, account())
 }
@@ -3,13 +3,17 @@ package launchd
 import (
 	"bytes"
 	"fmt"
+	"hash/fnv"
 	"os"
 	"os/exec"
 	"os/user"
 	"path/filepath"
 	"runtime"
+	"strings"
 
 	"howett.net/plist"
+
+	"github.com/lvrach/slack-social-ai/internal/paths"
 )
 
 const Label = "com.slack-social-ai.publish"
@@ -33,31 +37,46 @@ func defaultPlistDir() string {
 	return filepath.Join(home, "Library", "LaunchAgents")
 }
 
+// JobLabel returns the launchd label for the selected instance, so that each
+// --profile/--home instance gets its own job.
+func JobLabel() string {
+	instance := paths.Instance()
+	switch instance {
+	case "":
+		return Label
+	case paths.Profile():
+		return Label + "." + instance
+	default:
+		h := fnv.New32a()
+		_, _ = h.Write([]byte(instance))
+		return fmt.Sprintf("%s.%08x", Label, h.Sum32())
+	}
+}
+
 // PlistPath returns the path to the launchd plist file.
 func PlistPath() string {
-	return filepath.Join(plistDir(), Label+".plist")
+	return filepath.Join(plistDir(), JobLabel()+".plist")
 }
 
 // LogPath returns the path for publish command logs.
 func LogPath() string {
-	home, _ := os.UserHomeDir()
-	return filepath.Join(home, ".local", "share", "slack-social-ai", "publish.log")
+	return filepath.Join(paths.StateDir(), "publish.log")
 }
 
 // GeneratePlist creates the plist XML for the publishing schedule.
 func GeneratePlist(binaryPath string) ([]byte, error) {
 	home, _ := os.UserHomeDir()
+	env := paths.XDGEnv()
+	env["HOME"] = home
 
 	data := plistData{
-		Label:             Label,
-		ProgramArguments:  []string{binaryPath, "publish", "--json"},
-		StartInterval:     600, // 10 minutes
-		StandardOutPath:   LogPath(),
-		StandardErrorPath: LogPath(),
-		RunAtLoad:         false,
-		EnvironmentVariables: map[string]string{
-			"HOME": home,
-		},
+		Label:                JobLabel(),
+		ProgramArguments:     append(append([]string{binaryPath}, paths.Args()...), "publish", "--json"),
+		StartInterval:        600, // 10 minutes
+		StandardOutPath:      LogPath(),
+		StandardErrorPath:    LogPath(),
+		RunAtLoad:            false,
+		EnvironmentVariables: env,
 	}
 
 	var buf bytes.Buffer
@@ -79,8 +98,8 @@ func IsInstalled() bool {
 func Install(binaryPath string) error {
 	if runtime.GOOS != "darwin" {
 		return fmt.Errorf(
-			"automatic scheduling requires macOS (launchd). For Linux/other, set up a cron job manually:\n  */10 * * * * %s publish --json >> ~/.local/share/slack-social-ai/publish.log 2>&1",
-			binaryPath,
+			"automatic scheduling requires macOS (launchd). For Linux/other, set up a cron job manually:\n  */10 * * * * %s publish --json >> %s 2>&1",
+			strings.Join(append([]string{binaryPath}, paths.Args()...), " "), LogPath(),
 		)
 	}
 
@@ -99,7 +118,7 @@ func Install(binaryPath string) error {
 	// If already installed, bootout first (ignore errors — may not be loaded).
 	if IsInstalled() {
 		uid := currentUID()
-		_ = exec.Command("launchctl", "bootout", fmt.Sprintf("gui/%s/%s", uid, Label)).Run() //nolint:gosec // launchctl path constructed from constants
+		_ = exec.Command("launchctl", "bootout", fmt.Sprintf("gui/%s/%s", uid, JobLabel())).Run() //nolint:gosec // launchctl path constructed from constants
 	}
 
 	if err := os.WriteFile(path, plistBytes, 0o600); err != nil {
@@ -119,7 +138,7 @@ func Install(binaryPath string) error {
 func Uninstall() error {
 	uid := currentUID()
 	// Bootout first (ignore error if not loaded).
-	_ = exec.Command("launchctl", "bootout", fmt.Sprintf("gui/%s/%s", uid, Label)).Run() //nolint:gosec // launchctl path constructed from constants
+	_ = exec.Command("launchctl", "bootout", fmt.Sprintf("gui/%s/%s", uid, JobLabel())).Run() //nolint:gosec // launchctl path constructed from constants
 
 	path := PlistPath()
 	if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
@@ -131,7 +150,7 @@ func Uninstall() error {
 // IsLoaded checks if the service is currently loaded in launchctl.
 func IsLoaded() bool {
 	uid := currentUID()
-	err := exec.Command("launchctl", "print", fmt.Sprintf("gui/%s/%s", uid, Label)).Run() //nolint:gosec // launchctl path constructed from constants
+	err := exec.Command("launchctl", "print", fmt.Sprintf("gui/%s/%s", uid, JobLabel())).Run() //nolint:gosec // launchctl path constructed from constants
 	return err == nil
 }
 
@@ -5,6 +5,8 @@ import (
 	"path/filepath"
 	"strings"
 	"testing"
+
+	"github.com/lvrach/slack-social-ai/internal/paths"
 )
 
 func withTempPlistDir(t *testing.T) string {
@@ -64,6 +66,72 @@ func TestGeneratePlist(t *testing.T) {
 	}
 }
 
+func TestGeneratePlist_XDG(t *testing.T) {
+	t.Setenv("XDG_DATA_HOME", "/xdg/data")
+	t.Setenv("XDG_CONFIG_HOME", "/xdg/config")
+	t.Setenv("XDG_STATE_HOME", "")
+
+	plistBytes, err := GeneratePlist("/usr/local/bin/slack-social-ai")
+	if err != nil {
+		t.Fatalf("GeneratePlist() error = %v", err)
+	}
+	xml := string(plistBytes)
+
+	home, _ := os.UserHomeDir()
+	for _, want := range []string{
+		"<key>XDG_DATA_HOME</key>", "<string>/xdg/data</string>",
+		"<key>XDG_CONFIG_HOME</key>", "<string>/xdg/config</string>",
+		"<key>XDG_STATE_HOME</key>", "<string>" + filepath.Join(home, ".local", "state") + "</string>",
+	} {
+		if !strings.Contains(xml, want) {
+			t.Errorf("plist XML does not contain %q\n\nGot:\n%s", want, xml)
+		}
+	}
+}
+
+func TestGeneratePlist_Instance(t *testing.T) {
+	if err := paths.Set("/srv/team-a", "ci"); err != nil {
+		t.Fatalf("paths.Set() error = %v", err)
+	}
+	t.Cleanup(func() { _ = paths.Set("", "") })
+
+	plistBytes, err := GeneratePlist("/usr/local/bin/slack-social-ai")
+	if err != nil {
+		t.Fatalf("GeneratePlist() error = %v", err)
+	}
+	xml := string(plistBytes)
+
+	for _, want := range []string{
+		"<string>--home</string>",
+		"<string>/srv/team-a</string>",
+		"<string>--profile</string>",
+		"<string>ci</string>",
+		"<string>" + JobLabel() + "</string>",
+	} {
+		if !strings.Contains(xml, want) {
+			t.Errorf("plist XML does not contain %q\n\nGot:\n%s", want, xml)
+		}
+	}
+}
+
+func TestJobLabel(t *testing.T) {
+	t.Cleanup(func() { _ = paths.Set("", "") })
+
+	if got := JobLabel(); got != Label {
+		t.Errorf("JobLabel() = %q, want %q for the default instance", got, Label)
+	}
+	_ = paths.Set("", "team-a")
+	if got, want := JobLabel(), Label+".team-a"; got != want {
+		t.Errorf("JobLabel() = %q, want %q", got, want)
+	}
+	_ = paths.Set("/srv/team-a", "")
+	first := JobLabel()
+	_ = paths.Set("/srv/team-b", "")
+	if second := JobLabel(); first == second || !strings.HasPrefix(second, Label+".") {
+		t.Errorf("JobLabel() for distinct homes = %q and %q, want distinct suffixed labels", first, second)
+	}
+}
+
 func TestIsInstalled_True(t *testing.T) {
 	dir := withTempPlistDir(t)
 
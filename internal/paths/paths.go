@@ -0,0 +1,254 @@
+// Package paths resolves where slack-social-ai keeps its data, config, and
+// state, following the XDG base directory spec with optional per-instance
+// overrides.
+package paths
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+)
+
+const appName = "slack-social-ai"
+
+// home and profile select an isolated instance; both empty means the
+// default XDG locations. Set once at startup via Set.
+var home, profile string
+
+var validProfile = regexp.MustCompile(`^[a-z0-9][a-z0-9_-]*$`)
+
+// Set selects an instance. homeDir, when non-empty, keeps data, config, and
+// state together in that directory. profileName, when non-empty, isolates a
+// named instance under profiles/<name> of whichever base is in use.
+func Set(homeDir, profileName string) error {
+	if profileName != "" && !isValidProfile(profileName) {
+		return fmt.Errorf("invalid profile %q: use lowercase letters, digits, '-' and '_'", profileName)
+	}
+	if homeDir != "" {
+		abs, err := filepath.Abs(homeDir)
+		if err != nil {
+			return fmt.Errorf("resolve home %q: %w", homeDir, err)
+		}
+		homeDir = abs
+	}
+	home, profile = homeDir, profileName
+	return nil
+}
+
+func isValidProfile(name string) bool { return validProfile.MatchString(name) }
+
+// Profile returns the selected profile name, or "" for the default instance.
+func Profile() string { return profile }
+
+// Instance returns a key identifying the selected instance, or "" for the
+// default one. It is used to keep per-instance secrets apart.
+func Instance() string {
+	switch {
+	case home != "" && profile != "":
+		return home + "#" + profile
+	case home != "":
+		return home
+	default:
+		return profile
+	}
+}
+
+// Args returns the global flags that select the current instance, so that
+// spawned processes (e.g. the scheduler) use the same one.
+func Args() []string {
+	var args []string
+	if home != "" {
+		args = append(args, "--home", home)
+	}
+	if profile != "" {
+		args = append(args, "--profile", profile)
+	}
+	return args
+}
+
+// DataDir holds history and its audit and undo files.
+// Default: $XDG_DATA_HOME/slack-social-ai (~/.local/share/slack-social-ai).
+func DataDir() string { return dir("XDG_DATA_HOME", ".local", "share") }
+
+// ConfigDir holds config.json.
+// Default: $XDG_CONFIG_HOME/slack-social-ai (~/.config/slack-social-ai).
+func ConfigDir() string { return dir("XDG_CONFIG_HOME", ".config") }
+
+// StateDir holds logs.
+// Default: $XDG_STATE_HOME/slack-social-ai (~/.local/state/slack-social-ai).
+func StateDir() string { return dir("XDG_STATE_HOME", ".local", "state") }
+
+// XDGEnv returns the resolved XDG base directories, so that spawned
+// processes with a different environment (e.g. the scheduler) see the same
+// ones.
+func XDGEnv() map[string]string {
+	return map[string]string{
+		"XDG_DATA_HOME":   xdgBase("XDG_DATA_HOME", ".local", "share"),
+		"XDG_CONFIG_HOME": xdgBase("XDG_CONFIG_HOME", ".config"),
+		"XDG_STATE_HOME":  xdgBase("XDG_STATE_HOME", ".local", "state"),
+	}
+}
+
+func dir(env string, fallback ...string) string {
+	base := home
+	if base == "" {
+		base = filepath.Join(xdgBase(env, fallback...), appName)
+	}
+	if profile != "" {
+		base = filepath.Join(base, "profiles", profile)
+	}
+	return base
+}
+
+// xdgBase returns $env if it is an absolute path (relative values are
+// invalid per the spec), otherwise $HOME joined with fallback.
+func xdgBase(env string, fallback ...string) string {
+	if v := os.Getenv(env); filepath.IsAbs(v) {
+		return v
+	}
+	userHome, _ := os.UserHomeDir()
+	return filepath.Join(append([]string{userHome}, fallback...)...)
+}
+
+// legacyDataDir and legacyConfigDir are the locations used before XDG
+// variables were honoured.
+func legacyDataDir() string {
+	userHome, _ := os.UserHomeDir()
+	return filepath.Join(userHome, ".local", "share", appName)
+}
+
+func legacyConfigDir() string {
+	userHome, _ := os.UserHomeDir()
+	return filepath.Join(userHome, ".config", appName)
+}
+
+// lockFiles are never migrated: a lock file belongs to whichever process
+// holds it, and moving it would let another process lock a new file at the
+// old path at the same time.
+var lockFiles = map[string]bool{"history.lock": true, "history.lock.json": true}
+
+// migration moves files from one directory to another.
+type migration struct {
+	from, to string
+	files    []string // nil = every regular file in from
+}
+
+// migrations returns the moves Migrate makes, in order. Only the default
+// instance has legacy files to migrate.
+func migrations() []migration {
+	if home != "" || profile != "" {
+		return nil
+	}
+	return []migration{
+		// The log moves straight to the state dir before the bulk data move.
+		{from: legacyDataDir(), to: StateDir(), files: []string{"publish.log"}},
+		{from: DataDir(), to: StateDir(), files: []string{"publish.log"}},
+		{from: legacyDataDir(), to: DataDir(), files: nil},
+		{from: legacyConfigDir(), to: ConfigDir(), files: nil},
+	}
+}
+
+// names returns the files m would move.
+func (m migration) names() ([]string, error) {
+	if m.from == m.to {
+		return nil, nil
+	}
+	if m.files != nil {
+		return m.files, nil
+	}
+	return regularFiles(m.from)
+}
+
+// Pending reports whether Migrate has anything to move, so callers can skip
+// taking locks for it.
+func Pending() bool {
+	for _, m := range migrations() {
+		names, _ := m.names()
+		for _, name := range names {
+			if exists(filepath.Join(m.from, name)) && !exists(filepath.Join(m.to, name)) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func exists(path string) bool {
+	_, err := os.Stat(path)
+	return err == nil
+}
+
+// Migrate moves files left at pre-XDG locations into the current ones:
+// data and config from ~/.local/share and ~/.config when XDG variables point
+// elsewhere, and publish.log from the data dir to the state dir. Files that
+// already exist at the destination are left alone, and lock files stay
+// where they are. It returns the new paths of moved files.
+//
+// Callers must hold the history lock, since history files move.
+func Migrate() ([]string, error) {
+	var moved []string
+	for _, m := range migrations() {
+		if m.from == m.to {
+			continue
+		}
+		names, err := m.names()
+		if err != nil {
+			return moved, err
+		}
+		for _, name := range names {
+			ok, err := moveFile(filepath.Join(m.from, name), filepath.Join(m.to, name))
+			if err != nil {
+				return moved, err
+			}
+			if ok {
+				moved = append(moved, filepath.Join(m.to, name))
+			}
+		}
+		_ = os.Remove(m.from) // only succeeds once the legacy dir is empty
+	}
+	return moved, nil
+}
+
+func regularFiles(dir string) ([]string, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	var names []string
+	for _, e := range entries {
+		if e.Type().IsRegular() && !lockFiles[e.Name()] {
+			names = append(names, e.Name())
+		}
+	}
+	return names, nil
+}
+
+// moveFile renames from to to unless from is missing or to already exists.
+// It reports whether the file was moved.
+func moveFile(from, to string) (bool, error) {
+	if _, err := os.Stat(from); err != nil {
+		return false, nil
+	}
+	if _, err := os.Stat(to); err == nil {
+		return false, nil
+	}
+	if err := os.MkdirAll(filepath.Dir(to), 0o700); err != nil {
+		return false, fmt.Errorf("create %s: %w", filepath.Dir(to), err)
+	}
+	if err := os.Rename(from, to); err != nil {
+		// Rename fails across filesystems; fall back to copy and remove.
+		data, readErr := os.ReadFile(from) //nolint:gosec // path built from our own directories
+		if readErr != nil {
+			return false, fmt.Errorf("move %s: %w", from, err)
+		}
+		if err := os.WriteFile(to, data, 0o600); err != nil {
+			return false, fmt.Errorf("move %s: %w", from, err)
+		}
+		_ = os.Remove(from)
+	}
+	return true, nil
+}
@@ -0,0 +1,208 @@
+package paths
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// withInstance sets the instance for one test and restores the default.
+func withInstance(t *testing.T, homeDir, profileName string) {
+	t.Helper()
+	if err := Set(homeDir, profileName); err != nil {
+		t.Fatalf("Set(%q, %q) error = %v", homeDir, profileName, err)
+	}
+	t.Cleanup(func() { home, profile = "", "" })
+}
+
+func withUserHome(t *testing.T) string {
+	t.Helper()
+	dir := t.TempDir()
+	t.Setenv("HOME", dir)
+	t.Setenv("XDG_DATA_HOME", "")
+	t.Setenv("XDG_CONFIG_HOME", "")
+	t.Setenv("XDG_STATE_HOME", "")
+	return dir
+}
+
+func TestDirs_Defaults(t *testing.T) {
+	userHome := withUserHome(t)
+
+	tests := []struct {
+		name string
+		got  string
+		want string
+	}{
+		{"data", DataDir(), filepath.Join(userHome, ".local", "share", "slack-social-ai")},
+		{"config", ConfigDir(), filepath.Join(userHome, ".config", "slack-social-ai")},
+		{"state", StateDir(), filepath.Join(userHome, ".local", "state", "slack-social-ai")},
+	}
+	for _, tt := range tests {
+		if tt.got != tt.want {
+			t.Errorf("%s dir = %q, want %q", tt.name, tt.got, tt.want)
+		}
+	}
+}
+
+func TestDirs_XDG(t *testing.T) {
+	withUserHome(t)
+	t.Setenv("XDG_DATA_HOME", "/xdg/data")
+	t.Setenv("XDG_CONFIG_HOME", "/xdg/config")
+	t.Setenv("XDG_STATE_HOME", "relative/ignored")
+
+	if got := DataDir(); got != "/xdg/data/slack-social-ai" {
+		t.Errorf("DataDir() = %q", got)
+	}
+	if got := ConfigDir(); got != "/xdg/config/slack-social-ai" {
+		t.Errorf("ConfigDir() = %q", got)
+	}
+	if got := StateDir(); filepath.Base(filepath.Dir(got)) != "state" {
+		t.Errorf("StateDir() = %q, want relative XDG_STATE_HOME ignored", got)
+	}
+}
+
+func TestDirs_Profile(t *testing.T) {
+	withUserHome(t)
+	t.Setenv("XDG_DATA_HOME", "/xdg/data")
+	withInstance(t, "", "team-a")
+
+	if got, want := DataDir(), "/xdg/data/slack-social-ai/profiles/team-a"; got != want {
+		t.Errorf("DataDir() = %q, want %q", got, want)
+	}
+	if got := Instance(); got != "team-a" {
+		t.Errorf("Instance() = %q, want %q", got, "team-a")
+	}
+}
+
+func TestDirs_Home(t *testing.T) {
+	withUserHome(t)
+	dir := t.TempDir()
+	withInstance(t, dir, "")
+
+	for _, got := range []string{DataDir(), ConfigDir(), StateDir()} {
+		if got != dir {
+			t.Errorf("dir = %q, want %q", got, dir)
+		}
+	}
+	args := Args()
+	if len(args) != 2 || args[0] != "--home" || args[1] != dir {
+		t.Errorf("Args() = %v", args)
+	}
+}
+
+func TestSet_InvalidProfile(t *testing.T) {
+	for _, name := range []string{"Team", "../x", "a b", "-x"} {
+		if err := Set("", name); err == nil {
+			t.Errorf("Set(%q) = nil, want error", name)
+		}
+	}
+	if home != "" || profile != "" {
+		t.Errorf("failed Set changed the instance")
+	}
+}
+
+func TestMigrate(t *testing.T) {
+	userHome := withUserHome(t)
+	xdg := t.TempDir()
+	t.Setenv("XDG_DATA_HOME", filepath.Join(xdg, "data"))
+	t.Setenv("XDG_CONFIG_HOME", filepath.Join(xdg, "config"))
+	t.Setenv("XDG_STATE_HOME", filepath.Join(xdg, "state"))
+
+	legacyData := filepath.Join(userHome, ".local", "share", "slack-social-ai")
+	legacyConfig := filepath.Join(userHome, ".config", "slack-social-ai")
+	writeFile(t, filepath.Join(legacyData, "history.json"), "[]")
+	writeFile(t, filepath.Join(legacyData, "publish.log"), "log")
+	writeFile(t, filepath.Join(legacyConfig, "config.json"), "{}")
+	// An existing file at the destination wins.
+	writeFile(t, filepath.Join(ConfigDir(), "config.json"), `{"new":true}`)
+
+	moved, err := Migrate()
+	if err != nil {
+		t.Fatalf("Migrate() error = %v", err)
+	}
+	if len(moved) != 2 {
+		t.Errorf("moved = %v, want history.json and publish.log", moved)
+	}
+
+	assertFile(t, filepath.Join(DataDir(), "history.json"), "[]")
+	assertFile(t, filepath.Join(StateDir(), "publish.log"), "log")
+	assertFile(t, filepath.Join(ConfigDir(), "config.json"), `{"new":true}`)
+	if _, err := os.Stat(legacyData); !os.IsNotExist(err) {
+		t.Errorf("legacy data dir should be removed once empty")
+	}
+	assertFile(t, filepath.Join(legacyConfig, "config.json"), "{}")
+
+	// Running again is a no-op.
+	if moved, err := Migrate(); err != nil || len(moved) != 0 {
+		t.Errorf("second Migrate() = %v, %v; want nothing moved", moved, err)
+	}
+}
+
+func TestMigrate_LogToStateDir(t *testing.T) {
+	withUserHome(t)
+	writeFile(t, filepath.Join(DataDir(), "publish.log"), "log")
+	writeFile(t, filepath.Join(DataDir(), "history.json"), "[]")
+
+	if _, err := Migrate(); err != nil {
+		t.Fatalf("Migrate() error = %v", err)
+	}
+	assertFile(t, filepath.Join(StateDir(), "publish.log"), "log")
+	assertFile(t, filepath.Join(DataDir(), "history.json"), "[]")
+}
+
+func TestMigrate_LeavesLockFiles(t *testing.T) {
+	userHome := withUserHome(t)
+	t.Setenv("XDG_DATA_HOME", filepath.Join(t.TempDir(), "data"))
+	legacyData := filepath.Join(userHome, ".local", "share", "slack-social-ai")
+	writeFile(t, filepath.Join(legacyData, "history.json"), "[]")
+	writeFile(t, filepath.Join(legacyData, "history.lock"), "")
+	writeFile(t, filepath.Join(legacyData, "history.lock.json"), "{}")
+
+	if !Pending() {
+		t.Fatal("Pending() = false, want true before migrating")
+	}
+	if _, err := Migrate(); err != nil {
+		t.Fatalf("Migrate() error = %v", err)
+	}
+	assertFile(t, filepath.Join(DataDir(), "history.json"), "[]")
+	assertFile(t, filepath.Join(legacyData, "history.lock"), "")
+	assertFile(t, filepath.Join(legacyData, "history.lock.json"), "{}")
+	if _, err := os.Stat(filepath.Join(DataDir(), "history.lock")); !os.IsNotExist(err) {
+		t.Errorf("history.lock should not be moved")
+	}
+	if Pending() {
+		t.Error("Pending() = true, want false once migrated")
+	}
+}
+
+func TestMigrate_SkippedForProfiles(t *testing.T) {
+	withUserHome(t)
+	withInstance(t, "", "team-a")
+	writeFile(t, filepath.Join(legacyDataDir(), "history.json"), "[]")
+
+	if moved, err := Migrate(); err != nil || len(moved) != 0 {
+		t.Errorf("Migrate() = %v, %v; want nothing moved for a profile", moved, err)
+	}
+}
+
+func writeFile(t *testing.T, path, content string) {
+	t.Helper()
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func assertFile(t *testing.T, path, want string) {
+	t.Helper()
+	data, err := os.ReadFile(path) //nolint:gosec // test path
+	if err != nil {
+		t.Errorf("read %s: %v", path, err)
+		return
+	}
+	if string(data) != want {
+		t.Errorf("%s = %q, want %q", path, data, want)
+	}
+}
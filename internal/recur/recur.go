@@ -0,0 +1,217 @@
+// Package recur parses recurrence rules for repeating posts and computes
+// their occurrences.
+//
+// A rule is either a day list and a time ("mon 10:00", "mon,thu 09:30",
+// "mon-fri 16:00", "daily 08:45", "weekdays 12:00") or a standard 5-field
+// cron expression ("0 10 * * 1"). Occurrences are computed in the location
+// of the time passed to Next.
+package recur
+
+import (
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Rule is a parsed recurrence. The zero Rule never occurs.
+type Rule struct {
+	spec    string
+	minutes [60]bool
+	hours   [24]bool
+	doms    [32]bool // 1-31
+	months  [13]bool // 1-12
+	dows    [7]bool  // 0 = Sunday
+	// domAll and dowAll record unrestricted day fields; cron matches either
+	// day field when both are restricted.
+	domAll, dowAll bool
+}
+
+// String returns the spec the rule was parsed from.
+func (r Rule) String() string { return r.spec }
+
+var dayNames = []string{"sun", "mon", "tue", "wed", "thu", "fri", "sat"}
+
+// Parse parses a day list and time or a 5-field cron expression.
+func Parse(spec string) (Rule, error) {
+	spec = strings.Join(strings.Fields(strings.ToLower(spec)), " ")
+	fields := strings.Fields(spec)
+	switch len(fields) {
+	case 2:
+		return parseDaysAt(spec, fields[0], fields[1])
+	case 5:
+		return parseCron(spec, fields)
+	}
+	return Rule{}, fmt.Errorf("invalid recurrence %q: expected \"<days> HH:MM\" (e.g. \"mon 10:00\") or a 5-field cron expression", spec)
+}
+
+func parseDaysAt(spec, days, at string) (Rule, error) {
+	r := Rule{spec: spec, domAll: true}
+	hh, mm, ok := strings.Cut(at, ":")
+	hour, herr := strconv.Atoi(hh)
+	minute, merr := strconv.Atoi(mm)
+	if !ok || herr != nil || merr != nil || hour < 0 || hour > 23 || minute < 0 || minute > 59 {
+		return Rule{}, fmt.Errorf("invalid recurrence time %q: expected HH:MM", at)
+	}
+	r.hours[hour], r.minutes[minute] = true, true
+	for m := 1; m <= 12; m++ {
+		r.months[m] = true
+	}
+	for d := 1; d <= 31; d++ {
+		r.doms[d] = true
+	}
+
+	switch days {
+	case "daily":
+		days = "sun-sat"
+	case "weekdays":
+		days = "mon-fri"
+	case "weekends":
+		days = "sat,sun"
+	}
+	if err := setField(r.dows[:], days, 0, 6, dayNames); err != nil {
+		return Rule{}, fmt.Errorf("invalid recurrence days %q: %w", days, err)
+	}
+	r.dowAll = !slices.Contains(r.dows[:], false)
+	return r, nil
+}
+
+func parseCron(spec string, fields []string) (Rule, error) {
+	r := Rule{spec: spec}
+	// Cron allows 7 for Sunday; parse into 8 slots and fold it onto 0.
+	var dows [8]bool
+	for _, f := range []struct {
+		name     string
+		expr     string
+		dst      []bool
+		min, max int
+		names    []string
+	}{
+		{"minute", fields[0], r.minutes[:], 0, 59, nil},
+		{"hour", fields[1], r.hours[:], 0, 23, nil},
+		{"day of month", fields[2], r.doms[:], 1, 31, nil},
+		{"month", fields[3], r.months[:], 1, 12, nil},
+		{"day of week", fields[4], dows[:], 0, 7, dayNames},
+	} {
+		if err := setField(f.dst, f.expr, f.min, f.max, f.names); err != nil {
+			return Rule{}, fmt.Errorf("invalid cron %s %q: %w", f.name, f.expr, err)
+		}
+	}
+	copy(r.dows[:], dows[:7])
+	r.dows[0] = r.dows[0] || dows[7]
+	r.domAll = fields[2] == "*"
+	r.dowAll = fields[4] == "*"
+	return r, nil
+}
+
+// setField marks the values matched by a cron-style field: "*", "N",
+// "A-B", any of those with "/step", and comma-separated lists. names, if
+// set, are accepted in place of numbers starting at min.
+func setField(dst []bool, expr string, minVal, maxVal int, names []string) error {
+	for part := range strings.SplitSeq(expr, ",") {
+		rng, stepStr, hasStep := strings.Cut(part, "/")
+		step := 1
+		if hasStep {
+			var err error
+			if step, err = strconv.Atoi(stepStr); err != nil || step < 1 {
+				return fmt.Errorf("invalid step %q", stepStr)
+			}
+		}
+
+		lo, hi := minVal, maxVal
+		if rng != "*" {
+			a, b, isRange := strings.Cut(rng, "-")
+			var err error
+			if lo, err = fieldValue(a, minVal, maxVal, names); err != nil {
+				return err
+			}
+			hi = lo
+			if isRange {
+				if hi, err = fieldValue(b, minVal, maxVal, names); err != nil {
+					return err
+				}
+			} else if hasStep {
+				hi = maxVal
+			}
+			if lo > hi {
+				return fmt.Errorf("range %q runs backwards", rng)
+			}
+		}
+		for v := lo; v <= hi; v += step {
+			dst[v] = true
+		}
+	}
+	return nil
+}
+
+func fieldValue(s string, minVal, maxVal int, names []string) (int, error) {
+	if i := slices.Index(names, s); i != -1 {
+		return minVal + i, nil
+	}
+	v, err := strconv.Atoi(s)
+	if err != nil || v < minVal || v > maxVal {
+		return 0, fmt.Errorf("value %q out of range %d-%d", s, minVal, maxVal)
+	}
+	return v, nil
+}
+
+// maxSearchDays bounds the search for the next occurrence; rules such as
+// "0 0 30 2 *" never occur.
+const maxSearchDays = 5 * 366
+
+// Next returns the first occurrence strictly after t, in t's location, or
+// the zero time if the rule never occurs.
+func (r Rule) Next(t time.Time) time.Time {
+	loc := t.Location()
+	t = time.Date(t.Year(), t.Month(), t.Day(), t.Hour(), t.Minute(), 0, 0, loc).Add(time.Minute)
+	limit := t.AddDate(0, 0, maxSearchDays)
+	for t.Before(limit) {
+		if !r.months[t.Month()] || !r.dayMatches(t) {
+			t = time.Date(t.Year(), t.Month(), t.Day()+1, 0, 0, 0, 0, loc)
+			continue
+		}
+		if !r.hours[t.Hour()] {
+			t = time.Date(t.Year(), t.Month(), t.Day(), t.Hour()+1, 0, 0, 0, loc)
+			continue
+		}
+		if !r.minutes[t.Minute()] {
+			t = t.Add(time.Minute)
+			continue
+		}
+		return t
+	}
+	return time.Time{}
+}
+
+func (r Rule) dayMatches(t time.Time) bool {
+	dom, dow := r.doms[t.Day()], r.dows[t.Weekday()]
+	switch {
+	case r.domAll && r.dowAll:
+		return true
+	case r.domAll:
+		return dow
+	case r.dowAll:
+		return dom
+	default:
+		return dom || dow
+	}
+}
+
+// Variables lists the placeholders Render replaces.
+var Variables = []string{"{{date}}", "{{weekday}}", "{{week}}", "{{month}}", "{{year}}", "{{n}}"}
+
+// Render fills template placeholders for an occurrence at t: {{date}}
+// (2006-01-02), {{weekday}} (Monday), {{week}} (ISO week number), {{month}}
+// (January), {{year}}, and {{n}} (1-based occurrence count).
+func Render(template string, t time.Time, n int) string {
+	_, week := t.ISOWeek()
+	return strings.NewReplacer(
+		"{{date}}", t.Format("2006-01-02"),
+		"{{weekday}}", t.Weekday().String(),
+		"{{week}}", strconv.Itoa(week),
+		"{{month}}", t.Month().String(),
+		"{{year}}", strconv.Itoa(t.Year()),
+		"{{n}}", strconv.Itoa(n),
+	).Replace(template)
+}
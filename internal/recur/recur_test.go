@@ -0,0 +1,95 @@
+package recur
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// 2026-03-04 is a Wednesday.
+var base = time.Date(2026, 3, 4, 12, 0, 0, 0, time.UTC)
+
+func TestNext_DaysAt(t *testing.T) {
+	tests := []struct {
+		spec string
+		from time.Time
+		want time.Time
+	}{
+		{"mon 10:00", base, time.Date(2026, 3, 9, 10, 0, 0, 0, time.UTC)},
+		{"wed 12:00", base, time.Date(2026, 3, 11, 12, 0, 0, 0, time.UTC)}, // strictly after
+		{"wed 12:01", base, time.Date(2026, 3, 4, 12, 1, 0, 0, time.UTC)},
+		{"mon,fri 16:30", base, time.Date(2026, 3, 6, 16, 30, 0, 0, time.UTC)},
+		{"mon-fri 09:00", base, time.Date(2026, 3, 5, 9, 0, 0, 0, time.UTC)},
+		{"daily 08:45", base, time.Date(2026, 3, 5, 8, 45, 0, 0, time.UTC)},
+		{"weekends 11:00", base, time.Date(2026, 3, 7, 11, 0, 0, 0, time.UTC)},
+		{"  FRI   17:00 ", base, time.Date(2026, 3, 6, 17, 0, 0, 0, time.UTC)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.spec, func(t *testing.T) {
+			r, err := Parse(tt.spec)
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, r.Next(tt.from))
+		})
+	}
+}
+
+func TestNext_Cron(t *testing.T) {
+	tests := []struct {
+		spec string
+		want time.Time
+	}{
+		{"0 10 * * 1", time.Date(2026, 3, 9, 10, 0, 0, 0, time.UTC)},
+		{"30 9 * * mon-fri", time.Date(2026, 3, 5, 9, 30, 0, 0, time.UTC)},
+		{"*/15 * * * *", time.Date(2026, 3, 4, 12, 15, 0, 0, time.UTC)},
+		{"0 9 1 * *", time.Date(2026, 4, 1, 9, 0, 0, 0, time.UTC)},
+		{"0 9 * * 7", time.Date(2026, 3, 8, 9, 0, 0, 0, time.UTC)}, // 7 = Sunday
+		{"0 0 1 6 *", time.Date(2026, 6, 1, 0, 0, 0, 0, time.UTC)},
+		// Both day fields restricted: either matches (the 5th, or any Monday).
+		{"0 9 5 * 1", time.Date(2026, 3, 5, 9, 0, 0, 0, time.UTC)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.spec, func(t *testing.T) {
+			r, err := Parse(tt.spec)
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, r.Next(base))
+		})
+	}
+}
+
+func TestNext_Never(t *testing.T) {
+	r, err := Parse("0 0 30 2 *")
+	require.NoError(t, err)
+	assert.True(t, r.Next(base).IsZero())
+}
+
+func TestNext_Location(t *testing.T) {
+	loc := time.FixedZone("UTC+2", 2*60*60)
+	r, err := Parse("mon 10:00")
+	require.NoError(t, err)
+	got := r.Next(base.In(loc))
+	assert.Equal(t, time.Date(2026, 3, 9, 10, 0, 0, 0, loc), got)
+}
+
+func TestParse_Invalid(t *testing.T) {
+	for _, spec := range []string{
+		"", "mon", "monday 10:00", "mon 25:00", "mon 10", "mon 10:60",
+		"* * * *", "60 * * * *", "* * * 13 *", "* * * * 8", "5-1 * * * *", "*/0 * * * *",
+	} {
+		_, err := Parse(spec)
+		assert.Error(t, err, "spec %q", spec)
+	}
+}
+
+func TestRule_String(t *testing.T) {
+	r, err := Parse("Mon  10:00")
+	require.NoError(t, err)
+	assert.Equal(t, "mon 10:00", r.String())
+}
+
+func TestRender(t *testing.T) {
+	at := time.Date(2026, 3, 6, 16, 0, 0, 0, time.UTC)
+	got := Render("Week {{week}} ({{weekday}} {{date}}, {{month}} {{year}}) #{{n}}: what did we learn? {{unknown}}", at, 3)
+	assert.Equal(t, "Week 10 (Friday 2026-03-06, March 2026) #3: what did we learn? {{unknown}}", got)
+}
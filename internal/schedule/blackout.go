@@ -0,0 +1,86 @@
+package schedule
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+)
+
+// Blackout is a range of days on which nothing publishes, such as a public
+// holiday or a company shutdown. Dates are YYYY-MM-DD in the schedule's
+// time zone; To is inclusive.
+type Blackout struct {
+	From string `json:"from"`
+	To   string `json:"to"`
+	Name string `json:"name,omitempty"`
+
+	// Source is the calendar file the blackout was imported from; empty
+	// for blackouts added by hand. Re-importing a file replaces them.
+	Source string `json:"source,omitempty"`
+}
+
+// NewBlackout returns a blackout from from to to (YYYY-MM-DD, inclusive).
+// An empty to makes it a single day.
+func NewBlackout(from, to, name string) (Blackout, error) {
+	if to == "" {
+		to = from
+	}
+	for _, d := range []string{from, to} {
+		if _, err := time.Parse(time.DateOnly, d); err != nil {
+			return Blackout{}, fmt.Errorf("invalid date %q, expected YYYY-MM-DD", d)
+		}
+	}
+	if to < from {
+		return Blackout{}, fmt.Errorf("end %s is before start %s", to, from)
+	}
+	return Blackout{From: from, To: to, Name: name}, nil
+}
+
+func (b Blackout) String() string {
+	s := b.From
+	if b.To != b.From {
+		s += " to " + b.To
+	}
+	if b.Name != "" {
+		s += " (" + b.Name + ")"
+	}
+	return s
+}
+
+// Covers reports whether the blackout includes date (YYYY-MM-DD).
+func (b Blackout) Covers(date string) bool {
+	return b.From <= date && date <= b.To
+}
+
+// BlackoutAt returns the blackout covering the day of t, in the schedule's
+// time zone, if there is one.
+func (s Schedule) BlackoutAt(t time.Time) (Blackout, bool) {
+	date := s.in(t).Format(time.DateOnly)
+	i := slices.IndexFunc(s.Blackouts, func(b Blackout) bool { return b.Covers(date) })
+	if i == -1 {
+		return Blackout{}, false
+	}
+	return s.Blackouts[i], true
+}
+
+// blackoutEnd returns the last day of the blackout covering day (midnight
+// in day's zone), if there is one.
+func (s Schedule) blackoutEnd(day time.Time) (time.Time, bool) {
+	b, ok := s.BlackoutAt(day)
+	if !ok {
+		return time.Time{}, false
+	}
+	end, err := time.ParseInLocation(time.DateOnly, b.To, day.Location())
+	if err != nil {
+		return time.Time{}, false
+	}
+	return end, true
+}
+
+// SortBlackouts orders blackouts by start date, then end date.
+func SortBlackouts(blackouts []Blackout) {
+	slices.SortStableFunc(blackouts, func(a, b Blackout) int {
+		return strings.Compare(a.From+a.To, b.From+b.To)
+	})
+}
@@ -0,0 +1,116 @@
+package schedule
+
+import (
+	"testing"
+	"time"
+)
+
+func TestNewBlackout(t *testing.T) {
+	b, err := NewBlackout("2026-12-24", "", "Christmas Eve")
+	if err != nil {
+		t.Fatalf("NewBlackout() error = %v", err)
+	}
+	if b.From != "2026-12-24" || b.To != "2026-12-24" {
+		t.Errorf("single day = %+v, want From = To = 2026-12-24", b)
+	}
+	if got := b.String(); got != "2026-12-24 (Christmas Eve)" {
+		t.Errorf("String() = %q", got)
+	}
+
+	for _, tt := range [][2]string{
+		{"2026-12-32", ""},
+		{"24.12.2026", ""},
+		{"2026-12-26", "2026-12-24"},
+	} {
+		if _, err := NewBlackout(tt[0], tt[1], ""); err == nil {
+			t.Errorf("NewBlackout(%q, %q) succeeded, want error", tt[0], tt[1])
+		}
+	}
+}
+
+func TestIsActiveAt_Blackout(t *testing.T) {
+	s := DefaultSchedule() // 9-17 mon-fri
+	s.Blackouts = []Blackout{{From: "2026-12-24", To: "2026-12-25"}}
+
+	tests := []struct {
+		name string
+		t    time.Time
+		want bool
+	}{
+		{"day before", time.Date(2026, 12, 23, 10, 0, 0, 0, time.UTC), true},
+		{"first day", time.Date(2026, 12, 24, 10, 0, 0, 0, time.UTC), false},
+		{"last day", time.Date(2026, 12, 25, 16, 59, 0, 0, time.UTC), false},
+		{"day after", time.Date(2026, 12, 28, 10, 0, 0, 0, time.UTC), true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := s.IsActiveAt(tt.t); got != tt.want {
+				t.Errorf("IsActiveAt(%v) = %v, want %v", tt.t, got, tt.want)
+			}
+		})
+	}
+
+	// Blackout days are in the schedule's zone: 23:30 UTC on the 23rd is
+	// already the 24th in Berlin.
+	s.Windows = SameWindows(Weekdays, Window{Start: 0, End: At(24, 0)})
+	s.Timezone = "Europe/Berlin"
+	if s.IsActiveAt(time.Date(2026, 12, 23, 23, 30, 0, 0, time.UTC)) {
+		t.Error("IsActiveAt(23:30 UTC, 00:30 Berlin on the 24th) = true, want false")
+	}
+}
+
+func TestAdvanceToActive_Blackout(t *testing.T) {
+	sched := DefaultSchedule() // 9-17 mon-fri
+
+	tests := []struct {
+		name      string
+		blackouts []Blackout
+		t         time.Time
+		want      time.Time
+	}{
+		{
+			name:      "single holiday",
+			blackouts: []Blackout{{From: "2026-12-24", To: "2026-12-24"}},
+			t:         time.Date(2026, 12, 23, 18, 0, 0, 0, time.UTC), // Wednesday evening
+			want:      time.Date(2026, 12, 25, 9, 0, 0, 0, time.UTC),  // Friday 09:00
+		},
+		{
+			// Longer than the 14-day scan; ends on a Wednesday.
+			name:      "end-of-year shutdown",
+			blackouts: []Blackout{{From: "2026-12-18", To: "2027-01-06"}},
+			t:         time.Date(2026, 12, 18, 10, 0, 0, 0, time.UTC), // Friday, first day
+			want:      time.Date(2027, 1, 7, 9, 0, 0, 0, time.UTC),    // Thursday 09:00
+		},
+		{
+			name: "back-to-back and overlapping",
+			blackouts: []Blackout{
+				{From: "2026-12-01", To: "2026-12-20"},
+				{From: "2026-12-15", To: "2027-01-10"},
+				{From: "2027-01-11", To: "2027-01-11"},
+			},
+			t:    time.Date(2026, 11, 30, 18, 0, 0, 0, time.UTC), // Monday evening
+			want: time.Date(2027, 1, 12, 9, 0, 0, 0, time.UTC),   // Tuesday 09:00
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sched.Blackouts = tt.blackouts
+			got := AdvanceToActive(tt.t, sched)
+			if !got.Equal(tt.want) {
+				t.Errorf("AdvanceToActive(%v) = %v, want %v", tt.t, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestAdvanceToActive_NeverActive(t *testing.T) {
+	sched := Schedule{Blackouts: []Blackout{{From: "2026-01-01", To: "2026-01-31"}}}
+	start := time.Date(2026, 1, 10, 12, 0, 0, 0, time.UTC)
+
+	// No windows at all: past the blackout, give up after the scan.
+	got := AdvanceToActive(start, sched)
+	want := time.Date(2026, 2, 16, 0, 0, 0, 0, time.UTC)
+	if !got.Equal(want) {
+		t.Errorf("AdvanceToActive() = %v, want %v", got, want)
+	}
+}
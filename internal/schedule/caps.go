@@ -0,0 +1,97 @@
+package schedule
+
+import (
+	"strings"
+	"time"
+
+	"github.com/lvrach/slack-social-ai/internal/history"
+)
+
+// Caps limits how many posts publish per calendar day and per week
+// (Monday to Sunday) in the schedule's time zone. Zero means no limit.
+type Caps struct {
+	MaxPerDay  int `json:"max_per_day,omitempty"`
+	MaxPerWeek int `json:"max_per_week,omitempty"`
+}
+
+// IsZero reports whether c sets no limit.
+func (c Caps) IsZero() bool { return c.MaxPerDay <= 0 && c.MaxPerWeek <= 0 }
+
+// CapHit is a cap that keeps a post from publishing.
+type CapHit struct {
+	Tag    string    // tag whose cap is reached; empty for the overall caps
+	Period string    // "day" or "week"
+	Limit  int       // posts allowed per period
+	Until  time.Time // when the period ends
+}
+
+// HasCaps reports whether the schedule limits posts per day or week,
+// overall or for any tag.
+func (s Schedule) HasCaps() bool {
+	if !s.Caps.IsZero() {
+		return true
+	}
+	for _, c := range s.TagCaps {
+		if !c.IsZero() {
+			return true
+		}
+	}
+	return false
+}
+
+// CapAt returns the overall cap, if any, that keeps any post from
+// publishing at t, given the posts published so far.
+func (s Schedule) CapAt(published []history.Entry, t time.Time) (CapHit, bool) {
+	return s.capHit(s.Caps, "", published, t)
+}
+
+// TagCapAt returns the cap of one of e's tags, if any, that keeps e from
+// publishing at t, given the posts published so far. Posts without those
+// tags may still publish.
+func (s Schedule) TagCapAt(published []history.Entry, e history.Entry, t time.Time) (CapHit, bool) {
+	for _, tag := range e.Tags {
+		tag = strings.ToLower(tag)
+		if c, ok := s.TagCaps[tag]; ok {
+			if hit, ok := s.capHit(c, tag, published, t); ok {
+				return hit, true
+			}
+		}
+	}
+	return CapHit{}, false
+}
+
+// capHit checks c against the posts published, with tag if it is set, in
+// the day and week containing t.
+func (s Schedule) capHit(c Caps, tag string, published []history.Entry, t time.Time) (CapHit, bool) {
+	if c.IsZero() {
+		return CapHit{}, false
+	}
+	local := s.in(t)
+	day := time.Date(local.Year(), local.Month(), local.Day(), 0, 0, 0, 0, local.Location())
+	nextDay := day.AddDate(0, 0, 1)
+	week := day.AddDate(0, 0, -(int(day.Weekday())+6)%7) // Monday
+	nextWeek := week.AddDate(0, 0, 7)
+
+	var today, thisWeek int
+	for _, p := range published {
+		if tag != "" && !p.HasTag(tag) {
+			continue
+		}
+		at, err := time.Parse(time.RFC3339, p.PublishedAt)
+		if err != nil || at.Before(week) || !at.Before(nextWeek) {
+			continue
+		}
+		thisWeek++
+		if !at.Before(day) && at.Before(nextDay) {
+			today++
+		}
+	}
+
+	switch {
+	case c.MaxPerWeek > 0 && thisWeek >= c.MaxPerWeek:
+		return CapHit{Tag: tag, Period: "week", Limit: c.MaxPerWeek, Until: nextWeek}, true
+	case c.MaxPerDay > 0 && today >= c.MaxPerDay:
+		return CapHit{Tag: tag, Period: "day", Limit: c.MaxPerDay, Until: nextDay}, true
+	}
+	return CapHit{}, false
+}
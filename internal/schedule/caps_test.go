@@ -0,0 +1,147 @@
+package schedule
+
+import (
+	"testing"
+	"time"
+
+	"github.com/lvrach/slack-social-ai/internal/history"
+)
+
+func published(tags []string, at ...time.Time) []history.Entry {
+	entries := make([]history.Entry, len(at))
+	for i, t := range at {
+		entries[i] = history.Entry{Status: "published", Tags: tags, PublishedAt: t.UTC().Format(time.RFC3339)}
+	}
+	return entries
+}
+
+func TestCapAt(t *testing.T) {
+	mon := time.Date(2026, 2, 9, 10, 0, 0, 0, time.UTC) // Monday 10:00
+	sched := DefaultSchedule()
+	sched.Caps = Caps{MaxPerDay: 2, MaxPerWeek: 3}
+	sched.TagCaps = map[string]Caps{"release": {MaxPerDay: 1}}
+
+	tests := []struct {
+		name      string
+		published []history.Entry
+		entry     history.Entry
+		at        time.Time
+		wantHit   bool
+		want      CapHit
+	}{
+		{"under caps", published(nil, mon), history.Entry{}, mon.Add(time.Hour), false, CapHit{}},
+		{
+			"daily cap", published(nil, mon, mon.Add(time.Hour)), history.Entry{}, mon.Add(2 * time.Hour),
+			true, CapHit{Period: "day", Limit: 2, Until: mon.Add(14 * time.Hour)},
+		},
+		{"next day", published(nil, mon, mon.Add(time.Hour)), history.Entry{}, mon.Add(24 * time.Hour), false, CapHit{}},
+		{
+			"weekly cap", published(nil, mon, mon.Add(time.Hour), mon.Add(24*time.Hour)), history.Entry{}, mon.Add(48 * time.Hour),
+			true, CapHit{Period: "week", Limit: 3, Until: mon.Add(7*24*time.Hour - 10*time.Hour)},
+		},
+		{"last week's posts", published(nil, mon.Add(-24*time.Hour), mon.Add(-23*time.Hour), mon.Add(-22*time.Hour)), history.Entry{}, mon, false, CapHit{}},
+		{
+			"tag cap", published([]string{"release"}, mon), history.Entry{Tags: []string{"Release"}}, mon.Add(time.Hour),
+			true, CapHit{Tag: "release", Period: "day", Limit: 1, Until: mon.Add(14 * time.Hour)},
+		},
+		{"tag cap, other tag", published([]string{"release"}, mon), history.Entry{Tags: []string{"tips"}}, mon.Add(time.Hour), false, CapHit{}},
+		{"tag cap, untagged posts", published(nil, mon), history.Entry{Tags: []string{"release"}}, mon.Add(time.Hour), false, CapHit{}},
+		{"tag cap, untagged entry", published([]string{"release"}, mon), history.Entry{}, mon.Add(time.Hour), false, CapHit{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, hit := sched.CapAt(tt.published, tt.at)
+			if !hit {
+				got, hit = sched.TagCapAt(tt.published, tt.entry, tt.at)
+			}
+			if hit != tt.wantHit {
+				t.Fatalf("CapAt() hit = %v, want %v", hit, tt.wantHit)
+			}
+			if got.Tag != tt.want.Tag || got.Period != tt.want.Period || got.Limit != tt.want.Limit || !got.Until.Equal(tt.want.Until) {
+				t.Errorf("CapAt() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCapAt_Timezone(t *testing.T) {
+	sched := DefaultSchedule()
+	sched.Timezone = "America/New_York"
+	sched.MaxPerDay = 1
+
+	// 23:00 UTC Monday is 18:00 in New York; 03:00 UTC Tuesday is still
+	// Monday there.
+	posts := published(nil, time.Date(2026, 2, 9, 23, 0, 0, 0, time.UTC))
+	at := time.Date(2026, 2, 10, 3, 0, 0, 0, time.UTC)
+	hit, ok := sched.CapAt(posts, at)
+	if !ok {
+		t.Fatal("CapAt() = no hit, want daily cap")
+	}
+	if want := time.Date(2026, 2, 10, 5, 0, 0, 0, time.UTC); !hit.Until.Equal(want) {
+		t.Errorf("Until = %v, want %v (New York midnight)", hit.Until, want)
+	}
+}
+
+func TestPredictPublishTimes_Caps(t *testing.T) {
+	sched := DefaultSchedule() // 9-17 mon-fri, 180min
+	sched.PostEveryMinutes = 60
+	sched.MaxPerDay = 2
+	sched.TagCaps = map[string]Caps{"release": {MaxPerWeek: 1}}
+	mon := time.Date(2026, 2, 9, 10, 0, 0, 0, time.UTC) // Monday 10:00
+
+	entries := []history.Entry{
+		{ID: "done", Status: "published", Tags: []string{"release"}, PublishedAt: mon.Add(-time.Hour).Format(time.RFC3339)},
+		{ID: "a", Status: "queued", CreatedAt: "2026-02-09T08:00:00Z"},
+		{ID: "b", Status: "queued", CreatedAt: "2026-02-09T08:01:00Z"},
+		{ID: "c", Status: "queued", CreatedAt: "2026-02-09T08:02:00Z"},
+		{ID: "d", Status: "queued", CreatedAt: "2026-02-09T08:03:00Z", Tags: []string{"release"}},
+	}
+	want := map[string]time.Time{
+		"a": mon,                                          // Monday's second post
+		"b": time.Date(2026, 2, 10, 9, 0, 0, 0, time.UTC), // day cap: Tuesday
+		"c": time.Date(2026, 2, 10, 10, 0, 0, 0, time.UTC),
+		"d": time.Date(2026, 2, 16, 9, 0, 0, 0, time.UTC), // release cap: next Monday
+	}
+
+	predictions := PredictPublishTimes(entries, sched, mon.Add(-time.Hour), mon)
+	if len(predictions) != len(want) {
+		t.Fatalf("expected %d predictions, got %d", len(want), len(predictions))
+	}
+	for _, p := range predictions {
+		if !p.PublishAt.Equal(want[p.Entry.ID]) {
+			t.Errorf("%s: PublishAt = %v, want %v", p.Entry.ID, p.PublishAt, want[p.Entry.ID])
+		}
+	}
+}
+
+func TestPredictPublishTimes_TagCapPassesOver(t *testing.T) {
+	sched := DefaultSchedule() // 9-17 mon-fri
+	sched.PostEveryMinutes = 60
+	sched.TagCaps = map[string]Caps{"promo": {MaxPerDay: 1}}
+	mon := time.Date(2026, 2, 9, 10, 0, 0, 0, time.UTC) // Monday 10:00
+
+	entries := []history.Entry{
+		{ID: "done", Status: "published", Tags: []string{"promo"}, PublishedAt: mon.Add(-time.Hour).Format(time.RFC3339)},
+		{ID: "a", Status: "queued", CreatedAt: "2026-02-09T08:00:00Z", Tags: []string{"promo"}},
+		{ID: "b", Status: "queued", CreatedAt: "2026-02-09T08:01:00Z"},
+		{ID: "c", Status: "queued", CreatedAt: "2026-02-09T08:02:00Z"},
+	}
+	want := map[string]time.Time{
+		"a": time.Date(2026, 2, 10, 9, 0, 0, 0, time.UTC), // promo cap: Tuesday
+		"b": mon,                                          // overtakes a
+		"c": mon.Add(time.Hour),
+	}
+
+	predictions := PredictPublishTimes(entries, sched, mon.Add(-time.Hour), mon)
+	if len(predictions) != len(want) {
+		t.Fatalf("expected %d predictions, got %d", len(want), len(predictions))
+	}
+	for _, p := range predictions {
+		if !p.PublishAt.Equal(want[p.Entry.ID]) {
+			t.Errorf("%s: PublishAt = %v, want %v", p.Entry.ID, p.PublishAt, want[p.Entry.ID])
+		}
+	}
+	if predictions[0].Entry.ID != "a" || predictions[0].Approximate != true {
+		t.Errorf("a keeps its queue position, approximate: %+v", predictions[0])
+	}
+}
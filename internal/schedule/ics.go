@@ -0,0 +1,318 @@
+package schedule
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// icsHorizonYears is how far past now recurring events are expanded.
+const icsHorizonYears = 2
+
+// ParseICS reads the events of an iCalendar (.ics) file as blackouts,
+// tagged with source. All-day events cover their dates; timed events cover
+// every day they touch in loc, the schedule's time zone.
+//
+// Yearly and weekly recurring events (RRULE) are expanded to each
+// occurrence that has not ended by now, up to two years ahead. Other rules
+// cannot be expanded: those events contribute only their first occurrence,
+// and a warning naming each is returned.
+func ParseICS(r io.Reader, source string, loc *time.Location, now time.Time) ([]Blackout, []string, error) {
+	lines, err := unfoldICS(r)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var (
+		blackouts []Blackout
+		warnings  []string
+		event     map[string]string // property name -> raw value, while in a VEVENT
+	)
+	for n, line := range lines {
+		switch {
+		case line == "BEGIN:VEVENT":
+			event = make(map[string]string)
+		case line == "END:VEVENT":
+			if event == nil {
+				return nil, nil, fmt.Errorf("line %d: END:VEVENT without BEGIN", n+1)
+			}
+			bs, warning, err := icsEvent(event, loc, now)
+			if err != nil {
+				return nil, nil, fmt.Errorf("event ending on line %d: %w", n+1, err)
+			}
+			if warning != "" {
+				warnings = append(warnings, fmt.Sprintf("event ending on line %d: %s", n+1, warning))
+			}
+			for _, b := range bs {
+				b.Source = source
+				blackouts = append(blackouts, b)
+			}
+			event = nil
+		case event != nil:
+			nameParams, value, ok := strings.Cut(line, ":")
+			if !ok {
+				continue
+			}
+			name, params, _ := strings.Cut(nameParams, ";")
+			name = strings.ToUpper(name)
+			if name == "DTSTART" || name == "DTEND" {
+				// Keep VALUE=DATE and TZID for icsTime.
+				value = params + ":" + value
+			}
+			event[name] = value
+		}
+	}
+	if event != nil {
+		return nil, nil, fmt.Errorf("unterminated VEVENT")
+	}
+	return blackouts, warnings, nil
+}
+
+// unfoldICS splits an iCalendar stream into logical lines, joining
+// continuation lines (RFC 5545 section 3.1).
+func unfoldICS(r io.Reader) ([]string, error) {
+	var lines []string
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line := strings.TrimRight(scanner.Text(), "\r")
+		if len(lines) > 0 && (strings.HasPrefix(line, " ") || strings.HasPrefix(line, "\t")) {
+			lines[len(lines)-1] += line[1:]
+			continue
+		}
+		lines = append(lines, line)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("read calendar: %w", err)
+	}
+	return lines, nil
+}
+
+// icsEvent converts a VEVENT's properties to blackouts, one per occurrence.
+// warning is set if its RRULE could not be expanded.
+func icsEvent(props map[string]string, loc *time.Location, now time.Time) ([]Blackout, string, error) {
+	start, ok := props["DTSTART"]
+	if !ok {
+		return nil, "", fmt.Errorf("missing DTSTART")
+	}
+	first, allDay, err := icsTime(start, loc)
+	if err != nil {
+		return nil, "", fmt.Errorf("DTSTART: %w", err)
+	}
+	from := icsDay(first, allDay, loc)
+
+	// span is how many days after its first the event ends.
+	span := 0
+	if end, ok := props["DTEND"]; ok {
+		last, endAllDay, err := icsTime(end, loc)
+		if err != nil {
+			return nil, "", fmt.Errorf("DTEND: %w", err)
+		}
+		// DTEND is exclusive: an all-day event or one ending at midnight
+		// stops the day before.
+		to := icsDay(last, endAllDay, loc)
+		if endAllDay || last.In(loc).Equal(to) {
+			to = to.AddDate(0, 0, -1)
+		}
+		span = max(span, daysBetween(from, to))
+	}
+
+	name := icsText(props["SUMMARY"])
+	blackout := func(day time.Time) Blackout {
+		return Blackout{
+			From: day.Format(time.DateOnly),
+			To:   day.AddDate(0, 0, span).Format(time.DateOnly),
+			Name: name,
+		}
+	}
+
+	spec, ok := props["RRULE"]
+	if !ok {
+		return []Blackout{blackout(from)}, "", nil
+	}
+	rule, err := parseRRule(spec, loc)
+	if err != nil {
+		label := name
+		if label == "" {
+			label = from.Format(time.DateOnly)
+		}
+		return []Blackout{blackout(from)},
+			fmt.Sprintf("%q repeats by %s; only its first occurrence was imported", label, err), nil
+	}
+
+	today := icsDay(now, false, loc)
+	horizon := now.AddDate(icsHorizonYears, 0, 0)
+	var blackouts []Blackout
+	for _, occurrence := range rule.occurrences(first, horizon) {
+		day := icsDay(occurrence, allDay, loc)
+		if !day.AddDate(0, 0, span).Before(today) {
+			blackouts = append(blackouts, blackout(day))
+		}
+	}
+	return blackouts, "", nil
+}
+
+// icsTime parses a DTSTART/DTEND value, as "PARAMS:VALUE". allDay reports
+// a date (VALUE=DATE), returned as midnight in loc. Times ending in Z are
+// UTC; others are in their TZID, or in loc if it has none or names a zone
+// that is not an IANA one.
+func icsTime(v string, loc *time.Location) (t time.Time, allDay bool, err error) {
+	params, value, _ := strings.Cut(v, ":")
+	zone := loc
+	for param := range strings.SplitSeq(params, ";") {
+		if key, val, ok := strings.Cut(param, "="); ok && strings.EqualFold(key, "TZID") {
+			if z, err := LoadLocation(strings.Trim(val, `"`)); err == nil {
+				zone = z
+			}
+		}
+	}
+
+	switch {
+	case len(value) == 8:
+		t, err = time.ParseInLocation("20060102", value, loc)
+		allDay = true
+	case strings.HasSuffix(value, "Z"):
+		t, err = time.Parse("20060102T150405Z", value)
+	default:
+		t, err = time.ParseInLocation("20060102T150405", value, zone)
+	}
+	if err != nil {
+		return time.Time{}, false, fmt.Errorf("invalid date %q", value)
+	}
+	return t, allDay, nil
+}
+
+// icsDay returns the day of t in loc, as midnight in loc. Dates of all-day
+// events are already in loc.
+func icsDay(t time.Time, allDay bool, loc *time.Location) time.Time {
+	if !allDay {
+		t = t.In(loc)
+	}
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, loc)
+}
+
+// daysBetween returns the number of calendar days from a to b, both
+// midnights in the same zone.
+func daysBetween(a, b time.Time) int {
+	days := 0
+	for d := a; d.Before(b); d = d.AddDate(0, 0, 1) {
+		days++
+	}
+	return days
+}
+
+// icsText unescapes an iCalendar TEXT value.
+func icsText(s string) string {
+	return strings.NewReplacer(`\n`, " ", `\N`, " ", `\,`, ",", `\;`, ";", `\\`, `\`).Replace(s)
+}
+
+// rrule is a yearly or weekly recurrence rule (RFC 5545 section 3.3.10).
+type rrule struct {
+	freq     string // "YEARLY" or "WEEKLY"
+	interval int
+	count    int            // 0 = unlimited
+	until    time.Time      // zero = unlimited
+	byDay    []time.Weekday // WEEKLY only; empty = DTSTART's weekday
+}
+
+// parseRRule parses an RRULE value. The error describes what cannot be
+// expanded, e.g. "FREQ=MONTHLY".
+func parseRRule(s string, loc *time.Location) (rrule, error) {
+	r := rrule{interval: 1}
+	for part := range strings.SplitSeq(s, ";") {
+		key, val, _ := strings.Cut(part, "=")
+		key = strings.ToUpper(key)
+		switch key {
+		case "FREQ":
+			r.freq = strings.ToUpper(val)
+		case "INTERVAL", "COUNT":
+			n, err := strconv.Atoi(val)
+			if err != nil || n < 1 {
+				return rrule{}, fmt.Errorf("%s=%s", key, val)
+			}
+			if key == "INTERVAL" {
+				r.interval = n
+			} else {
+				r.count = n
+			}
+		case "UNTIL":
+			t, _, err := icsTime(":"+val, loc)
+			if err != nil {
+				return rrule{}, fmt.Errorf("UNTIL=%s", val)
+			}
+			r.until = t
+		case "BYDAY":
+			for day := range strings.SplitSeq(val, ",") {
+				wd, ok := icsWeekdays[strings.ToUpper(day)]
+				if !ok {
+					return rrule{}, fmt.Errorf("BYDAY=%s", val)
+				}
+				r.byDay = append(r.byDay, wd)
+			}
+		case "WKST":
+			// Weeks start on Monday, which only matters for INTERVAL > 1.
+		default:
+			return rrule{}, fmt.Errorf("%s=%s", key, val)
+		}
+	}
+	switch {
+	case r.freq != "YEARLY" && r.freq != "WEEKLY":
+		return rrule{}, fmt.Errorf("FREQ=%s", r.freq)
+	case r.freq == "YEARLY" && len(r.byDay) > 0:
+		return rrule{}, fmt.Errorf("BYDAY in a yearly rule")
+	}
+	slices.SortFunc(r.byDay, func(a, b time.Weekday) int { return mondayIndex(a) - mondayIndex(b) })
+	return r, nil
+}
+
+var icsWeekdays = map[string]time.Weekday{
+	"MO": time.Monday, "TU": time.Tuesday, "WE": time.Wednesday, "TH": time.Thursday,
+	"FR": time.Friday, "SA": time.Saturday, "SU": time.Sunday,
+}
+
+func mondayIndex(d time.Weekday) int { return (int(d) + 6) % 7 }
+
+// occurrences returns the starts of the rule's occurrences from start, the
+// first one, up to horizon.
+func (r rrule) occurrences(start, horizon time.Time) []time.Time {
+	var result []time.Time
+	emit := func(t time.Time) bool {
+		if t.Before(start) {
+			return true
+		}
+		if t.After(horizon) || (!r.until.IsZero() && t.After(r.until)) ||
+			(r.count > 0 && len(result) == r.count) {
+			return false
+		}
+		result = append(result, t)
+		return true
+	}
+
+	for k := 0; ; k++ {
+		if r.freq == "YEARLY" {
+			t := start.AddDate(k*r.interval, 0, 0)
+			if t.Day() != start.Day() {
+				continue // Feb 29 in a non-leap year
+			}
+			if !emit(t) {
+				return result
+			}
+			continue
+		}
+
+		// WEEKLY: each listed day of every interval-th week from start's.
+		weekStart := start.AddDate(0, 0, 7*k*r.interval-mondayIndex(start.Weekday()))
+		days := r.byDay
+		if len(days) == 0 {
+			days = []time.Weekday{start.Weekday()}
+		}
+		for _, d := range days {
+			if !emit(weekStart.AddDate(0, 0, mondayIndex(d))) {
+				return result
+			}
+		}
+	}
+}
@@ -0,0 +1,125 @@
+package schedule
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+const holidaysICS = "BEGIN:VCALENDAR\r\n" +
+	"VERSION:2.0\r\n" +
+	"PRODID:-//Example//Holidays//EN\r\n" +
+	"BEGIN:VEVENT\r\n" +
+	"UID:1@example.com\r\n" +
+	"DTSTART;VALUE=DATE:20261224\r\n" +
+	"DTEND;VALUE=DATE:20261227\r\n" +
+	"SUMMARY:Christmas\\, office clo\r\n" +
+	" sed\r\n" +
+	"END:VEVENT\r\n" +
+	"BEGIN:VEVENT\r\n" +
+	"UID:2@example.com\r\n" +
+	"DTSTART;VALUE=DATE:20270101\r\n" +
+	"SUMMARY:New Year\r\n" +
+	"END:VEVENT\r\n" +
+	"BEGIN:VEVENT\r\n" +
+	"UID:3@example.com\r\n" +
+	"DTSTART;TZID=Europe/Berlin:20261030T140000\r\n" +
+	"DTEND;TZID=Europe/Berlin:20261031T000000\r\n" +
+	"SUMMARY:Offsite\r\n" +
+	"END:VEVENT\r\n" +
+	"BEGIN:VEVENT\r\n" +
+	"DTSTART:20261111T220000Z\r\n" +
+	"DTEND:20261112T020000Z\r\n" +
+	"END:VEVENT\r\n" +
+	"END:VCALENDAR\r\n"
+
+func TestParseICS(t *testing.T) {
+	now := time.Date(2026, 10, 1, 12, 0, 0, 0, time.UTC)
+	got, warnings, err := ParseICS(strings.NewReader(holidaysICS), "holidays.ics", time.UTC, now)
+	if err != nil {
+		t.Fatalf("ParseICS() error = %v", err)
+	}
+	if len(warnings) > 0 {
+		t.Errorf("warnings = %v, want none", warnings)
+	}
+
+	want := []Blackout{
+		{From: "2026-12-24", To: "2026-12-26", Name: "Christmas, office closed", Source: "holidays.ics"},
+		{From: "2027-01-01", To: "2027-01-01", Name: "New Year", Source: "holidays.ics"},
+		{From: "2026-10-30", To: "2026-10-30", Name: "Offsite", Source: "holidays.ics"},
+		{From: "2026-11-11", To: "2026-11-12", Source: "holidays.ics"},
+	}
+	if len(got) != len(want) {
+		t.Fatalf("got %d blackouts, want %d: %+v", len(got), len(want), got)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("blackout %d = %+v, want %+v", i, got[i], want[i])
+		}
+	}
+}
+
+func TestParseICS_Errors(t *testing.T) {
+	tests := map[string]string{
+		"missing DTSTART": "BEGIN:VEVENT\nSUMMARY:x\nEND:VEVENT\n",
+		"bad date":        "BEGIN:VEVENT\nDTSTART;VALUE=DATE:2026-12-24\nEND:VEVENT\n",
+		"unterminated":    "BEGIN:VEVENT\nDTSTART;VALUE=DATE:20261224\n",
+	}
+	for name, ics := range tests {
+		t.Run(name, func(t *testing.T) {
+			if _, _, err := ParseICS(strings.NewReader(ics), "x.ics", time.UTC, time.Now()); err == nil {
+				t.Error("ParseICS() succeeded, want error")
+			}
+		})
+	}
+}
+
+func TestParseICS_TimedEventsInScheduleZone(t *testing.T) {
+	// 23:30-23:45 UTC on Nov 11 is already Nov 12 in Tokyo.
+	ics := "BEGIN:VEVENT\nDTSTART:20261111T233000Z\nDTEND:20261111T234500Z\nEND:VEVENT\n" +
+		// 09:00 in New York is 23:00 in Tokyo, the same day.
+		"BEGIN:VEVENT\nDTSTART;TZID=America/New_York:20261111T090000\nEND:VEVENT\n"
+	tokyo, _ := LoadLocation("Asia/Tokyo")
+	got, _, err := ParseICS(strings.NewReader(ics), "x.ics", tokyo, time.Date(2026, 10, 1, 0, 0, 0, 0, tokyo))
+	if err != nil {
+		t.Fatalf("ParseICS() error = %v", err)
+	}
+	if len(got) != 2 || got[0].From != "2026-11-12" || got[0].To != "2026-11-12" || got[1].From != "2026-11-11" {
+		t.Errorf("ParseICS() = %+v, want 2026-11-12 then 2026-11-11", got)
+	}
+}
+
+func TestParseICS_Recurring(t *testing.T) {
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	ics := "BEGIN:VEVENT\nDTSTART;VALUE=DATE:20101225\nDTEND;VALUE=DATE:20101227\nRRULE:FREQ=YEARLY\nSUMMARY:Christmas\nEND:VEVENT\n" +
+		"BEGIN:VEVENT\nDTSTART;VALUE=DATE:20260302\nRRULE:FREQ=WEEKLY;INTERVAL=2;BYDAY=MO,FR;COUNT=3\nSUMMARY:No-post day\nEND:VEVENT\n" +
+		"BEGIN:VEVENT\nDTSTART;VALUE=DATE:20250505\nRRULE:FREQ=YEARLY;UNTIL=20260101\nSUMMARY:Ended\nEND:VEVENT\n" +
+		"BEGIN:VEVENT\nDTSTART;VALUE=DATE:20101126\nRRULE:FREQ=YEARLY;BYMONTH=11;BYDAY=4TH\nSUMMARY:Thanksgiving\nEND:VEVENT\n"
+
+	got, warnings, err := ParseICS(strings.NewReader(ics), "x.ics", time.UTC, now)
+	if err != nil {
+		t.Fatalf("ParseICS() error = %v", err)
+	}
+
+	var dates []string
+	for _, b := range got {
+		dates = append(dates, b.Name+" "+b.From+".."+b.To)
+	}
+	want := []string{
+		// Yearly from 2010: the occurrences from now up to two years ahead.
+		"Christmas 2026-12-25..2026-12-26",
+		"Christmas 2027-12-25..2027-12-26",
+		// Mondays and Fridays of every other week, three in all.
+		"No-post day 2026-03-02..2026-03-02",
+		"No-post day 2026-03-06..2026-03-06",
+		"No-post day 2026-03-16..2026-03-16",
+		// Rules that can't be expanded keep their first occurrence.
+		"Thanksgiving 2010-11-26..2010-11-26",
+	}
+	if strings.Join(dates, "\n") != strings.Join(want, "\n") {
+		t.Errorf("ParseICS() =\n%s\nwant\n%s", strings.Join(dates, "\n"), strings.Join(want, "\n"))
+	}
+	if len(warnings) != 1 || !strings.Contains(warnings[0], "Thanksgiving") || !strings.Contains(warnings[0], "BYMONTH=11") {
+		t.Errorf("warnings = %v, want one naming Thanksgiving", warnings)
+	}
+}
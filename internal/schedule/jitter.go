@@ -0,0 +1,54 @@
+package schedule
+
+import (
+	"time"
+
+	"github.com/lvrach/slack-social-ai/internal/history"
+)
+
+// Jitter returns the longest random delay added to each publish slot.
+func (s Schedule) Jitter() time.Duration {
+	return time.Duration(s.JitterMinutes) * time.Minute
+}
+
+// JitterAt returns the earliest time e may publish under the schedule's
+// jitter, or the zero time if jitter is off.
+//
+// The slot opens at the first active time after slot (when the previous
+// post's interval ends; zero if there is none), e's ScheduledAt, and its
+// CreatedAt. e publishes a delay after that, chosen by its JitterSeed, so
+// the result is the same every time it is computed. The delay stays inside
+// the opening window, leaving a launchd tick before the window closes.
+func (s Schedule) JitterAt(e history.Entry, slot time.Time) time.Time {
+	if s.JitterMinutes <= 0 {
+		return time.Time{}
+	}
+	open := slot
+	for _, ts := range []string{e.ScheduledAt, e.CreatedAt} {
+		if t, err := time.Parse(time.RFC3339, ts); err == nil && t.After(open) {
+			open = t
+		}
+	}
+	open = AdvanceToActive(open, s)
+
+	limit := min(s.Jitter(), s.windowEnd(open).Sub(open)-launchdInterval)
+	if limit <= 0 {
+		return open
+	}
+	seconds := uint64(limit / time.Second)
+	return open.Add(time.Duration(seconds*uint64(e.JitterSeed)>>32) * time.Second)
+}
+
+// windowEnd returns the end of the active window containing t, or t if it
+// is not in one.
+func (s Schedule) windowEnd(t time.Time) time.Time {
+	local := s.in(t)
+	now := At(local.Hour(), local.Minute())
+	for _, w := range s.Windows[weekdayKey(local)] {
+		if w.contains(now) {
+			end := time.Date(local.Year(), local.Month(), local.Day(), int(w.End)/60, int(w.End)%60, 0, 0, local.Location())
+			return end.In(t.Location())
+		}
+	}
+	return t
+}
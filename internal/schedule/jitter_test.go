@@ -0,0 +1,82 @@
+package schedule
+
+import (
+	"testing"
+	"time"
+
+	"github.com/lvrach/slack-social-ai/internal/history"
+)
+
+func TestJitterAt(t *testing.T) {
+	sched := DefaultSchedule() // 9-17 mon-fri
+	sched.JitterMinutes = 40
+	created := time.Date(2026, 2, 9, 10, 0, 0, 0, time.UTC) // Monday 10:00
+	entry := history.Entry{CreatedAt: created.Format(time.RFC3339)}
+
+	tests := []struct {
+		name string
+		seed uint32
+		slot time.Time
+		want time.Time
+	}{
+		{"smallest seed", 1, time.Time{}, created},
+		{"middle seed", 1 << 31, time.Time{}, created.Add(20 * time.Minute)},
+		{"largest seed", ^uint32(0), time.Time{}, created.Add(40*time.Minute - time.Second)},
+		{"slot after creation", 1 << 31, created.Add(time.Hour), created.Add(80 * time.Minute)},
+		// The slot opens at 16:40; the delay stays 10 minutes clear of 17:00.
+		{"near window end", 1 << 31, created.Add(6*time.Hour + 40*time.Minute), created.Add(6*time.Hour + 45*time.Minute)},
+		// Opens outside hours, so at Tuesday 09:00.
+		{"outside hours", 1 << 31, created.Add(8 * time.Hour), time.Date(2026, 2, 10, 9, 20, 0, 0, time.UTC)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			entry.JitterSeed = tt.seed
+			got := sched.JitterAt(entry, tt.slot)
+			if !got.Equal(tt.want) {
+				t.Errorf("JitterAt() = %v, want %v", got, tt.want)
+			}
+			if again := sched.JitterAt(entry, tt.slot); !again.Equal(got) {
+				t.Errorf("JitterAt() not deterministic: %v then %v", got, again)
+			}
+		})
+	}
+
+	sched.JitterMinutes = 0
+	if got := sched.JitterAt(entry, time.Time{}); !got.IsZero() {
+		t.Errorf("JitterAt() without jitter = %v, want zero", got)
+	}
+}
+
+func TestPredictPublishTimes_Jitter(t *testing.T) {
+	sched := DefaultSchedule() // 9-17 mon-fri, 180min
+	sched.JitterMinutes = 30
+	created := time.Date(2026, 2, 9, 10, 0, 0, 0, time.UTC) // Monday 10:00
+
+	entries := []history.Entry{
+		{ID: "a", Status: "queued", CreatedAt: created.Format(time.RFC3339), JitterSeed: 1 << 31},
+		{ID: "b", Status: "queued", CreatedAt: created.Format(time.RFC3339), JitterSeed: 1 << 30},
+	}
+	want := []time.Time{
+		created.Add(15 * time.Minute), // 10:15
+		created.Add(15*time.Minute + 3*time.Hour + 7*time.Minute + 30*time.Second), // 13:22:30
+	}
+
+	// Predictions don't move as time passes within the slot.
+	for _, now := range []time.Time{created, created.Add(5 * time.Minute), created.Add(14 * time.Minute)} {
+		predictions := PredictPublishTimes(entries, sched, time.Time{}, now)
+		if len(predictions) != 2 {
+			t.Fatalf("expected 2 predictions, got %d", len(predictions))
+		}
+		for i, p := range predictions {
+			if !p.PublishAt.Equal(want[i]) {
+				t.Errorf("now %s: prediction %d = %v, want %v", now.Format("15:04"), i, p.PublishAt, want[i])
+			}
+		}
+	}
+
+	// Once the jittered time has passed, the entry is due now.
+	late := created.Add(20 * time.Minute)
+	if got := PredictPublishTimes(entries, sched, time.Time{}, late)[0].PublishAt; !got.Equal(late) {
+		t.Errorf("after the delay: PublishAt = %v, want %v", got, late)
+	}
+}
@@ -1,9 +1,11 @@
 package schedule
 
 import (
+	"slices"
 	"time"
 
 	"github.com/lvrach/slack-social-ai/internal/history"
+	"github.com/lvrach/slack-social-ai/internal/recur"
 )
 
 // launchdInterval is the fallback minimum interval between publish runs.
@@ -15,83 +17,343 @@ type Prediction struct {
 	Position    int       // 1-based queue position
 	PublishAt   time.Time // predicted publish time
 	Approximate bool      // true for position > 1 (depends on earlier items)
+	Recurring   bool      // upcoming instance of a recurring template, not yet spawned
+	Held        bool      // held, or behind a held part of its series; PublishAt is zero
 }
 
 // PredictPublishTimes calculates predicted publish times for queued entries
 // based on the schedule, last published time, and current time.
+// Entries are predicted in publish order (see history.SortQueue).
+//
+// While publishing is paused, predictions start when the pause ends. A pause
+// with no end is assumed to be lifted now, and every prediction is marked
+// approximate.
+//
+// "recurring" templates among entries are not queued themselves; each
+// unpaused one contributes its next instance, rendered for its occurrence,
+// after the queued entries.
+//
+// "published" entries count towards the schedule's caps. While an overall
+// cap is reached the queue waits for the period to end, like an entry
+// scheduled for later. An entry over one of its tags' caps is passed over
+// instead: later entries overtake it until the period ends.
 func PredictPublishTimes(
 	entries []history.Entry,
 	sched Schedule,
 	lastPublished time.Time,
 	now time.Time,
 ) []Prediction {
+	var templates, published []history.Entry
+	entries = slices.DeleteFunc(slices.Clone(entries), func(e history.Entry) bool {
+		switch e.Status {
+		case "recurring":
+			templates = append(templates, e)
+			return true
+		case "published":
+			published = append(published, e)
+			return true
+		}
+		return false
+	})
+
+	start, indefinite := resumeAt(sched, now)
+	predictions := predictQueue(entries, sched, lastPublished, start, &published)
+	predictions = append(predictions, predictRecurring(templates, sched, start, len(predictions), published)...)
+	if indefinite {
+		for i := range predictions {
+			predictions[i].Approximate = true
+		}
+	}
+	return predictions
+}
+
+// resumeAt returns when publishing may next happen at or after now as far
+// as a pause is concerned. indefinite is set for a pause with no end, in
+// which case now is returned.
+func resumeAt(sched Schedule, now time.Time) (time.Time, bool) {
+	paused, until := sched.PausedAt(now)
+	switch {
+	case !paused:
+		return now, false
+	case until.IsZero():
+		return now, true
+	}
+	return until.In(now.Location()), false
+}
+
+// predictQueue predicts the queue in publish order, adding each predicted
+// post to published for the caps of the ones after it. An entry over a tag
+// cap is passed over, with the later parts of its series, for the next
+// entry that is not; only when every entry is over one does the queue wait
+// for the first tag cap to end.
+func predictQueue(entries []history.Entry, sched Schedule, lastPublished, now time.Time, published *[]history.Entry) []Prediction {
 	if len(entries) == 0 {
 		return nil
 	}
 
+	entries = orderSeries(history.SortQueue(entries))
 	interval := max(sched.PostEvery(), launchdInterval)
 
-	cursor := now
+	start := now
+	var slot time.Time // when the first slot opens, for jitter
 
-	// If last published + PostEvery > cursor, wait for the frequency guard.
+	// If last published + PostEvery > start, wait for the frequency guard.
 	if !lastPublished.IsZero() && sched.PostEvery() > 0 {
 		nextEligible := lastPublished.Add(sched.PostEvery())
-		if nextEligible.After(cursor) {
-			cursor = nextEligible
+		slot = nextEligible
+		if nextEligible.After(start) {
+			start = nextEligible
 		}
 	}
 
 	predictions := make([]Prediction, len(entries))
+	var pending []int                // entries still to predict, in publish order
+	blocked := make(map[string]bool) // series waiting on a held part
 	for i, entry := range entries {
-		// If entry has a ScheduledAt that's after cursor, jump to it.
-		if entry.ScheduledAt != "" {
-			if scheduled, err := time.Parse(time.RFC3339, entry.ScheduledAt); err == nil {
-				if scheduled.After(cursor) {
-					cursor = scheduled
+		predictions[i] = Prediction{Entry: entry, Position: i + 1}
+		// Held entries keep their position but take no slot, and neither
+		// do later parts of their series.
+		if entry.Held || (entry.Series != nil && blocked[entry.Series.Name]) {
+			if entry.Series != nil {
+				blocked[entry.Series.Name] = true
+			}
+			predictions[i].Held = true
+			continue
+		}
+		pending = append(pending, i)
+	}
+
+	last := -1 // previous pick
+	var lastAt time.Time
+	floor := start // raised while every candidate is over a tag cap
+	for picks := 0; len(pending) > 0; {
+		// Nothing publishes between the parts of a strict series.
+		candidates := pending
+		if last >= 0 && entries[last].Series != nil && entries[last].Series.Strict {
+			if n := slices.IndexFunc(pending, func(i int) bool { return nextPart(entries[last], entries[i]) }); n >= 0 {
+				candidates = pending[n : n+1]
+			}
+		}
+
+		pick := -1
+		var pickAt, wait time.Time
+		capped := make(map[string]bool) // series with a part over a tag cap
+		for _, i := range candidates {
+			entry := entries[i]
+			if entry.Series != nil && capped[entry.Series.Name] {
+				continue
+			}
+
+			// The next slot opens an interval after the previous pick; the
+			// next part of a series follows after the series gap instead.
+			cursor, jitterSlot := start, slot
+			if last >= 0 {
+				gap := interval
+				if nextPart(entries[last], entry) {
+					gap = max(entries[last].Series.GapDuration(), launchdInterval)
+				}
+				cursor = lastAt.Add(gap)
+				jitterSlot = cursor
+			}
+			cursor = later(cursor, floor)
+
+			// If entry has a ScheduledAt that's after cursor, jump to it.
+			if entry.ScheduledAt != "" {
+				if scheduled, err := time.Parse(time.RFC3339, entry.ScheduledAt); err == nil {
+					if scheduled.After(cursor) {
+						cursor = scheduled
+					}
 				}
 			}
+
+			// Advance cursor to the next active window, then past the
+			// entry's jitter delay and the overall caps.
+			cursor = AdvanceToActive(cursor, sched)
+			if at := sched.JitterAt(entry, jitterSlot); at.After(cursor) {
+				cursor = AdvanceToActive(at, sched)
+			}
+			cursor = afterCaps(cursor, sched, func(t time.Time) (CapHit, bool) {
+				return sched.CapAt(*published, t)
+			})
+
+			if hit, ok := sched.TagCapAt(*published, entry, cursor); ok {
+				if entry.Series != nil {
+					capped[entry.Series.Name] = true
+				}
+				if wait.IsZero() || hit.Until.Before(wait) {
+					wait = hit.Until
+				}
+				continue
+			}
+			pick, pickAt = i, cursor
+			break
 		}
 
-		// Advance cursor to the next active window.
-		cursor = AdvanceToActive(cursor, sched)
+		if pick < 0 {
+			floor = AdvanceToActive(wait.In(start.Location()), sched)
+			continue
+		}
+		*published = append(*published, predictedPost(entries[pick], pickAt))
+		predictions[pick].PublishAt = pickAt
+		predictions[pick].Approximate = picks > 0
+		pending = slices.DeleteFunc(pending, func(i int) bool { return i == pick })
+		last, lastAt = pick, pickAt
+		picks++
+	}
+
+	return predictions
+}
 
-		predictions[i] = Prediction{
-			Entry:       entry,
-			Position:    i + 1,
-			PublishAt:   cursor,
-			Approximate: i > 0,
+// orderSeries puts the parts of each series in part order within the slots
+// its parts occupy in the publish order. Strict series are gathered at the
+// slot of their first part, since nothing publishes between their parts.
+func orderSeries(entries []history.Entry) []history.Entry {
+	parts := make(map[string][]history.Entry)
+	for _, e := range entries {
+		if e.Series != nil {
+			parts[e.Series.Name] = append(parts[e.Series.Name], e)
 		}
+	}
+	if len(parts) == 0 {
+		return entries
+	}
+	for _, p := range parts {
+		slices.SortStableFunc(p, func(a, b history.Entry) int { return a.Series.Part - b.Series.Part })
+	}
 
-		// Advance cursor for the next entry.
-		cursor = cursor.Add(interval)
+	ordered := make([]history.Entry, 0, len(entries))
+	used := make(map[string]int)
+	for _, e := range entries {
+		if e.Series == nil {
+			ordered = append(ordered, e)
+			continue
+		}
+		name := e.Series.Name
+		p := parts[name]
+		switch {
+		case !p[0].Series.Strict:
+			ordered = append(ordered, p[used[name]])
+			used[name]++
+		case used[name] == 0:
+			ordered = append(ordered, p...)
+			used[name] = len(p)
+		}
 	}
+	return ordered
+}
 
+// nextPart reports whether b continues the series a belongs to.
+func nextPart(a, b history.Entry) bool {
+	return a.Series != nil && b.Series != nil && a.Series.Name == b.Series.Name
+}
+
+// predictRecurring predicts the next instance of each unpaused template,
+// ordered by time, with positions continuing after the queue. Instances are
+// always approximate: they publish in the first active window after they
+// spawn, contention with the queue permitting.
+func predictRecurring(templates []history.Entry, sched Schedule, now time.Time, queued int, published []history.Entry) []Prediction {
+	var predictions []Prediction
+	for _, tmpl := range templates {
+		rec := tmpl.Recurrence
+		if rec == nil || rec.Paused || rec.NextAt == "" {
+			continue
+		}
+		next, err := time.Parse(time.RFC3339, rec.NextAt)
+		if err != nil {
+			continue
+		}
+		next = next.In(now.Location())
+
+		instance := tmpl
+		instance.ID = ""
+		instance.Status = "queued"
+		instance.Message = recur.Render(tmpl.Message, next, rec.Count+1)
+		instance.ScheduledAt = next.UTC().Format(time.RFC3339)
+		instance.Recurrence = nil
+		instance.RecurrenceID = tmpl.ID
+		instance.JitterSeed = history.InstanceSeed(tmpl)
+		if rec.Draft {
+			instance.Status = "draft"
+		}
+		publishAt := AdvanceToActive(later(next, now), sched)
+		if at := sched.JitterAt(instance, time.Time{}); at.After(publishAt) {
+			publishAt = AdvanceToActive(at, sched)
+		}
+		publishAt = afterCaps(publishAt, sched, func(t time.Time) (CapHit, bool) {
+			if hit, ok := sched.CapAt(published, t); ok {
+				return hit, true
+			}
+			return sched.TagCapAt(published, instance, t)
+		})
+		predictions = append(predictions, Prediction{
+			Entry:       instance,
+			PublishAt:   publishAt,
+			Approximate: true,
+			Recurring:   true,
+		})
+	}
+	slices.SortStableFunc(predictions, func(a, b Prediction) int { return a.PublishAt.Compare(b.PublishAt) })
+	for i := range predictions {
+		predictions[i].Position = queued + i + 1
+	}
 	return predictions
 }
 
+// afterCaps returns the first active time from t at which capAt reports
+// no cap reached.
+func afterCaps(t time.Time, sched Schedule, capAt func(time.Time) (CapHit, bool)) time.Time {
+	for {
+		hit, ok := capAt(t)
+		if !ok {
+			return t
+		}
+		t = AdvanceToActive(hit.Until.In(t.Location()), sched)
+	}
+}
+
+// predictedPost is e as if published at t, for counting against caps.
+func predictedPost(e history.Entry, t time.Time) history.Entry {
+	return history.Entry{Tags: e.Tags, Status: "published", PublishedAt: t.UTC().Format(time.RFC3339)}
+}
+
+func later(a, b time.Time) time.Time {
+	if a.After(b) {
+		return a
+	}
+	return b
+}
+
 // AdvanceToActive advances t to the next time the schedule is active.
-// If t is already in an active window, returns t unchanged.
-// Scans up to 14 days forward to handle long inactive gaps.
+// If t is already in an active window, returns t unchanged. Windows are
+// read in the schedule's Timezone, if set, else in t's zone; the result
+// is in t's zone.
+//
+// Blackouts are skipped whole, however long. Beyond them, it scans up to
+// 14 days forward, which covers any weekly pattern; if none of those days
+// has a window, the schedule is never active and the day after is returned.
 func AdvanceToActive(t time.Time, sched Schedule) time.Time {
 	if sched.IsActiveAt(t) {
 		return t
 	}
 
-	// Scan up to 14 days forward, day by day.
-	for range 15 {
-		// Try start hour on the current day (if we haven't passed it yet).
-		if t.Hour() < sched.StartHour {
-			candidate := time.Date(t.Year(), t.Month(), t.Day(), sched.StartHour, 0, 0, 0, t.Location())
-			if sched.IsActiveAt(candidate) {
-				return candidate
-			}
+	// Scan day by day for the next window start. time.Date keeps window
+	// starts at their wall-clock time across DST changes.
+	local := sched.in(t)
+	day := time.Date(local.Year(), local.Month(), local.Day(), 0, 0, 0, 0, local.Location())
+	for scanned := 0; scanned < 15; {
+		if end, ok := sched.blackoutEnd(day); ok {
+			day = end.AddDate(0, 0, 1)
+			continue
 		}
-		// Jump to start hour of the next day.
-		t = time.Date(t.Year(), t.Month(), t.Day()+1, sched.StartHour, 0, 0, 0, t.Location())
-		if sched.IsActiveAt(t) {
-			return t
+		for _, w := range sched.Windows[weekdayKey(day)] {
+			start := time.Date(day.Year(), day.Month(), day.Day(), int(w.Start)/60, int(w.Start)%60, 0, 0, local.Location())
+			if start.After(t) {
+				return start.In(t.Location())
+			}
 		}
+		day = day.AddDate(0, 0, 1)
+		scanned++
 	}
 
-	return t
+	return day.In(t.Location())
 }
@@ -1,6 +1,7 @@
 package schedule
 
 import (
+	"slices"
 	"testing"
 	"time"
 
@@ -159,9 +160,7 @@ func TestPredictPublishTimes_WeekendGap(t *testing.T) {
 func TestPredictPublishTimes_ZeroPostEvery_UsesLaunchdInterval(t *testing.T) {
 	sched := Schedule{
 		PostEveryMinutes: 0,
-		StartHour:        0,
-		EndHour:          24,
-		Weekdays:         []string{"mon", "tue", "wed", "thu", "fri", "sat", "sun"},
+		Windows:          SameWindows(Weekdays, Window{Start: At(0, 0), End: At(24, 0)}),
 	}
 	now := time.Date(2026, 2, 9, 10, 0, 0, 0, time.UTC) // Monday 10:00
 
@@ -185,9 +184,7 @@ func TestPredictPublishTimes_ZeroPostEvery_UsesLaunchdInterval(t *testing.T) {
 func TestPredictPublishTimes_SingleActiveWeekday(t *testing.T) {
 	sched := Schedule{
 		PostEveryMinutes: 180,
-		StartHour:        9,
-		EndHour:          17,
-		Weekdays:         []string{"wed"},
+		Windows:          SameWindows([]string{"wed"}, Window{Start: At(9, 0), End: At(17, 0)}),
 	}
 	// Monday — not active
 	now := time.Date(2026, 2, 9, 10, 0, 0, 0, time.UTC) // Monday
@@ -268,3 +265,232 @@ func TestAdvanceToActive(t *testing.T) {
 		})
 	}
 }
+
+func TestAdvanceToActive_DST(t *testing.T) {
+	sched := Schedule{
+		Windows:  SameWindows(Weekdays, Window{Start: At(9, 0), End: At(17, 0)}),
+		Timezone: "America/New_York",
+	}
+
+	tests := []struct {
+		name string
+		t    time.Time
+		want time.Time
+	}{
+		{
+			// Sat 18:00 EST -> Sun 09:00 EDT, 14 hours later.
+			name: "spring forward",
+			t:    time.Date(2026, 3, 7, 23, 0, 0, 0, time.UTC),
+			want: time.Date(2026, 3, 8, 13, 0, 0, 0, time.UTC),
+		},
+		{
+			// Sat 18:00 EDT -> Sun 09:00 EST, 16 hours later.
+			name: "fall back",
+			t:    time.Date(2026, 10, 31, 22, 0, 0, 0, time.UTC),
+			want: time.Date(2026, 11, 1, 14, 0, 0, 0, time.UTC),
+		},
+		{
+			// 08:30 EST on the Monday after: the offset has changed again.
+			name: "morning after fall back",
+			t:    time.Date(2026, 11, 2, 13, 30, 0, 0, time.UTC),
+			want: time.Date(2026, 11, 2, 14, 0, 0, 0, time.UTC),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := AdvanceToActive(tt.t, sched)
+			if !got.Equal(tt.want) {
+				t.Errorf("AdvanceToActive(%v) = %v, want %v", tt.t, got, tt.want)
+			}
+			if got.Location() != time.UTC {
+				t.Errorf("AdvanceToActive returned zone %v, want the input's (UTC)", got.Location())
+			}
+		})
+	}
+}
+
+func TestPredictPublishTimes_Timezone(t *testing.T) {
+	sched := DefaultSchedule() // 9-17 mon-fri
+	sched.Timezone = "Asia/Tokyo"
+	// Monday 10:00 UTC is 19:00 in Tokyo, after hours there.
+	now := time.Date(2026, 2, 9, 10, 0, 0, 0, time.UTC)
+
+	entries := []history.Entry{{ID: "a", Message: "Hello", Status: "queued"}}
+	predictions := PredictPublishTimes(entries, sched, time.Time{}, now)
+	if len(predictions) != 1 {
+		t.Fatalf("expected 1 prediction, got %d", len(predictions))
+	}
+	// Tuesday 09:00 in Tokyo is Tuesday 00:00 UTC.
+	want := time.Date(2026, 2, 10, 0, 0, 0, 0, time.UTC)
+	if !predictions[0].PublishAt.Equal(want) {
+		t.Errorf("PublishAt = %v, want %v", predictions[0].PublishAt, want)
+	}
+}
+
+func TestPredictPublishTimes_PriorityOrder(t *testing.T) {
+	sched := DefaultSchedule()                         // 9-17 mon-fri, 180min
+	now := time.Date(2026, 2, 9, 9, 0, 0, 0, time.UTC) // Monday 09:00
+
+	entries := []history.Entry{
+		{ID: "a1", Message: "Filler", Status: "queued"},
+		{ID: "a2", Message: "Urgent", Status: "queued", Priority: 1},
+	}
+
+	predictions := PredictPublishTimes(entries, sched, time.Time{}, now)
+	if len(predictions) != 2 {
+		t.Fatalf("expected 2 predictions, got %d", len(predictions))
+	}
+	if predictions[0].Entry.ID != "a2" {
+		t.Errorf("first prediction = %s, want a2 (higher priority)", predictions[0].Entry.ID)
+	}
+	if !predictions[0].PublishAt.Equal(now) {
+		t.Errorf("PublishAt = %v, want %v", predictions[0].PublishAt, now)
+	}
+}
+
+func TestPredictPublishTimes_RecurringInstances(t *testing.T) {
+	sched := DefaultSchedule()                          // 9-17 mon-fri, 180min
+	now := time.Date(2026, 2, 9, 10, 0, 0, 0, time.UTC) // Monday 10:00
+
+	entries := []history.Entry{
+		{ID: "r1", Message: "Fun friday #{{n}}", Status: "recurring",
+			Recurrence: &history.Recurrence{Every: "fri 16:00", NextAt: "2026-02-13T16:00:00Z", Count: 4}},
+		{ID: "r2", Message: "Sprint {{week}} learnings", Status: "recurring",
+			Recurrence: &history.Recurrence{Every: "sat 08:00", NextAt: "2026-02-14T08:00:00Z"}},
+		{ID: "r3", Message: "Paused", Status: "recurring",
+			Recurrence: &history.Recurrence{Every: "mon 10:00", NextAt: "2026-02-09T10:00:00Z", Paused: true}},
+		{ID: "a1", Message: "Queued", Status: "queued"},
+	}
+
+	predictions := PredictPublishTimes(entries, sched, time.Time{}, now)
+	if len(predictions) != 3 {
+		t.Fatalf("expected 3 predictions, got %d", len(predictions))
+	}
+
+	if predictions[0].Entry.ID != "a1" || predictions[0].Recurring {
+		t.Errorf("first prediction = %+v, want queued entry a1", predictions[0])
+	}
+
+	fri := predictions[1]
+	if !fri.Recurring || fri.Entry.RecurrenceID != "r1" || fri.Entry.ID != "" {
+		t.Errorf("second prediction = %+v, want instance of r1", fri)
+	}
+	if fri.Position != 2 || !fri.Approximate {
+		t.Errorf("Position = %d, Approximate = %v, want 2, true", fri.Position, fri.Approximate)
+	}
+	if fri.Entry.Message != "Fun friday #5" {
+		t.Errorf("Message = %q, want rendered instance", fri.Entry.Message)
+	}
+	if want := time.Date(2026, 2, 13, 16, 0, 0, 0, time.UTC); !fri.PublishAt.Equal(want) {
+		t.Errorf("PublishAt = %v, want %v", fri.PublishAt, want)
+	}
+
+	// Saturday occurrence publishes when the schedule is next active.
+	sat := predictions[2]
+	if sat.Entry.Message != "Sprint 7 learnings" {
+		t.Errorf("Message = %q, want rendered instance", sat.Entry.Message)
+	}
+	if want := time.Date(2026, 2, 16, 9, 0, 0, 0, time.UTC); !sat.PublishAt.Equal(want) {
+		t.Errorf("PublishAt = %v, want %v", sat.PublishAt, want)
+	}
+}
+
+func TestPredictPublishTimes_Series(t *testing.T) {
+	sched := DefaultSchedule()                         // 9-17 mon-fri, 180min
+	now := time.Date(2026, 2, 9, 9, 0, 0, 0, time.UTC) // Monday 09:00
+
+	tests := []struct {
+		name   string
+		strict bool
+		want   []string
+	}{
+		{"loose", false, []string{"p1", "x", "p2"}},
+		{"strict", true, []string{"p1", "p2", "x"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			entries := []history.Entry{
+				{ID: "p2", Status: "queued", Series: &history.SeriesPart{Name: "dive", Part: 2, Gap: "30m", Strict: tt.strict}},
+				{ID: "x", Status: "queued"},
+				{ID: "p1", Status: "queued", Series: &history.SeriesPart{Name: "dive", Part: 1, Gap: "30m", Strict: tt.strict}},
+			}
+			predictions := PredictPublishTimes(entries, sched, time.Time{}, now)
+			var got []string
+			for _, p := range predictions {
+				got = append(got, p.Entry.ID)
+			}
+			if !slices.Equal(got, tt.want) {
+				t.Fatalf("order = %v, want %v", got, tt.want)
+			}
+			if tt.strict {
+				if want := now.Add(30 * time.Minute); !predictions[1].PublishAt.Equal(want) {
+					t.Errorf("part 2 PublishAt = %v, want %v (one gap after part 1)", predictions[1].PublishAt, want)
+				}
+			}
+		})
+	}
+}
+
+func TestPredictPublishTimes_Paused(t *testing.T) {
+	now := time.Date(2026, 2, 9, 10, 0, 0, 0, time.UTC) // Monday 10:00
+	entries := []history.Entry{
+		{ID: "a1", Message: "first", Status: "queued"},
+		{ID: "a2", Message: "second", Status: "queued"},
+	}
+
+	sched := DefaultSchedule()
+	sched.Paused = true
+	sched.PausedUntil = "2026-02-09T13:30:00Z"
+	predictions := PredictPublishTimes(entries, sched, time.Time{}, now)
+	if want := time.Date(2026, 2, 9, 13, 30, 0, 0, time.UTC); !predictions[0].PublishAt.Equal(want) {
+		t.Errorf("PublishAt = %v, want %v (end of pause)", predictions[0].PublishAt, want)
+	}
+	if predictions[0].Approximate {
+		t.Error("first prediction after a timed pause should not be approximate")
+	}
+
+	// A pause ending outside active hours waits for the next window.
+	sched.PausedUntil = "2026-02-09T20:00:00Z"
+	predictions = PredictPublishTimes(entries, sched, time.Time{}, now)
+	if want := time.Date(2026, 2, 10, 9, 0, 0, 0, time.UTC); !predictions[0].PublishAt.Equal(want) {
+		t.Errorf("PublishAt = %v, want %v", predictions[0].PublishAt, want)
+	}
+
+	// An expired pause no longer applies.
+	sched.PausedUntil = "2026-02-09T09:00:00Z"
+	predictions = PredictPublishTimes(entries, sched, time.Time{}, now)
+	if !predictions[0].PublishAt.Equal(now) {
+		t.Errorf("PublishAt = %v, want %v", predictions[0].PublishAt, now)
+	}
+
+	// A pause with no end predicts as if lifted now, approximately.
+	sched.PausedUntil = ""
+	predictions = PredictPublishTimes(entries, sched, time.Time{}, now)
+	if !predictions[0].PublishAt.Equal(now) || !predictions[0].Approximate {
+		t.Errorf("got %v (approximate %v), want %v approximate", predictions[0].PublishAt, predictions[0].Approximate, now)
+	}
+}
+
+func TestPredictPublishTimes_Held(t *testing.T) {
+	sched := DefaultSchedule()                          // 9-17 mon-fri, 180min
+	now := time.Date(2026, 2, 9, 10, 0, 0, 0, time.UTC) // Monday 10:00
+	entries := []history.Entry{
+		{ID: "a1", Message: "held", Status: "queued", Held: true},
+		{ID: "s1", Message: "part 1", Status: "queued", Held: true, Series: &history.SeriesPart{Name: "dive", Part: 1}},
+		{ID: "s2", Message: "part 2", Status: "queued", Series: &history.SeriesPart{Name: "dive", Part: 2}},
+		{ID: "a2", Message: "free", Status: "queued"},
+	}
+
+	predictions := PredictPublishTimes(entries, sched, time.Time{}, now)
+	if len(predictions) != 4 {
+		t.Fatalf("expected 4 predictions, got %d", len(predictions))
+	}
+	for _, p := range predictions[:3] {
+		if !p.Held || !p.PublishAt.IsZero() {
+			t.Errorf("%s: Held = %v, PublishAt = %v; want held with no time", p.Entry.ID, p.Held, p.PublishAt)
+		}
+	}
+	if p := predictions[3]; p.Entry.ID != "a2" || p.Position != 4 || !p.PublishAt.Equal(now) {
+		t.Errorf("got %s at #%d %v, want a2 at #4 %v", p.Entry.ID, p.Position, p.PublishAt, now)
+	}
+}
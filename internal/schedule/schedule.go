@@ -3,17 +3,39 @@ package schedule
 import (
 	"fmt"
 	"slices"
-	"strconv"
 	"strings"
+	"sync"
 	"time"
 )
 
 // Schedule controls when automated posts are allowed.
 type Schedule struct {
-	PostEveryMinutes int      `json:"post_every_minutes"` // min time between posts (0 = no limit)
-	StartHour        int      `json:"start_hour"`         // 0-23 (default: 9)
-	EndHour          int      `json:"end_hour"`           // 0-23 (default: 18)
-	Weekdays         []string `json:"weekdays"`           // ["mon","tue",...] (default: mon-fri)
+	PostEveryMinutes int `json:"post_every_minutes"`       // min time between posts (0 = no limit)
+	JitterMinutes    int `json:"jitter_minutes,omitempty"` // max random delay per slot (0 = off); see JitterAt
+
+	// Caps limit posts per day and week; TagCaps do so for posts with a
+	// given (lowercase) tag. They count the posts of the profile the
+	// schedule belongs to, not of its webhook's channel: posts another
+	// profile sends to the same channel are not counted.
+	Caps
+	TagCaps map[string]Caps `json:"tag_caps,omitempty"`
+
+	// Windows maps a weekday ("mon".."sun") to its active windows, in
+	// order and not overlapping. Days without windows are inactive.
+	Windows map[string][]Window `json:"windows"`
+
+	// Timezone is the IANA zone (e.g. "Europe/Berlin") the windows are in.
+	// Empty means the machine's local zone.
+	Timezone string `json:"timezone,omitempty"`
+
+	// Blackouts are days on which the schedule is inactive regardless of
+	// Windows, e.g. holidays.
+	Blackouts []Blackout `json:"blackouts,omitempty"`
+
+	// Paused stops publishing until PausedUntil (RFC3339), or until resumed
+	// if PausedUntil is empty. Set by `queue pause`.
+	Paused      bool   `json:"paused,omitempty"`
+	PausedUntil string `json:"paused_until,omitempty"`
 }
 
 // DefaultSchedule returns a schedule with sensible defaults:
@@ -21,65 +43,116 @@ type Schedule struct {
 func DefaultSchedule() Schedule {
 	return Schedule{
 		PostEveryMinutes: 180,
-		StartHour:        9,
-		EndHour:          17,
-		Weekdays:         []string{"mon", "tue", "wed", "thu", "fri"},
+		Windows:          SameWindows(Weekdays[:5], Window{Start: At(9, 0), End: At(17, 0)}),
 	}
 }
 
-// IsActiveAt checks if the schedule is active at the given time.
+// IsActiveAt checks if the schedule is active at the given time. Without a
+// Timezone, t is read in the zone it carries.
 func (s Schedule) IsActiveAt(t time.Time) bool {
-	weekday := strings.ToLower(t.Weekday().String()[:3])
-	if !slices.Contains(s.Weekdays, weekday) {
+	t = s.in(t)
+	if _, ok := s.BlackoutAt(t); ok {
 		return false
 	}
-	hour := t.Hour()
-	return hour >= s.StartHour && hour < s.EndHour
+	now := At(t.Hour(), t.Minute())
+	return slices.ContainsFunc(s.Windows[weekdayKey(t)], func(w Window) bool { return w.contains(now) })
 }
 
-// IsActiveNow checks if the schedule is active right now.
-func (s Schedule) IsActiveNow() bool {
-	return s.IsActiveAt(time.Now())
+// weekdayKey returns the Windows key for t's weekday, e.g. "mon".
+func weekdayKey(t time.Time) string {
+	return strings.ToLower(t.Weekday().String()[:3])
 }
 
-// PostEvery returns the minimum interval between posts as a duration.
-func (s Schedule) PostEvery() time.Duration {
-	return time.Duration(s.PostEveryMinutes) * time.Minute
+// ActiveDays returns the weekdays that have windows, in week order.
+func (s Schedule) ActiveDays() []string {
+	var days []string
+	for _, d := range Weekdays {
+		if len(s.Windows[d]) > 0 {
+			days = append(days, d)
+		}
+	}
+	return days
 }
 
-// ParseHours parses "9-22" into start and end hour.
-func ParseHours(s string) (int, int, error) {
-	parts := strings.SplitN(s, "-", 2)
-	if len(parts) != 2 {
-		return 0, 0, fmt.Errorf("invalid hours format %q, expected START-END (e.g. 9-22)", s)
+// UniformWindows returns the windows shared by every active day, if all
+// active days have the same windows.
+func (s Schedule) UniformWindows() ([]Window, bool) {
+	days := s.ActiveDays()
+	if len(days) == 0 {
+		return nil, false
 	}
+	first := s.Windows[days[0]]
+	for _, d := range days[1:] {
+		if !slices.Equal(s.Windows[d], first) {
+			return nil, false
+		}
+	}
+	return first, true
+}
 
-	start, err := strconv.Atoi(strings.TrimSpace(parts[0]))
+// Location returns the schedule's time zone: Timezone if it names a known
+// zone, else the machine's local zone.
+func (s Schedule) Location() *time.Location {
+	if s.Timezone == "" {
+		return time.Local
+	}
+	loc, err := LoadLocation(s.Timezone)
 	if err != nil {
-		return 0, 0, fmt.Errorf("invalid start hour: %w", err)
+		return time.Local
 	}
+	return loc
+}
 
-	end, err := strconv.Atoi(strings.TrimSpace(parts[1]))
-	if err != nil {
-		return 0, 0, fmt.Errorf("invalid end hour: %w", err)
+// in converts t to the schedule's Timezone, if one is set.
+func (s Schedule) in(t time.Time) time.Time {
+	if s.Timezone == "" {
+		return t
 	}
+	return t.In(s.Location())
+}
 
-	if start < 0 || start > 23 {
-		return 0, 0, fmt.Errorf("start hour %d out of range 0-23", start)
+var zones sync.Map // IANA name -> *time.Location
+
+// LoadLocation is time.LoadLocation, cached: schedules look their zone up
+// for every time they check.
+func LoadLocation(name string) (*time.Location, error) {
+	if loc, ok := zones.Load(name); ok {
+		return loc.(*time.Location), nil
 	}
-	if end < 0 || end > 24 {
-		return 0, 0, fmt.Errorf("end hour %d out of range 0-24", end)
+	loc, err := time.LoadLocation(name)
+	if err != nil {
+		return nil, fmt.Errorf("unknown time zone %q, expected an IANA name like Europe/Berlin", name)
 	}
-	if start >= end {
-		return 0, 0, fmt.Errorf("start hour %d must be less than end hour %d", start, end)
+	zones.Store(name, loc)
+	return loc, nil
+}
+
+// IsActiveNow checks if the schedule is active right now.
+func (s Schedule) IsActiveNow() bool {
+	return s.IsActiveAt(time.Now())
+}
+
+// PausedAt reports whether publishing is paused at t, and when the pause
+// ends (zero if it lasts until resumed).
+func (s Schedule) PausedAt(t time.Time) (bool, time.Time) {
+	if !s.Paused {
+		return false, time.Time{}
+	}
+	until, err := time.Parse(time.RFC3339, s.PausedUntil)
+	if err != nil {
+		return true, time.Time{}
 	}
+	return t.Before(until), until
+}
 
-	return start, end, nil
+// PostEvery returns the minimum interval between posts as a duration.
+func (s Schedule) PostEvery() time.Duration {
+	return time.Duration(s.PostEveryMinutes) * time.Minute
 }
 
 // ParseWeekdays parses "mon-fri" or "mon,wed,fri" into a slice of weekday abbreviations.
 func ParseWeekdays(s string) ([]string, error) {
-	valid := []string{"mon", "tue", "wed", "thu", "fri", "sat", "sun"}
+	valid := Weekdays
 
 	// Range format: "mon-fri"
 	if parts := strings.SplitN(s, "-", 2); len(parts) == 2 && !strings.Contains(s, ",") {
@@ -99,7 +172,7 @@ func ParseWeekdays(s string) ([]string, error) {
 			return nil, fmt.Errorf("start weekday %q must come before end %q", start, end)
 		}
 
-		return valid[startIdx : endIdx+1], nil
+		return slices.Clone(valid[startIdx : endIdx+1]), nil
 	}
 
 	// List format: "mon,wed,fri"
@@ -11,20 +11,22 @@ func TestDefaultSchedule(t *testing.T) {
 	if s.PostEveryMinutes != 180 {
 		t.Errorf("PostEveryMinutes = %d, want 180", s.PostEveryMinutes)
 	}
-	if s.StartHour != 9 {
-		t.Errorf("StartHour = %d, want 9", s.StartHour)
+	windows, ok := s.UniformWindows()
+	if !ok || len(windows) != 1 {
+		t.Fatalf("UniformWindows() = %v, %v; want one shared window", windows, ok)
 	}
-	if s.EndHour != 17 {
-		t.Errorf("EndHour = %d, want 17", s.EndHour)
+	if windows[0] != (Window{Start: At(9, 0), End: At(17, 0)}) {
+		t.Errorf("window = %s, want 09:00-17:00", windows[0])
 	}
 
 	wantDays := []string{"mon", "tue", "wed", "thu", "fri"}
-	if len(s.Weekdays) != len(wantDays) {
-		t.Fatalf("Weekdays len = %d, want %d", len(s.Weekdays), len(wantDays))
+	days := s.ActiveDays()
+	if len(days) != len(wantDays) {
+		t.Fatalf("ActiveDays len = %d, want %d", len(days), len(wantDays))
 	}
-	for i, d := range s.Weekdays {
+	for i, d := range days {
 		if d != wantDays[i] {
-			t.Errorf("Weekdays[%d] = %q, want %q", i, d, wantDays[i])
+			t.Errorf("ActiveDays[%d] = %q, want %q", i, d, wantDays[i])
 		}
 	}
 }
@@ -81,9 +83,7 @@ func TestIsActiveAt(t *testing.T) {
 
 func TestIsActiveAt_CustomSchedule(t *testing.T) {
 	s := Schedule{
-		StartHour: 9,
-		EndHour:   22,
-		Weekdays:  []string{"mon", "tue", "wed", "thu", "fri", "sat", "sun"},
+		Windows: SameWindows(Weekdays, Window{Start: At(9, 0), End: At(22, 0)}),
 	}
 
 	// Saturday 15:00 should be active with this custom schedule.
@@ -93,6 +93,48 @@ func TestIsActiveAt_CustomSchedule(t *testing.T) {
 	}
 }
 
+func TestIsActiveAt_Timezone(t *testing.T) {
+	s := DefaultSchedule() // 9-17 mon-fri
+	s.Timezone = "America/New_York"
+
+	// Instants in UTC; New York is UTC-5 in winter and UTC-4 in summer.
+	tests := []struct {
+		name string
+		t    time.Time
+		want bool
+	}{
+		{"before spring forward, 08:30 EST", time.Date(2026, 3, 6, 13, 30, 0, 0, time.UTC), false},
+		{"before spring forward, 09:30 EST", time.Date(2026, 3, 6, 14, 30, 0, 0, time.UTC), true},
+		{"after spring forward, 09:30 EDT", time.Date(2026, 3, 9, 13, 30, 0, 0, time.UTC), true},
+		{"after spring forward, 17:30 EDT", time.Date(2026, 3, 9, 21, 30, 0, 0, time.UTC), false},
+		{"before fall back, 09:30 EDT", time.Date(2026, 10, 30, 13, 30, 0, 0, time.UTC), true},
+		{"after fall back, 08:30 EST", time.Date(2026, 11, 2, 13, 30, 0, 0, time.UTC), false},
+		{"after fall back, 09:30 EST", time.Date(2026, 11, 2, 14, 30, 0, 0, time.UTC), true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := s.IsActiveAt(tt.t); got != tt.want {
+				t.Errorf("IsActiveAt(%v) = %v, want %v", tt.t, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestLocation(t *testing.T) {
+	if got := (Schedule{}).Location(); got != time.Local {
+		t.Errorf("Location() without Timezone = %v, want Local", got)
+	}
+	if got := (Schedule{Timezone: "Asia/Tokyo"}).Location(); got.String() != "Asia/Tokyo" {
+		t.Errorf("Location() = %v, want Asia/Tokyo", got)
+	}
+	if got := (Schedule{Timezone: "Mars/Olympus"}).Location(); got != time.Local {
+		t.Errorf("Location() for unknown zone = %v, want Local", got)
+	}
+	if _, err := LoadLocation("Mars/Olympus"); err == nil {
+		t.Error("LoadLocation(Mars/Olympus) succeeded, want error")
+	}
+}
+
 func TestPostEvery(t *testing.T) {
 	tests := []struct {
 		minutes int
@@ -111,36 +153,36 @@ func TestPostEvery(t *testing.T) {
 	}
 }
 
-func TestParseHours(t *testing.T) {
+func TestParseRanges(t *testing.T) {
 	tests := []struct {
-		input     string
-		wantStart int
-		wantEnd   int
-		wantErr   bool
+		input   string
+		want    string // formatted windows
+		wantErr bool
 	}{
-		{"9-22", 9, 22, false},
-		{"0-24", 0, 24, false},
-		{"25-10", 0, 0, true},  // start out of range
-		{"abc", 0, 0, true},    // not START-END format
-		{"10-5", 0, 0, true},   // start >= end
-		{"9-25", 0, 0, true},   // end out of range
-		{"-1-10", 0, 0, true},  // negative start
-		{"abc-10", 0, 0, true}, // non-numeric start
-		{"9-abc", 0, 0, true},  // non-numeric end
+		{"9-22", "09:00-22:00", false},
+		{"0-24", "00:00-24:00", false},
+		{"09:30-12:00,14:00-17:30", "09:30-12:00,14:00-17:30", false},
+		{"14:00-17:30, 9:30-12", "09:30-12:00,14:00-17:30", false}, // sorted
+		{"25-10", "", true},                                        // start out of range
+		{"abc", "", true},                                          // not START-END format
+		{"10-5", "", true},                                         // start >= end
+		{"9-25", "", true},                                         // end out of range
+		{"-1-10", "", true},                                        // negative start
+		{"abc-10", "", true},                                       // non-numeric start
+		{"9-abc", "", true},                                        // non-numeric end
+		{"09:60-10:00", "", true},                                  // minutes out of range
+		{"09:00-12:00,11:00-13:00", "", true},                      // overlapping
 	}
 
 	for _, tt := range tests {
 		t.Run(tt.input, func(t *testing.T) {
-			start, end, err := ParseHours(tt.input)
+			windows, err := ParseRanges(tt.input)
 			if (err != nil) != tt.wantErr {
-				t.Fatalf("ParseHours(%q) error = %v, wantErr %v", tt.input, err, tt.wantErr)
+				t.Fatalf("ParseRanges(%q) error = %v, wantErr %v", tt.input, err, tt.wantErr)
 			}
 			if err == nil {
-				if start != tt.wantStart {
-					t.Errorf("start = %d, want %d", start, tt.wantStart)
-				}
-				if end != tt.wantEnd {
-					t.Errorf("end = %d, want %d", end, tt.wantEnd)
+				if got := FormatRanges(windows); got != tt.want {
+					t.Errorf("windows = %s, want %s", got, tt.want)
 				}
 			}
 		})
@@ -0,0 +1,182 @@
+package schedule
+
+import (
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// Weekdays lists the weekday keys of Schedule.Windows in week order.
+var Weekdays = []string{"mon", "tue", "wed", "thu", "fri", "sat", "sun"}
+
+// Clock is a time of day in minutes since midnight. It is written as
+// "HH:MM"; "24:00" is the end of the day.
+type Clock int
+
+// At returns the Clock for hour:minute.
+func At(hour, minute int) Clock { return Clock(hour*60 + minute) }
+
+func (c Clock) String() string { return fmt.Sprintf("%02d:%02d", c/60, c%60) }
+
+// MarshalText implements encoding.TextMarshaler.
+func (c Clock) MarshalText() ([]byte, error) { return []byte(c.String()), nil }
+
+// UnmarshalText implements encoding.TextUnmarshaler.
+func (c *Clock) UnmarshalText(text []byte) error {
+	parsed, err := parseClock(string(text))
+	if err != nil {
+		return err
+	}
+	*c = parsed
+	return nil
+}
+
+// parseClock parses "H", "HH", or "HH:MM" between 00:00 and 24:00.
+func parseClock(s string) (Clock, error) {
+	s = strings.TrimSpace(s)
+	hourStr, minuteStr, hasMinutes := strings.Cut(s, ":")
+	hour, err := strconv.Atoi(hourStr)
+	if err != nil || hourStr == "" || strings.HasPrefix(hourStr, "-") || strings.HasPrefix(hourStr, "+") {
+		return 0, fmt.Errorf("invalid time %q, expected HH:MM", s)
+	}
+	minute := 0
+	if hasMinutes {
+		if len(minuteStr) != 2 {
+			return 0, fmt.Errorf("invalid time %q, expected HH:MM", s)
+		}
+		if minute, err = strconv.Atoi(minuteStr); err != nil || minute < 0 || minute > 59 {
+			return 0, fmt.Errorf("invalid time %q, expected HH:MM", s)
+		}
+	}
+	c := At(hour, minute)
+	if hour < 0 || c > At(24, 0) {
+		return 0, fmt.Errorf("time %q out of range 00:00-24:00", s)
+	}
+	return c, nil
+}
+
+// Window is an active period within a day. End is exclusive.
+type Window struct {
+	Start Clock `json:"start"`
+	End   Clock `json:"end"`
+}
+
+func (w Window) String() string { return w.Start.String() + "-" + w.End.String() }
+
+// contains reports whether the time of day c falls in the window.
+func (w Window) contains(c Clock) bool { return c >= w.Start && c < w.End }
+
+// SameWindows returns a Windows map giving each of days the same windows.
+func SameWindows(days []string, windows ...Window) map[string][]Window {
+	m := make(map[string][]Window, len(days))
+	for _, d := range days {
+		m[d] = slices.Clone(windows)
+	}
+	return m
+}
+
+// ParseRanges parses comma-separated time ranges such as
+// "09:30-12:00,14:00-17:30". Whole hours may omit the minutes ("9-17").
+// The ranges are returned in order and must not overlap.
+func ParseRanges(s string) ([]Window, error) {
+	var windows []Window
+	for r := range strings.SplitSeq(s, ",") {
+		startStr, endStr, ok := strings.Cut(strings.TrimSpace(r), "-")
+		if !ok {
+			return nil, fmt.Errorf("invalid range %q, expected START-END (e.g. 09:30-12:00)", strings.TrimSpace(r))
+		}
+		start, err := parseClock(startStr)
+		if err != nil {
+			return nil, fmt.Errorf("invalid start: %w", err)
+		}
+		end, err := parseClock(endStr)
+		if err != nil {
+			return nil, fmt.Errorf("invalid end: %w", err)
+		}
+		if start >= end {
+			return nil, fmt.Errorf("start %s must be before end %s", start, end)
+		}
+		windows = append(windows, Window{Start: start, End: end})
+	}
+	return sortWindows(windows)
+}
+
+// sortWindows orders windows by start and rejects overlapping ones.
+func sortWindows(windows []Window) ([]Window, error) {
+	slices.SortFunc(windows, func(a, b Window) int { return int(a.Start - b.Start) })
+	for i := 1; i < len(windows); i++ {
+		if windows[i].Start < windows[i-1].End {
+			return nil, fmt.Errorf("windows %s and %s overlap", windows[i-1], windows[i])
+		}
+	}
+	return windows, nil
+}
+
+// ParseWindows parses per-weekday windows: semicolon-separated groups of
+// weekdays (as for ParseWeekdays) followed by ranges (as for ParseRanges),
+// e.g. "mon-thu 09:30-12:00,14:00-17:30; fri 10:00-13:00". Days not named
+// are inactive.
+func ParseWindows(spec string) (map[string][]Window, error) {
+	m := make(map[string][]Window)
+	for group := range strings.SplitSeq(spec, ";") {
+		group = strings.TrimSpace(group)
+		if group == "" {
+			continue
+		}
+		daysStr, rangesStr, ok := strings.Cut(group, " ")
+		if !ok {
+			return nil, fmt.Errorf("invalid group %q, expected DAYS RANGES (e.g. mon-fri 09:00-17:00)", group)
+		}
+		days, err := ParseWeekdays(daysStr)
+		if err != nil {
+			return nil, err
+		}
+		windows, err := ParseRanges(strings.ReplaceAll(rangesStr, " ", ""))
+		if err != nil {
+			return nil, err
+		}
+		for _, d := range days {
+			merged, err := sortWindows(append(m[d], windows...))
+			if err != nil {
+				return nil, fmt.Errorf("%s: %w", d, err)
+			}
+			m[d] = merged
+		}
+	}
+	if len(m) == 0 {
+		return nil, fmt.Errorf("no windows in %q, expected e.g. mon-fri 09:00-17:00", spec)
+	}
+	return m, nil
+}
+
+// FormatWindows renders windows in the syntax ParseWindows accepts,
+// grouping consecutive days that share the same windows.
+func FormatWindows(m map[string][]Window) string {
+	var groups []string
+	for i := 0; i < len(Weekdays); {
+		ws := m[Weekdays[i]]
+		j := i + 1
+		for j < len(Weekdays) && slices.Equal(m[Weekdays[j]], ws) {
+			j++
+		}
+		if len(ws) > 0 {
+			days := Weekdays[i]
+			if j-i > 1 {
+				days += "-" + Weekdays[j-1]
+			}
+			groups = append(groups, days+" "+FormatRanges(ws))
+		}
+		i = j
+	}
+	return strings.Join(groups, "; ")
+}
+
+// FormatRanges renders windows in the syntax ParseRanges accepts.
+func FormatRanges(windows []Window) string {
+	parts := make([]string, len(windows))
+	for i, w := range windows {
+		parts[i] = w.String()
+	}
+	return strings.Join(parts, ",")
+}
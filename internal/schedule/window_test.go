@@ -0,0 +1,95 @@
+package schedule
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+)
+
+// splitWeek is Mon–Thu 09:30–12:00 and 14:00–17:30, Fri 10:00–13:00.
+const splitWeek = "mon-thu 09:30-12:00,14:00-17:30; fri 10:00-13:00"
+
+func TestParseWindows(t *testing.T) {
+	m, err := ParseWindows(splitWeek)
+	if err != nil {
+		t.Fatalf("ParseWindows: %v", err)
+	}
+	if got := FormatRanges(m["tue"]); got != "09:30-12:00,14:00-17:30" {
+		t.Errorf("tue = %s", got)
+	}
+	if got := FormatRanges(m["fri"]); got != "10:00-13:00" {
+		t.Errorf("fri = %s", got)
+	}
+	if len(m["sat"]) != 0 {
+		t.Errorf("sat = %v, want inactive", m["sat"])
+	}
+	if got := FormatWindows(m); got != splitWeek {
+		t.Errorf("FormatWindows = %q, want %q", got, splitWeek)
+	}
+
+	// Groups naming the same day add to its windows.
+	m, err = ParseWindows("mon-fri 09:00-12:00; mon 13:00-15:00")
+	if err != nil {
+		t.Fatalf("ParseWindows: %v", err)
+	}
+	if got := FormatWindows(m); got != "mon 09:00-12:00,13:00-15:00; tue-fri 09:00-12:00" {
+		t.Errorf("FormatWindows = %q", got)
+	}
+
+	for _, bad := range []string{"", "mon-fri", "funday 09:00-10:00", "mon 10:00-09:00", "mon 09:00-12:00; mon 11:00-13:00"} {
+		if _, err := ParseWindows(bad); err == nil {
+			t.Errorf("ParseWindows(%q) = nil error, want error", bad)
+		}
+	}
+}
+
+func TestWindows_ActiveAndAdvance(t *testing.T) {
+	windows, err := ParseWindows(splitWeek)
+	if err != nil {
+		t.Fatal(err)
+	}
+	s := Schedule{Windows: windows}
+
+	tests := []struct {
+		name   string
+		t      time.Time
+		active bool
+		next   time.Time
+	}{
+		{"Monday 09:29 before the first window", time.Date(2026, 2, 9, 9, 29, 0, 0, time.UTC), false, time.Date(2026, 2, 9, 9, 30, 0, 0, time.UTC)},
+		{"Monday 11:59 in the morning window", time.Date(2026, 2, 9, 11, 59, 0, 0, time.UTC), true, time.Date(2026, 2, 9, 11, 59, 0, 0, time.UTC)},
+		{"Monday 12:00 lunch gap", time.Date(2026, 2, 9, 12, 0, 0, 0, time.UTC), false, time.Date(2026, 2, 9, 14, 0, 0, 0, time.UTC)},
+		{"Thursday 17:30 after the last window", time.Date(2026, 2, 12, 17, 30, 0, 0, time.UTC), false, time.Date(2026, 2, 13, 10, 0, 0, 0, time.UTC)},
+		{"Friday 13:00 skips the weekend", time.Date(2026, 2, 13, 13, 0, 0, 0, time.UTC), false, time.Date(2026, 2, 16, 9, 30, 0, 0, time.UTC)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := s.IsActiveAt(tt.t); got != tt.active {
+				t.Errorf("IsActiveAt = %v, want %v", got, tt.active)
+			}
+			if got := AdvanceToActive(tt.t, s); !got.Equal(tt.next) {
+				t.Errorf("AdvanceToActive = %v, want %v", got, tt.next)
+			}
+		})
+	}
+}
+
+func TestWindow_JSON(t *testing.T) {
+	data, err := json.Marshal(Window{Start: At(9, 30), End: At(24, 0)})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(data) != `{"start":"09:30","end":"24:00"}` {
+		t.Errorf("Marshal = %s", data)
+	}
+	var w Window
+	if err := json.Unmarshal([]byte(`{"start":"7:05","end":"12:00"}`), &w); err != nil {
+		t.Fatal(err)
+	}
+	if w != (Window{Start: At(7, 5), End: At(12, 0)}) {
+		t.Errorf("Unmarshal = %s", w)
+	}
+	if err := json.Unmarshal([]byte(`{"start":"25:00","end":"26:00"}`), &w); err == nil {
+		t.Error("Unmarshal accepted 25:00")
+	}
+}
@@ -0,0 +1,94 @@
+// Package similarity scores how alike two short texts are, for catching
+// near-duplicate posts.
+package similarity
+
+import (
+	"hash/fnv"
+	"strings"
+	"unicode"
+)
+
+// shingleSize is the length, in runes, of each character shingle. Five is
+// long enough to ignore common letter pairs and short enough that a reworded
+// sentence still shares most shingles with the original.
+const shingleSize = 5
+
+// Normalize lowercases text, strips punctuation and Slack formatting, and
+// collapses whitespace so that cosmetic edits do not affect the score.
+func Normalize(text string) string {
+	var b strings.Builder
+	space := false
+	for _, r := range strings.ToLower(text) {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			if space && b.Len() > 0 {
+				b.WriteByte(' ')
+			}
+			b.WriteRune(r)
+			space = false
+			continue
+		}
+		space = true
+	}
+	return b.String()
+}
+
+// Shingles returns the set of hashed character shingles of the normalized text.
+// Texts shorter than one shingle yield a single shingle of the whole text.
+func Shingles(text string) map[uint64]struct{} {
+	runes := []rune(Normalize(text))
+	set := make(map[uint64]struct{})
+	if len(runes) == 0 {
+		return set
+	}
+	if len(runes) < shingleSize {
+		set[hash(runes)] = struct{}{}
+		return set
+	}
+	for i := 0; i+shingleSize <= len(runes); i++ {
+		set[hash(runes[i:i+shingleSize])] = struct{}{}
+	}
+	return set
+}
+
+// Score returns the Jaccard similarity of the two texts' shingle sets,
+// from 0 (nothing in common) to 1 (identical after normalization).
+func Score(a, b string) float64 {
+	return jaccard(Shingles(a), Shingles(b))
+}
+
+// Match is the closest candidate found by Best.
+type Match struct {
+	Index int     // index into the candidates slice
+	Score float64 // similarity, 0..1
+}
+
+// Best returns the candidate most similar to text. ok is false when there
+// are no candidates or none shares a shingle with text.
+func Best(text string, candidates []string) (m Match, ok bool) {
+	want := Shingles(text)
+	for i, c := range candidates {
+		if s := jaccard(want, Shingles(c)); s > m.Score {
+			m, ok = Match{Index: i, Score: s}, true
+		}
+	}
+	return m, ok
+}
+
+func jaccard(a, b map[uint64]struct{}) float64 {
+	if len(a) == 0 || len(b) == 0 {
+		return 0
+	}
+	shared := 0
+	for h := range a {
+		if _, ok := b[h]; ok {
+			shared++
+		}
+	}
+	return float64(shared) / float64(len(a)+len(b)-shared)
+}
+
+func hash(runes []rune) uint64 {
+	h := fnv.New64a()
+	_, _ = h.Write([]byte(string(runes)))
+	return h.Sum64()
+}
@@ -0,0 +1,48 @@
+package similarity
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNormalize(t *testing.T) {
+	assert.Equal(t, "r go til use errors is", Normalize("*r/go* — TIL: use `errors.Is`!"))
+	assert.Equal(t, "", Normalize("  ...  "))
+}
+
+func TestScore_Identical(t *testing.T) {
+	assert.InDelta(t, 1.0, Score("Hello, world!", "hello world"), 1e-9)
+}
+
+func TestScore_NearDuplicate(t *testing.T) {
+	a := "r/go\nTIL: errors.Is walks the whole wrap chain, so you rarely need errors.As just to compare sentinels."
+	b := "r/go\nTIL errors.Is walks the entire wrap chain, so you rarely need errors.As to compare sentinels."
+	assert.Greater(t, Score(a, b), 0.6)
+}
+
+func TestScore_Unrelated(t *testing.T) {
+	a := "r/go\nTIL: errors.Is walks the whole wrap chain."
+	b := "r/ai\nHot take: agents should write their own tests before touching prod."
+	assert.Less(t, Score(a, b), 0.2)
+}
+
+func TestScore_Empty(t *testing.T) {
+	assert.Zero(t, Score("", "something"))
+	assert.Zero(t, Score("!!!", "???"))
+}
+
+func TestBest(t *testing.T) {
+	candidates := []string{
+		"completely different topic about python packaging",
+		"TIL: errors.Is walks the whole wrap chain",
+		"TIL: errors.Is walks the wrap chain",
+	}
+	m, ok := Best("TIL: errors.Is walks the whole wrap chain!", candidates)
+	assert.True(t, ok)
+	assert.Equal(t, 1, m.Index)
+	assert.InDelta(t, 1.0, m.Score, 1e-9)
+
+	_, ok = Best("anything", nil)
+	assert.False(t, ok)
+}
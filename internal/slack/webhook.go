@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"io"
 	"net/http"
+	"regexp"
 	"strings"
 	"time"
 )
@@ -13,12 +14,21 @@ import (
 var httpClient = &http.Client{Timeout: 10 * time.Second}
 
 type payload struct {
-	Text string `json:"text"`
+	Text     string `json:"text"`
+	ThreadTS string `json:"thread_ts,omitempty"`
 }
 
 // SendWebhook posts a text message to the given Slack webhook URL.
 func SendWebhook(webhookURL, message string) error {
-	body, err := json.Marshal(payload{Text: message})
+	return SendWebhookReply(webhookURL, message, "")
+}
+
+// SendWebhookReply posts a message as a reply in the thread of the message
+// with timestamp threadTS, or as a new message if threadTS is empty.
+// Incoming webhooks do not return the timestamp of what they post, so the
+// thread parent has to be an existing message.
+func SendWebhookReply(webhookURL, message, threadTS string) error {
+	body, err := json.Marshal(payload{Text: message, ThreadTS: threadTS})
 	if err != nil {
 		return fmt.Errorf("marshal payload: %w", err)
 	}
@@ -71,3 +81,30 @@ func VerifyWebhook(webhookURL string) error {
 		return fmt.Errorf("webhook returned unexpected status %d: %s", resp.StatusCode, bodyStr)
 	}
 }
+
+// permalinkTS matches the timestamp in a message permalink, e.g.
+// https://team.slack.com/archives/C0123/p1712345678123456.
+var permalinkTS = regexp.MustCompile(`/p(\d{10})(\d{6})(?:[?#]|$)`)
+
+// ParseThreadTS accepts a message timestamp ("1712345678.123456") or a
+// message permalink and returns the timestamp.
+func ParseThreadTS(s string) (string, error) {
+	s = strings.TrimSpace(s)
+	if m := permalinkTS.FindStringSubmatch(s); m != nil {
+		return m[1] + "." + m[2], nil
+	}
+	sec, frac, ok := strings.Cut(s, ".")
+	if ok && len(sec) > 0 && len(frac) > 0 && isDigits(sec) && isDigits(frac) {
+		return s, nil
+	}
+	return "", fmt.Errorf("invalid thread %q: want a message timestamp (1712345678.123456) or permalink", s)
+}
+
+func isDigits(s string) bool {
+	for _, c := range s {
+		if c < '0' || c > '9' {
+			return false
+		}
+	}
+	return true
+}
@@ -1,6 +1,7 @@
 package slack
 
 import (
+	"io"
 	"net/http"
 	"net/http/httptest"
 	"testing"
@@ -91,3 +92,42 @@ func TestSendWebhook_Error(t *testing.T) {
 	require.Error(t, err)
 	assert.Contains(t, err.Error(), "500")
 }
+
+func TestSendWebhookReply_ThreadTS(t *testing.T) {
+	var body string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		b, _ := io.ReadAll(r.Body)
+		body = string(b)
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer srv.Close()
+
+	require.NoError(t, SendWebhookReply(srv.URL, "part 2", "1712345678.123456"))
+	assert.JSONEq(t, `{"text": "part 2", "thread_ts": "1712345678.123456"}`, body)
+
+	require.NoError(t, SendWebhook(srv.URL, "plain"))
+	assert.JSONEq(t, `{"text": "plain"}`, body)
+}
+
+func TestParseThreadTS(t *testing.T) {
+	tests := []struct {
+		in, want string
+		wantErr  bool
+	}{
+		{"1712345678.123456", "1712345678.123456", false},
+		{"https://team.slack.com/archives/C0123ABC/p1712345678123456", "1712345678.123456", false},
+		{"https://team.slack.com/archives/C0123ABC/p1712345678123456?thread_ts=1.2&cid=C0123ABC", "1712345678.123456", false},
+		{"1712345678", "", true},
+		{"https://team.slack.com/archives/C0123ABC", "", true},
+		{"abc.def", "", true},
+	}
+	for _, tt := range tests {
+		got, err := ParseThreadTS(tt.in)
+		if tt.wantErr {
+			assert.Error(t, err, tt.in)
+			continue
+		}
+		require.NoError(t, err, tt.in)
+		assert.Equal(t, tt.want, got)
+	}
+}
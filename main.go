@@ -2,30 +2,45 @@ package main
 
 import (
 	"errors"
+	"fmt"
 	"os"
+	"path/filepath"
 	"strings"
 
 	"github.com/alecthomas/kong"
 	"github.com/charmbracelet/huh"
+
+	"github.com/lvrach/slack-social-ai/internal/config"
+	"github.com/lvrach/slack-social-ai/internal/history"
+	"github.com/lvrach/slack-social-ai/internal/paths"
 )
 
 // Globals holds flags shared across all commands.
 type Globals struct {
-	JSON bool `help:"Output JSON for LLM/script consumption." short:"j"`
+	JSON    bool   `help:"Output JSON for LLM/script consumption." short:"j"`
+	Profile string `help:"Use an isolated named instance (own history, config, and webhook)." env:"SLACK_SOCIAL_AI_PROFILE"`
+	Home    string `help:"Keep all data, config, and logs in this directory instead of the XDG locations." env:"SLACK_SOCIAL_AI_HOME"`
 }
 
 // CLI is the root command structure for slack-social-ai.
 type CLI struct {
 	Globals
 
-	Init     InitCmd     `cmd:"" help:"Configure Slack webhook (interactive setup)."`
-	Auth     AuthCmd     `cmd:"" help:"Manage webhook credentials (login, logout, status)."`
-	Post     PostCmd     `cmd:"" help:"Queue a message for publishing (use --now to publish immediately)."`
-	Queue    QueueCmd    `cmd:"" help:"Show or manage the post queue."`
-	Publish  PublishCmd  `cmd:"" help:"Publish the next queued message to Slack (typically run by scheduler)."`
-	Schedule ScheduleCmd `cmd:"" help:"Configure the publishing schedule (hours, weekdays, frequency)."`
-	History  HistoryCmd  `cmd:"" help:"Show or manage post history."`
-	Guide    GuideCmd    `cmd:"" help:"Print the posting guide — designed for LLM agents to learn how to compose posts."`
+	Init      InitCmd      `cmd:"" help:"Configure Slack webhook (interactive setup)."`
+	Auth      AuthCmd      `cmd:"" help:"Manage webhook credentials (login, logout, status)."`
+	Post      PostCmd      `cmd:"" help:"Queue a message for publishing (use --now to publish immediately)."`
+	Queue     QueueCmd     `cmd:"" help:"Show or manage the post queue."`
+	Recurring RecurringCmd `cmd:"" help:"List, pause, resume, or delete recurring posts (post --every)."`
+	Series    SeriesCmd    `cmd:"" help:"List, change, or remove post series (post --series)."`
+	Publish   PublishCmd   `cmd:"" help:"Publish the next queued message to Slack (typically run by scheduler)."`
+	Schedule  ScheduleCmd  `cmd:"" help:"Configure the publishing schedule (hours, weekdays, frequency)."`
+	History   HistoryCmd   `cmd:"" help:"Show or manage post history."`
+	Search    SearchCmd    `cmd:"" help:"Search post history by text, tag, or status."`
+	Config    ConfigCmd    `cmd:"" help:"Show or change general settings."`
+	Audit     AuditCmd     `cmd:"" help:"Show the audit log of queue and history changes."`
+	Undo      UndoCmd      `cmd:"" help:"Restore entries removed by the last remove or clear (--list for older ones)."`
+	Doctor    DoctorCmd    `cmd:"" help:"Check for stale locks and unreadable files (--fix to repair)."`
+	Guide     GuideCmd     `cmd:"" help:"Print the posting guide — designed for LLM agents to learn how to compose posts."`
 }
 
 func main() {
@@ -35,24 +50,34 @@ func main() {
 		kong.Description("Post messages to Slack from the terminal."),
 		kong.UsageOnError(),
 	)
-	err := ctx.Run(&cli.Globals)
+	err := selectInstance(cli.Globals)
+	if err == nil {
+		history.SetAuditContext(auditActorName(os.Getenv), auditCommandName(ctx.Command()))
+		if cfg, cfgErr := config.Load(); cfgErr == nil {
+			history.SetLockTimeout(cfg.LockTimeout())
+		}
+		err = ctx.Run(&cli.Globals)
+	}
 	if err != nil {
 		// Ctrl+C / Ctrl+D — exit silently.
 		if isUserAbort(err) {
 			os.Exit(0)
 		}
 
+		if hErr := historyError(err); hErr != nil {
+			err = hErr
+		}
 		var cliErr *CLIError
 		if ok := asCLIError(err, &cliErr); ok {
 			if cli.JSON {
-				printErrorJSON(cliErr.Message, cliErr.Code)
+				printErrorJSON(cliErr.Message, cliErr.Code, cliErr.Details)
 			} else {
 				printErrorHuman(cliErr.Message)
 			}
 			os.Exit(cliErr.ExitCode)
 		}
 		if cli.JSON {
-			printErrorJSON(err.Error(), "runtime_error")
+			printErrorJSON(err.Error(), "runtime_error", nil)
 		} else {
 			printErrorHuman(err.Error())
 		}
@@ -60,6 +85,22 @@ func main() {
 	}
 }
 
+// selectInstance applies --home/--profile and moves files left at pre-XDG
+// locations into place.
+func selectInstance(globals Globals) error {
+	if err := paths.Set(globals.Home, globals.Profile); err != nil {
+		return newCLIError(ExitInvalidInput, "invalid_profile", err.Error())
+	}
+	moved, err := history.MigratePaths()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: failed to migrate files to XDG locations: %s\n", err)
+	}
+	for _, path := range moved {
+		fmt.Fprintf(os.Stderr, "Moved %s to %s.\n", filepath.Base(path), filepath.Dir(path))
+	}
+	return nil
+}
+
 // isUserAbort returns true for errors caused by the user
 // quitting an interactive prompt (Ctrl+C, Ctrl+D).
 // It intentionally does NOT match io.EOF via errors.Is because
@@ -20,6 +20,7 @@ type CLIError struct {
 	ExitCode int
 	Code     string
 	Message  string
+	Details  map[string]any // extra machine-readable fields for --json output
 }
 
 func (e *CLIError) Error() string { return e.Message }
@@ -36,9 +37,10 @@ func newCLIError(exitCode int, code, message string) *CLIError {
 
 // JSON response types.
 type jsonResponse struct {
-	Status  string `json:"status"`
-	Message string `json:"message,omitempty"`
-	Error   string `json:"error,omitempty"`
+	Status  string         `json:"status"`
+	Message string         `json:"message,omitempty"`
+	Error   string         `json:"error,omitempty"`
+	Details map[string]any `json:"details,omitempty"`
 }
 
 func printSuccessJSON(message string) {
@@ -47,8 +49,8 @@ func printSuccessJSON(message string) {
 	fmt.Fprintln(os.Stdout, string(b))
 }
 
-func printErrorJSON(message, code string) {
-	resp := jsonResponse{Status: "error", Error: code, Message: message}
+func printErrorJSON(message, code string, details map[string]any) {
+	resp := jsonResponse{Status: "error", Error: code, Message: message, Details: details}
 	b, _ := json.Marshal(resp)
 	fmt.Fprintln(os.Stderr, string(b))
 }
@@ -6,17 +6,29 @@ import (
 	"os"
 	"time"
 
+	"github.com/lvrach/slack-social-ai/internal/config"
 	"github.com/lvrach/slack-social-ai/internal/history"
 	"github.com/lvrach/slack-social-ai/internal/keyring"
+	"github.com/lvrach/slack-social-ai/internal/recur"
+	"github.com/lvrach/slack-social-ai/internal/schedule"
 	"github.com/lvrach/slack-social-ai/internal/slack"
 )
 
 // PostCmd queues a message for publishing (default) or publishes immediately.
 type PostCmd struct {
-	MessageInput `embed:""`
-	Now          bool   `help:"Publish immediately, skip the queue." short:"N" xor:"mode"`
-	DryRun       bool   `help:"Preview the message without publishing or queuing." short:"n" xor:"mode"`
-	At           string `help:"Schedule for a future time (HH:MM, duration like 2h, or RFC3339)." short:"a" xor:"mode"`
+	MessageInput  `embed:""`
+	Provenance    `embed:""`
+	Now           bool   `help:"Publish immediately, skip the queue." short:"N" xor:"mode,draft,series"`
+	DryRun        bool   `help:"Preview the message without publishing or queuing." short:"n" xor:"mode,draft"`
+	Draft         bool   `help:"Save as a draft that must be approved (queue approve) before publishing." short:"D" xor:"draft"`
+	At            string `help:"Schedule for a future time (HH:MM, duration like 2h, or RFC3339)." short:"a" xor:"mode"`
+	Every         string `help:"Repeat on a schedule (\"mon 10:00\", \"weekdays 12:00\", or cron). The message may use {{date}}, {{week}}, {{n}}, etc." short:"e" xor:"mode,series"`
+	Series        string `help:"Queue as the next part of this series; parts publish in order." short:"S" xor:"series"`
+	SeriesOptions `embed:""`
+	Tag           []string `help:"Tag the post (repeatable). The r/<topic> header is tagged automatically." short:"t"`
+	Priority      int      `help:"Queue priority; higher publishes first (default 0)." short:"P"`
+	Force         bool     `help:"Post even if the message looks like a near-duplicate of a recent post." short:"f"`
+	Expires       string   `help:"Discard the post if still unpublished after this long, counted from --at if set (e.g. 3d, 12h, or \"never\"). Defaults to config default_ttl."`
 }
 
 func (cmd *PostCmd) Run(globals *Globals) error {
@@ -42,61 +54,259 @@ func (cmd *PostCmd) Run(globals *Globals) error {
 		message = "```\n" + message + "\n```"
 	}
 
-	// 4. Dry run — preview only.
+	// 4. Resolve tags from the r/<topic> header and --tag flags.
+	tags, err := resolveTags(message, cmd.Tag)
+	if err != nil {
+		return err
+	}
+
+	// 5. Record who queued the post.
+	prov := resolveProvenance(cmd.Provenance, message)
+
+	// 6. Series settings only make sense with --series.
+	if cmd.Series == "" && cmd.SeriesOptions.isSet() {
+		return newCLIError(ExitInvalidInput, "series_required",
+			"--gap, --strict, and --thread apply to a series; add --series <name>.")
+	}
+	seriesUpdate, err := cmd.SeriesOptions.update(false, false)
+	if err != nil {
+		return err
+	}
+
+	// 7. Dry run — preview only.
 	if cmd.DryRun {
-		return cmd.dryRun(globals, message)
+		return cmd.dryRun(globals, message, tags)
 	}
 
-	// 5. Publish immediately with --now.
-	if cmd.Now {
-		return cmd.publishNow(globals, webhookURL, message)
+	// 8. Refuse near-duplicates of recent posts unless --force.
+	cfg, _ := config.Load()
+	if !cmd.Force {
+		if err := checkDuplicate(message, cfg, time.Now()); err != nil {
+			return err
+		}
 	}
 
-	// 6. Parse --at if provided.
+	// 9. Decide whether the post needs human approval.
+	draft, forced := cmd.Draft, false
+	if !draft && cfg.RequireApproval && nonInteractive(prov) {
+		draft, forced = true, true
+	}
+
+	// 10. Publish immediately with --now.
+	if cmd.Now && !draft {
+		return cmd.publishNow(globals, webhookURL, newEntry(message, "published", tags, prov))
+	}
+
+	// 11. Save a recurring template with --every.
+	if cmd.Every != "" {
+		return cmd.saveRecurring(globals, cfg, newEntry(message, "recurring", tags, prov), draft, forced)
+	}
+
+	// 12. Parse --at if provided.
 	var scheduledAt time.Time
 	if cmd.At != "" {
-		scheduledAt, err = parseAt(cmd.At)
+		scheduledAt, err = parseAtFrom(cmd.At, time.Now().In(cfg.Schedule.Location()))
 		if err != nil {
 			return err
 		}
 	}
 
-	// 7. Queue the message.
-	entry, err := history.Append(message, "queued", scheduledAt)
+	// 13. Resolve the expiry from --expires or the configured default TTL,
+	// counted from the scheduled time so a post can't expire before it is due.
+	expiresAt, err := cmd.resolveExpiry(cfg, expiryBase(scheduledAt, time.Now()))
+	if err != nil {
+		return err
+	}
+
+	// 14. Queue the message (or save it as a draft).
+	status := "queued"
+	if draft {
+		status = "draft"
+	}
+	entry := newEntry(message, status, tags, prov)
+	entry.Priority = cmd.Priority
+	if !scheduledAt.IsZero() {
+		entry.ScheduledAt = scheduledAt.UTC().Format(time.RFC3339)
+	}
+	if !expiresAt.IsZero() {
+		entry.ExpiresAt = expiresAt.UTC().Format(time.RFC3339)
+	}
+	if !draft {
+		entry.PredictedAt = predictSlot(cfg, entry)
+	}
+	if cmd.Series != "" {
+		entry, err = history.AppendSeriesPart(entry, cmd.Series, seriesUpdate)
+	} else {
+		entry, err = history.AppendEntry(entry)
+	}
 	if err != nil {
-		return newCLIError(ExitRuntimeError, "queue_failed",
+		return historyFailure(err, "queue_failed",
 			fmt.Sprintf("Failed to queue message: %s", err))
 	}
 
-	// 8. Print confirmation.
+	// 15. Print confirmation.
+	cmd.printQueued(globals, entry, scheduledAt, forced, cfg.Schedule.Location())
+	return nil
+}
+
+// saveRecurring stores a recurring template. Instances are spawned by
+// publish as occurrences come due; --expires applies to each instance.
+func (cmd *PostCmd) saveRecurring(globals *Globals, cfg config.Config, entry history.Entry, draft, forced bool) error {
+	rule, err := recur.Parse(cmd.Every)
+	if err != nil {
+		return newCLIError(ExitInvalidInput, "invalid_recurrence",
+			fmt.Sprintf("Invalid --every %q: %s", cmd.Every, err))
+	}
+	now := time.Now().In(cfg.Schedule.Location())
+	next := rule.Next(now)
+	if next.IsZero() {
+		return newCLIError(ExitInvalidInput, "invalid_recurrence",
+			fmt.Sprintf("--every %q never occurs.", cmd.Every))
+	}
+	expiresAt, err := cmd.resolveExpiry(cfg, now)
+	if err != nil {
+		return err
+	}
+
+	entry.Priority = cmd.Priority
+	entry.Recurrence = &history.Recurrence{
+		Every:  rule.String(),
+		NextAt: next.UTC().Format(time.RFC3339),
+		Draft:  draft,
+	}
+	if !expiresAt.IsZero() {
+		entry.Recurrence.ExpireAfter = expiresAt.Sub(now).String()
+	}
+	entry, err = history.AppendEntry(entry)
+	if err != nil {
+		return historyFailure(err, "queue_failed",
+			fmt.Sprintf("Failed to save recurring post: %s", err))
+	}
+
 	if globals.JSON {
 		resp := map[string]any{
-			"status": "queued",
+			"status":  entry.Status,
+			"id":      entry.ID,
+			"every":   entry.Recurrence.Every,
+			"next_at": entry.Recurrence.NextAt,
+		}
+		if draft {
+			resp["draft"] = true
+		}
+		if forced {
+			resp["approval_required"] = true
+		}
+		b, _ := json.Marshal(resp)
+		fmt.Fprintln(os.Stdout, string(b))
+		return nil
+	}
+	if forced {
+		fmt.Fprintln(os.Stdout, "Approval required for non-interactive posts; each occurrence is saved as a draft.")
+	}
+	fmt.Fprintf(os.Stdout, "Recurring post saved (id: %s, every %s). Next: %s.\n",
+		entry.ID, entry.Recurrence.Every, next.Format("2006-01-02 15:04"))
+	return nil
+}
+
+// printQueued confirms a queued or draft entry.
+// forced reports that require_approval turned the post into a draft. Times
+// are shown in loc.
+func (cmd *PostCmd) printQueued(globals *Globals, entry history.Entry, scheduledAt time.Time, forced bool, loc *time.Location) {
+	if globals.JSON {
+		resp := map[string]any{
+			"status": entry.Status,
 			"id":     entry.ID,
 		}
+		if len(entry.Tags) > 0 {
+			resp["tags"] = entry.Tags
+		}
+		if entry.Agent != "" {
+			resp["agent"] = entry.Agent
+		}
+		if entry.Priority != 0 {
+			resp["priority"] = entry.Priority
+		}
+		if forced {
+			resp["approval_required"] = true
+		}
 		if !scheduledAt.IsZero() {
 			resp["scheduled_at"] = scheduledAt.UTC().Format(time.RFC3339)
 		}
+		if entry.ExpiresAt != "" {
+			resp["expires_at"] = entry.ExpiresAt
+		}
+		if entry.Series != nil {
+			resp["series"] = entry.Series.Name
+			resp["part"] = entry.Series.Part
+		}
 		b, _ := json.Marshal(resp)
 		fmt.Fprintln(os.Stdout, string(b))
-	} else {
-		if !scheduledAt.IsZero() {
-			fmt.Fprintf(os.Stdout, "Message queued. Scheduled for: %s.\n",
-				scheduledAt.Local().Format("2006-01-02 15:04"))
-		} else {
-			fmt.Fprintln(os.Stdout, "Message queued.")
+		return
+	}
+
+	if entry.Status == "draft" {
+		if forced {
+			fmt.Fprintln(os.Stdout, "Approval required for non-interactive posts.")
 		}
+		fmt.Fprintf(os.Stdout, "Draft saved (id: %s). Approve with `slack-social-ai queue approve %s`.\n",
+			entry.ID, entry.ID)
+		return
 	}
-	return nil
+	if !scheduledAt.IsZero() {
+		fmt.Fprintf(os.Stdout, "Message queued. Scheduled for: %s.\n",
+			scheduledAt.In(loc).Format("2006-01-02 15:04"))
+	} else {
+		fmt.Fprintln(os.Stdout, "Message queued.")
+	}
+	if entry.Series != nil {
+		fmt.Fprintf(os.Stdout, "Part %d of series %s.\n", entry.Series.Part, entry.Series.Name)
+	}
+	if entry.ExpiresAt != "" {
+		fmt.Fprintf(os.Stdout, "Expires if unpublished by: %s.\n", formatExpiry(entry.ExpiresAt, loc))
+	}
+}
+
+// resolveExpiry returns when the post expires, or the zero time if it never does.
+// --expires overrides the configured default TTL; "never" disables expiry.
+func (cmd *PostCmd) resolveExpiry(cfg config.Config, from time.Time) (time.Time, error) {
+	return resolveExpiry(cmd.Expires, cfg, from)
 }
 
-func (cmd *PostCmd) dryRun(globals *Globals, message string) error {
+// resolveExpiry applies an --expires value, falling back to the configured
+// default TTL, counted from from.
+func resolveExpiry(ttl string, cfg config.Config, from time.Time) (time.Time, error) {
+	if ttl == "" {
+		ttl = cfg.DefaultTTL
+	}
+	if ttl == "" || ttl == "never" {
+		return time.Time{}, nil
+	}
+	d, err := parseTTL(ttl)
+	if err != nil {
+		return time.Time{}, err
+	}
+	return from.Add(d), nil
+}
+
+// expiryBase returns the time a post's TTL counts from: its scheduled time
+// if it has one, else now.
+func expiryBase(scheduledAt, now time.Time) time.Time {
+	if scheduledAt.After(now) {
+		return scheduledAt
+	}
+	return now
+}
+
+func (cmd *PostCmd) dryRun(globals *Globals, message string, tags []string) error {
 	if globals.JSON {
 		resp := map[string]any{
 			"status":     "dry_run",
 			"message":    message,
 			"char_count": len(message),
 		}
+		if len(tags) > 0 {
+			resp["tags"] = tags
+		}
 		b, _ := json.Marshal(resp)
 		fmt.Fprintln(os.Stdout, string(b))
 	} else {
@@ -104,17 +314,20 @@ func (cmd *PostCmd) dryRun(globals *Globals, message string) error {
 		fmt.Fprintln(os.Stdout)
 		fmt.Fprintln(os.Stdout, message)
 		fmt.Fprintf(os.Stdout, "\n(%d characters)\n", len(message))
+		if len(tags) > 0 {
+			fmt.Fprintf(os.Stdout, "Tags: %s\n", formatTags(tags))
+		}
 	}
 	return nil
 }
 
-func (cmd *PostCmd) publishNow(globals *Globals, webhookURL, message string) error {
-	if err := slack.SendWebhook(webhookURL, message); err != nil {
+func (cmd *PostCmd) publishNow(globals *Globals, webhookURL string, entry history.Entry) error {
+	if err := slack.SendWebhook(webhookURL, entry.Message); err != nil {
 		return newCLIError(ExitRuntimeError, "send_failed",
 			fmt.Sprintf("Failed to post message: %s", err))
 	}
 
-	_, _ = history.Append(message, "published", time.Time{}) // best-effort
+	_, _ = history.AppendEntry(entry) // best-effort
 
 	if globals.JSON {
 		printSuccessJSON("Message posted to Slack.")
@@ -123,3 +336,60 @@ func (cmd *PostCmd) publishNow(globals *Globals, webhookURL, message string) err
 	}
 	return nil
 }
+
+// newEntry builds a history entry carrying tags and provenance metadata.
+func newEntry(message, status string, tags []string, prov Provenance) history.Entry {
+	return history.Entry{
+		Message:   message,
+		Status:    status,
+		Tags:      tags,
+		Source:    prov.Source,
+		Agent:     prov.Agent,
+		SessionID: prov.SessionID,
+	}
+}
+
+// predictSlot returns the predicted publish time (RFC3339) for an entry
+// about to be queued, or "" if the queue can't be loaded. It is recorded
+// so history stats can measure how closely the schedule is kept.
+func predictSlot(cfg config.Config, entry history.Entry) string {
+	return predictSlots(cfg, []history.Entry{entry})[0]
+}
+
+// predictSlots is predictSlot for several entries queued together; they are
+// predicted behind the current queue in the given order.
+func predictSlots(cfg config.Config, entries []history.Entry) []string {
+	slots := make([]string, len(entries))
+	queued, err := history.Queued()
+	if err != nil {
+		return slots
+	}
+	lastPublished, _ := history.LastPublishedTime()
+	pending := make(map[string]int, len(entries))
+	for i, e := range entries {
+		e.ID = fmt.Sprintf("unsaved-%d", i)
+		pending[e.ID] = i
+		queued = append(queued, e)
+	}
+	if cfg.Schedule.HasCaps() {
+		published, _ := history.PublishedRecord()
+		queued = append(queued, published...)
+	}
+	now := time.Now().In(cfg.Schedule.Location())
+	for _, p := range schedule.PredictPublishTimes(queued, cfg.Schedule, lastPublished, now) {
+		if i, ok := pending[p.Entry.ID]; ok {
+			slots[i] = p.PublishAt.UTC().Format(time.RFC3339)
+		}
+	}
+	return slots
+}
+
+// nonInteractive reports whether post was invoked by an agent or a script
+// rather than by a human at a terminal.
+func nonInteractive(prov Provenance) bool {
+	if prov.Agent != "" {
+		return true
+	}
+	fi, err := os.Stdout.Stat()
+	return err != nil || fi.Mode()&os.ModeCharDevice == 0
+}
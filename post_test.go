@@ -0,0 +1,41 @@
+package main
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/lvrach/slack-social-ai/internal/config"
+)
+
+func TestPostResolveExpiry(t *testing.T) {
+	now := time.Date(2026, 3, 2, 12, 0, 0, 0, time.UTC)
+	cfg := config.Config{DefaultTTL: "2d"}
+
+	got, err := (&PostCmd{}).resolveExpiry(cfg, now)
+	require.NoError(t, err)
+	assert.Equal(t, now.Add(48*time.Hour), got, "default TTL applies")
+
+	got, err = (&PostCmd{Expires: "6h"}).resolveExpiry(cfg, now)
+	require.NoError(t, err)
+	assert.Equal(t, now.Add(6*time.Hour), got, "--expires overrides the default")
+
+	got, err = (&PostCmd{Expires: "never"}).resolveExpiry(cfg, now)
+	require.NoError(t, err)
+	assert.True(t, got.IsZero())
+
+	got, err = (&PostCmd{}).resolveExpiry(config.Config{}, now)
+	require.NoError(t, err)
+	assert.True(t, got.IsZero(), "no TTL configured")
+}
+
+func TestExpiryBase(t *testing.T) {
+	now := time.Date(2026, 3, 2, 12, 0, 0, 0, time.UTC)
+	later := now.Add(72 * time.Hour)
+
+	assert.Equal(t, now, expiryBase(time.Time{}, now), "unscheduled posts count from now")
+	assert.Equal(t, later, expiryBase(later, now), "scheduled posts count from their time")
+	assert.Equal(t, now, expiryBase(now.Add(-time.Hour), now), "a past --at counts from now")
+}
@@ -0,0 +1,97 @@
+package main
+
+import (
+	"os"
+	"regexp"
+	"strings"
+)
+
+// Provenance records who queued a post and where its content came from.
+// Embedded in PostCmd; explicit flags win over auto-detection.
+type Provenance struct {
+	Source    string `help:"Where the post came from (default: the _Source: ..._ footer)." env:"SLACK_SOCIAL_AI_SOURCE"`
+	Agent     string `help:"Agent that wrote the post (auto-detected for Claude Code, Cursor, OpenCode)." env:"SLACK_SOCIAL_AI_AGENT"`
+	SessionID string `name:"session-id" help:"Agent session ID (auto-detected when the agent exports one)." env:"SLACK_SOCIAL_AI_SESSION_ID"`
+}
+
+// agentEnv maps an agent name to environment variables that identify it.
+// The first variable that is set wins; order matters for agents that
+// run inside one another (e.g. Claude Code launched from Cursor's terminal).
+var agentEnv = []struct {
+	agent     string
+	markers   []string
+	sessionID []string
+}{
+	{agent: "claude", markers: []string{"CLAUDECODE", "CLAUDE_CODE_ENTRYPOINT"}, sessionID: []string{"CLAUDE_SESSION_ID", "CLAUDE_CODE_SESSION_ID"}},
+	{agent: "opencode", markers: []string{"OPENCODE", "OPENCODE_SESSION_ID"}, sessionID: []string{"OPENCODE_SESSION_ID"}},
+	{agent: "cursor", markers: []string{"CURSOR_AGENT", "CURSOR_TRACE_ID"}, sessionID: []string{"CURSOR_TRACE_ID"}},
+	{agent: "codex", markers: []string{"CODEX_SANDBOX", "CODEX_SESSION_ID"}, sessionID: []string{"CODEX_SESSION_ID"}},
+}
+
+// sourceFooter matches the guide's "_Source: <source>_" footer line.
+var sourceFooter = regexp.MustCompile(`(?m)^_Source:\s*(.+?)_\s*$`)
+
+// withDefaults fills empty fields from the message footer and the environment.
+func (p Provenance) withDefaults(message string, getenv func(string) string) Provenance {
+	if p.Source == "" {
+		p.Source = parseSource(message)
+	}
+	agent, sessionID := detectAgent(getenv)
+	p.Agent = strings.ToLower(strings.TrimSpace(p.Agent))
+	if p.Agent == "" {
+		p.Agent = agent
+	}
+	// Only borrow the detected session when it belongs to the same agent.
+	if p.SessionID == "" && p.Agent == agent {
+		p.SessionID = sessionID
+	}
+	return p
+}
+
+// parseSource extracts the last "_Source: ..._" footer from a message.
+func parseSource(message string) string {
+	matches := sourceFooter.FindAllStringSubmatch(message, -1)
+	if len(matches) == 0 {
+		return ""
+	}
+	return strings.TrimSpace(matches[len(matches)-1][1])
+}
+
+// detectAgent identifies the calling agent from well-known environment variables.
+func detectAgent(getenv func(string) string) (agent, sessionID string) {
+	for _, a := range agentEnv {
+		for _, marker := range a.markers {
+			if getenv(marker) == "" {
+				continue
+			}
+			for _, key := range a.sessionID {
+				if v := getenv(key); v != "" {
+					return a.agent, v
+				}
+			}
+			return a.agent, ""
+		}
+	}
+	return "", ""
+}
+
+// resolveProvenance fills provenance defaults from the process environment.
+func resolveProvenance(p Provenance, message string) Provenance {
+	return p.withDefaults(message, os.Getenv)
+}
+
+// formatProvenance renders agent/session/source for human output, or "".
+func formatProvenance(agent, sessionID, source string) string {
+	var parts []string
+	if agent != "" {
+		who := "by " + agent
+		if sessionID != "" {
+			who += " (" + sessionID + ")"
+		}
+		parts = append(parts, who)
+	}
+	if source != "" {
+		parts = append(parts, "source: "+source)
+	}
+	return strings.Join(parts, " · ")
+}
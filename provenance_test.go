@@ -0,0 +1,71 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/lvrach/slack-social-ai/internal/history"
+)
+
+// envMap returns a getenv func backed by a map.
+func envMap(env map[string]string) func(string) string {
+	return func(key string) string { return env[key] }
+}
+
+func TestParseSource(t *testing.T) {
+	msg := "r/go\n:bulb: *Headline*\n\nBody.\n\n_Source: claude session (debugging a goroutine leak)_"
+	assert.Equal(t, "claude session (debugging a goroutine leak)", parseSource(msg))
+	assert.Empty(t, parseSource("no footer here"))
+}
+
+func TestProvenance_DetectsClaude(t *testing.T) {
+	env := envMap(map[string]string{"CLAUDECODE": "1", "CLAUDE_SESSION_ID": "sess-42"})
+
+	got := Provenance{}.withDefaults("_Source: claude memory (pattern)_", env)
+	assert.Equal(t, "claude", got.Agent)
+	assert.Equal(t, "sess-42", got.SessionID)
+	assert.Equal(t, "claude memory (pattern)", got.Source)
+}
+
+func TestProvenance_ExplicitFlagsWin(t *testing.T) {
+	env := envMap(map[string]string{"CLAUDECODE": "1", "CLAUDE_SESSION_ID": "sess-42"})
+
+	got := Provenance{Source: "code review", Agent: "Cursor"}.withDefaults("_Source: ignored_", env)
+	assert.Equal(t, "cursor", got.Agent)
+	assert.Empty(t, got.SessionID, "detected session belongs to a different agent")
+	assert.Equal(t, "code review", got.Source)
+}
+
+func TestProvenance_ExplicitAgentMixedCase(t *testing.T) {
+	env := envMap(map[string]string{"CLAUDECODE": "1", "CLAUDE_SESSION_ID": "sess-42"})
+
+	got := Provenance{Agent: " Claude "}.withDefaults("plain", env)
+	assert.Equal(t, "claude", got.Agent)
+	assert.Equal(t, "sess-42", got.SessionID, "--agent Claude is the detected agent")
+}
+
+func TestProvenance_NoAgent(t *testing.T) {
+	got := Provenance{}.withDefaults("plain", envMap(nil))
+	assert.Equal(t, Provenance{}, got)
+}
+
+func TestFilterByAgent(t *testing.T) {
+	entries := []history.Entry{
+		{ID: "a1", Agent: "claude"},
+		{ID: "a2", Agent: "cursor"},
+		{ID: "a3"},
+	}
+
+	assert.Len(t, filterByAgent(entries, ""), 3)
+	got := filterByAgent(entries, "Claude")
+	assert.Len(t, got, 1)
+	assert.Equal(t, "a1", got[0].ID)
+}
+
+func TestFormatProvenance(t *testing.T) {
+	assert.Equal(t, "by claude (s1) · source: claude session (x)",
+		formatProvenance("claude", "s1", "claude session (x)"))
+	assert.Equal(t, "source: code review", formatProvenance("", "", "code review"))
+	assert.Empty(t, formatProvenance("", "", ""))
+}
@@ -2,6 +2,7 @@ package main
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
 	"os"
 	"time"
@@ -34,7 +35,7 @@ func (cmd *PublishCmd) Run(globals *Globals) error {
 	// 2. Load config.
 	cfg, err := config.Load()
 	if err != nil {
-		cfg = config.Config{Schedule: schedule.DefaultSchedule()}
+		cfg = config.Default()
 	}
 
 	return cmd.publishOne(webhookURL, cfg, globals, cmd.IgnoreSchedule)
@@ -44,53 +45,118 @@ func (cmd *PublishCmd) Run(globals *Globals) error {
 // recover stuck, claim, send webhook, and mark published.
 // Extracted from Run so it can be tested without the macOS keychain.
 func (cmd *PublishCmd) publishOne(webhookURL string, cfg config.Config, globals *Globals, ignoreSchedule bool) error {
+	// Spawn due recurring posts first, so occurrences outside active hours
+	// are queued on time and publish when the schedule next allows.
+	if _, err := history.SpawnDue(time.Now().In(cfg.Schedule.Location())); err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: failed to spawn recurring posts: %s\n", err)
+	}
+
+	var skip func(history.Entry) bool // entries over a tag cap
 	if !ignoreSchedule {
-		// 3. Time guard: check if we're in active hours.
+		// 3. Pause and time guards: `queue pause` stops publishing entirely;
+		// otherwise publish only in active hours.
+		if paused, until := cfg.Schedule.PausedAt(time.Now()); paused {
+			return cmd.exitPaused(globals, until, cfg.Schedule.Location())
+		}
+
 		if !cfg.Schedule.IsActiveNow() {
 			return cmd.exitOutsideSchedule(globals, cfg.Schedule)
 		}
 
-		// 4. Frequency guard: check minimum interval between posts.
-		if postEvery := cfg.Schedule.PostEvery(); postEvery > 0 {
+		// 4. Frequency guard: check minimum interval between posts. Between
+		// parts of a series, the series gap applies instead.
+		var slot time.Time // when the current slot opened, for jitter
+		series, inSeries, _ := history.CurrentSeries()
+		switch {
+		case inSeries && !time.Now().Before(series.ReadyAt):
+			// The next part is due.
+			slot = series.ReadyAt
+		case inSeries && series.Strict:
+			return cmd.exitTooSoon(globals, series.ReadyAt, cfg.Schedule.Location())
+		case cfg.Schedule.PostEvery() > 0:
+			postEvery := cfg.Schedule.PostEvery()
 			lastPublished, err := history.LastPublishedTime()
 			if err == nil && !lastPublished.IsZero() {
 				elapsed := time.Since(lastPublished)
 				if elapsed < postEvery {
 					nextEligible := lastPublished.Add(postEvery)
-					return cmd.exitTooSoon(globals, nextEligible)
+					return cmd.exitTooSoon(globals, nextEligible, cfg.Schedule.Location())
+				}
+				slot = lastPublished.Add(postEvery)
+			}
+		}
+
+		// 5. Cap guard: while an overall daily or weekly cap is reached the
+		// queue waits for the period to end; entries over a tag's cap are
+		// passed over for the next entry that is not, as `queue` predicts.
+		if cfg.Schedule.HasCaps() {
+			published, _ := history.PublishedRecord()
+			if hit, ok := cfg.Schedule.CapAt(published, time.Now()); ok {
+				if next, err := history.NextReady(); err == nil && next != nil {
+					return cmd.exitCapReached(globals, cfg.Schedule, hit)
+				}
+			}
+			skip = func(e history.Entry) bool {
+				_, ok := cfg.Schedule.TagCapAt(published, e, time.Now())
+				return ok
+			}
+		}
+
+		// 6. Jitter guard: the next entry waits out its own random delay
+		// into the slot, as `queue` predicts.
+		if cfg.Schedule.JitterMinutes > 0 {
+			if next, err := history.NextReadyExcept(skip); err == nil && next != nil {
+				if at := cfg.Schedule.JitterAt(*next, slot); time.Now().Before(at) {
+					return cmd.exitTooSoon(globals, at, cfg.Schedule.Location())
 				}
 			}
 		}
 	}
 
-	// 5. Recover stuck entries (publishing for > 5 minutes).
+	// 7. Recover stuck entries (publishing for > 5 minutes).
 	_ = history.RecoverStuck(5 * time.Minute)
 
-	// 6. Claim next ready entry.
-	entry, err := history.ClaimNextReady()
+	// 8. Claim next ready entry.
+	entry, err := history.ClaimNextReadyExcept(skip)
 	if err != nil {
-		return newCLIError(ExitRuntimeError, "claim_error",
+		return historyFailure(err, "claim_error",
 			fmt.Sprintf("Failed to claim entry: %s", err))
 	}
 	if entry == nil {
+		// Every ready entry is over a tag cap.
+		if next, err := history.NextReady(); err == nil && next != nil && skip != nil {
+			published, _ := history.PublishedRecord()
+			if hit, ok := cfg.Schedule.TagCapAt(published, *next, time.Now()); ok {
+				return cmd.exitCapReached(globals, cfg.Schedule, hit)
+			}
+		}
 		return cmd.exitNoQueued(globals)
 	}
 
-	// 7. Send webhook.
-	if err := slack.SendWebhook(webhookURL, entry.Message); err != nil {
+	// 9. Send webhook, as a thread reply for threaded series.
+	threadTS := ""
+	if entry.Series != nil {
+		threadTS = entry.Series.ThreadTS
+	}
+	if err := slack.SendWebhookReply(webhookURL, entry.Message, threadTS); err != nil {
 		// Reset to queued on failure.
 		_ = history.ResetToQueued(entry.ID)
 		return newCLIError(ExitRuntimeError, "webhook_failed",
 			fmt.Sprintf("Failed to publish message: %s", err))
 	}
 
-	// 8. Mark published.
-	if err := history.MarkPublished(entry.ID); err != nil {
+	// 10. Mark published. The message is already out, so keep waiting out
+	// lock timeouts rather than leaving the entry for RecoverStuck to re-send.
+	err = history.MarkPublished(entry.ID)
+	for deadline := time.Now().Add(4 * time.Minute); errors.Is(err, history.ErrLockTimeout) && time.Now().Before(deadline); {
+		err = history.MarkPublished(entry.ID)
+	}
+	if err != nil {
 		// Webhook succeeded but marking failed -- log but don't fail.
 		fmt.Fprintf(os.Stderr, "Warning: message sent but failed to mark as published: %s\n", err)
 	}
 
-	// 9. Success.
+	// 11. Success.
 	if globals.JSON {
 		resp := map[string]string{"status": "ok", "message": entry.Message, "id": entry.ID}
 		b, _ := json.Marshal(resp)
@@ -101,21 +167,45 @@ func (cmd *PublishCmd) publishOne(webhookURL string, cfg config.Config, globals
 	return nil
 }
 
-// exitOutsideSchedule reports that we're outside the configured active hours.
+// exitOutsideSchedule reports that we're outside the configured active
+// hours, or on a blackout day.
 func (cmd *PublishCmd) exitOutsideSchedule(globals *Globals, sched schedule.Schedule) error {
+	blackout, inBlackout := sched.BlackoutAt(time.Now())
 	if globals.JSON {
 		resp := map[string]string{"status": "outside_schedule"}
+		if inBlackout {
+			resp["blackout"] = blackout.String()
+		}
 		b, _ := json.Marshal(resp)
 		fmt.Fprintln(os.Stdout, string(b))
+	} else if inBlackout {
+		fmt.Fprintf(os.Stdout, "Skipped: blackout %s.\n", blackout)
 	} else {
-		fmt.Fprintf(os.Stdout, "Skipped: outside active hours (%s %02d:00–%02d:00).\n",
-			formatWeekdays(sched.Weekdays), sched.StartHour, sched.EndHour)
+		fmt.Fprintf(os.Stdout, "Skipped: outside active hours (%s).\n", formatActiveHours(sched))
+	}
+	return nil
+}
+
+// exitPaused reports that publishing is paused, until the given time or,
+// if it is zero, until resumed.
+func (cmd *PublishCmd) exitPaused(globals *Globals, until time.Time, loc *time.Location) error {
+	if globals.JSON {
+		resp := map[string]string{"status": "paused"}
+		if !until.IsZero() {
+			resp["paused_until"] = until.UTC().Format(time.RFC3339)
+		}
+		b, _ := json.Marshal(resp)
+		fmt.Fprintln(os.Stdout, string(b))
+	} else if until.IsZero() {
+		fmt.Fprintln(os.Stdout, "Skipped: publishing paused. Resume with `slack-social-ai queue resume`.")
+	} else {
+		fmt.Fprintf(os.Stdout, "Skipped: publishing paused until %s.\n", formatPredictedTime(until, loc))
 	}
 	return nil
 }
 
 // exitTooSoon handles the "too soon since last post" case.
-func (cmd *PublishCmd) exitTooSoon(globals *Globals, nextEligible time.Time) error {
+func (cmd *PublishCmd) exitTooSoon(globals *Globals, nextEligible time.Time, loc *time.Location) error {
 	if globals.JSON {
 		resp := map[string]string{
 			"status":        "too_soon",
@@ -125,7 +215,30 @@ func (cmd *PublishCmd) exitTooSoon(globals *Globals, nextEligible time.Time) err
 		fmt.Fprintln(os.Stdout, string(b))
 	} else {
 		fmt.Fprintf(os.Stdout, "Skipped: too soon. Next eligible: %s.\n",
-			nextEligible.Local().Format("3:04pm"))
+			nextEligible.In(loc).Format("3:04pm"))
+	}
+	return nil
+}
+
+// exitCapReached reports that a cap keeps the ready entries from
+// publishing, and when they may publish instead.
+func (cmd *PublishCmd) exitCapReached(globals *Globals, sched schedule.Schedule, hit schedule.CapHit) error {
+	nextEligible := schedule.AdvanceToActive(hit.Until, sched)
+	if globals.JSON {
+		resp := map[string]any{
+			"status":        "cap_reached",
+			"cap":           hit.Period,
+			"limit":         hit.Limit,
+			"next_eligible": nextEligible.UTC().Format(time.RFC3339),
+		}
+		if hit.Tag != "" {
+			resp["tag"] = hit.Tag
+		}
+		b, _ := json.Marshal(resp)
+		fmt.Fprintln(os.Stdout, string(b))
+	} else {
+		fmt.Fprintf(os.Stdout, "Skipped: %s reached. Next eligible: %s.\n",
+			formatCapHit(hit), formatPredictedTime(nextEligible, sched.Location()))
 	}
 	return nil
 }
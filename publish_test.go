@@ -117,7 +117,7 @@ func TestPublishCmd_ExitTooSoon_JSON(t *testing.T) {
 	globals := &Globals{JSON: true}
 
 	nextEligible := time.Date(2025, 6, 15, 14, 30, 0, 0, time.UTC)
-	retErr := cmd.exitTooSoon(globals, nextEligible)
+	retErr := cmd.exitTooSoon(globals, nextEligible, time.Local)
 
 	_ = w.Close()
 	os.Stdout = oldStdout
@@ -145,20 +145,13 @@ func TestPublishCmd_ExitTooSoon_JSON(t *testing.T) {
 func alwaysActiveSchedule() schedule.Schedule {
 	return schedule.Schedule{
 		PostEveryMinutes: 0,
-		StartHour:        0,
-		EndHour:          24,
-		Weekdays:         []string{"mon", "tue", "wed", "thu", "fri", "sat", "sun"},
+		Windows:          schedule.SameWindows(schedule.Weekdays, schedule.Window{Start: 0, End: schedule.At(24, 0)}),
 	}
 }
 
-// neverActiveSchedule returns a schedule that is never active (start == end).
+// neverActiveSchedule returns a schedule that is never active (no windows).
 func neverActiveSchedule() schedule.Schedule {
-	return schedule.Schedule{
-		PostEveryMinutes: 0,
-		StartHour:        0,
-		EndHour:          0,
-		Weekdays:         []string{"mon", "tue", "wed", "thu", "fri", "sat", "sun"},
-	}
+	return schedule.Schedule{PostEveryMinutes: 0}
 }
 
 // withTempHome sets HOME to a temporary directory so that history.dataDir and
@@ -168,6 +161,9 @@ func withTempHome(t *testing.T) {
 	t.Helper()
 	home := t.TempDir()
 	t.Setenv("HOME", home)
+	t.Setenv("XDG_DATA_HOME", filepath.Join(home, ".local", "share"))
+	t.Setenv("XDG_CONFIG_HOME", filepath.Join(home, ".config"))
+	t.Setenv("XDG_STATE_HOME", filepath.Join(home, ".local", "state"))
 
 	// Pre-create the data and config dirs that the packages will use.
 	dataDir := filepath.Join(home, ".local", "share", "slack-social-ai")
@@ -210,12 +206,12 @@ func writeHistoryEntries(t *testing.T, entries []history.Entry) {
 	t.Helper()
 	home := os.Getenv("HOME")
 	path := filepath.Join(home, ".local", "share", "slack-social-ai", "history.json")
-	data, err := json.MarshalIndent(entries, "", "  ")
+	data, err := json.MarshalIndent(map[string]any{"version": history.CurrentVersion, "entries": entries}, "", "  ")
 	require.NoError(t, err)
 	require.NoError(t, os.WriteFile(path, data, 0o600))
 }
 
-// readHistoryEntries reads all entries from the history JSON file.
+// readHistoryEntries reads all entries from the history file envelope.
 func readHistoryEntries(t *testing.T) []history.Entry {
 	t.Helper()
 	home := os.Getenv("HOME")
@@ -225,9 +221,11 @@ func readHistoryEntries(t *testing.T) []history.Entry {
 		return nil
 	}
 	require.NoError(t, err)
-	var entries []history.Entry
-	require.NoError(t, json.Unmarshal(data, &entries))
-	return entries
+	var file struct {
+		Entries []history.Entry `json:"entries"`
+	}
+	require.NoError(t, json.Unmarshal(data, &file))
+	return file.Entries
 }
 
 func TestPublish_HappyPath(t *testing.T) {
@@ -356,9 +354,7 @@ func TestPublish_TooSoon(t *testing.T) {
 	// PostEvery = 180 minutes (3 hours). Last published 30 min ago, so too soon.
 	cfg := config.Config{Schedule: schedule.Schedule{
 		PostEveryMinutes: 180,
-		StartHour:        0,
-		EndHour:          24,
-		Weekdays:         []string{"mon", "tue", "wed", "thu", "fri", "sat", "sun"},
+		Windows:          alwaysActiveSchedule().Windows,
 	}}
 
 	output := captureStdout(t, func() {
@@ -407,9 +403,7 @@ func TestPublish_FrequencyOK(t *testing.T) {
 	// PostEvery = 180 minutes (3 hours). Last published 4 hours ago, so OK.
 	cfg := config.Config{Schedule: schedule.Schedule{
 		PostEveryMinutes: 180,
-		StartHour:        0,
-		EndHour:          24,
-		Weekdays:         []string{"mon", "tue", "wed", "thu", "fri", "sat", "sun"},
+		Windows:          alwaysActiveSchedule().Windows,
 	}}
 
 	output := captureStdout(t, func() {
@@ -517,3 +511,129 @@ func TestPublish_RecoverStuck(t *testing.T) {
 	require.Len(t, entries, 1)
 	assert.Equal(t, "published", entries[0].Status)
 }
+
+func TestPublish_RespectsJitter(t *testing.T) {
+	withTempHome(t)
+
+	cfg := config.Config{Schedule: alwaysActiveSchedule()}
+	cfg.Schedule.JitterMinutes = 60
+	entry := history.Entry{
+		ID: "j1", Message: "Jittered", Status: "queued",
+		CreatedAt: time.Now().UTC().Format(time.RFC3339), JitterSeed: ^uint32(0),
+	}
+	at := cfg.Schedule.JitterAt(entry, time.Time{})
+	if !at.After(time.Now()) {
+		t.Skip("too close to midnight for a jitter delay")
+	}
+	writeHistoryEntries(t, []history.Entry{entry})
+
+	output := captureStdout(t, func() {
+		require.NoError(t, (&PublishCmd{}).publishOne("http://unused", cfg, &Globals{JSON: true}, false))
+	})
+	var resp map[string]string
+	require.NoError(t, json.Unmarshal([]byte(output), &resp))
+	assert.Equal(t, "too_soon", resp["status"])
+	assert.Equal(t, at.UTC().Format(time.RFC3339), resp["next_eligible"])
+
+	// Created two hours ago, its jittered slot has passed.
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer srv.Close()
+	entry.CreatedAt = time.Now().Add(-2 * time.Hour).UTC().Format(time.RFC3339)
+	writeHistoryEntries(t, []history.Entry{entry})
+
+	output = captureStdout(t, func() {
+		require.NoError(t, (&PublishCmd{}).publishOne(srv.URL, cfg, &Globals{JSON: true}, false))
+	})
+	require.NoError(t, json.Unmarshal([]byte(output), &resp))
+	assert.Equal(t, "ok", resp["status"])
+}
+
+func TestPublish_CapReached(t *testing.T) {
+	withTempHome(t)
+
+	cfg := config.Config{Schedule: alwaysActiveSchedule()}
+	cfg.Schedule.PostEveryMinutes = 0
+	cfg.Schedule.TagCaps = map[string]schedule.Caps{"release": {MaxPerDay: 1}}
+	now := time.Now().UTC().Format(time.RFC3339)
+	writeHistoryEntries(t, []history.Entry{
+		{ID: "p1", Message: "Shipped", Status: "published", Tags: []string{"release"}, CreatedAt: now, PublishedAt: now},
+		{ID: "q1", Message: "Shipped again", Status: "queued", Tags: []string{"release"}, CreatedAt: now},
+	})
+
+	output := captureStdout(t, func() {
+		require.NoError(t, (&PublishCmd{}).publishOne("http://unused", cfg, &Globals{JSON: true}, false))
+	})
+	var resp map[string]any
+	require.NoError(t, json.Unmarshal([]byte(output), &resp))
+	assert.Equal(t, "cap_reached", resp["status"])
+	assert.Equal(t, "day", resp["cap"])
+	assert.Equal(t, "release", resp["tag"])
+	assert.InDelta(t, 1, resp["limit"], 0)
+	assert.NotEmpty(t, resp["next_eligible"])
+
+	entries := readHistoryEntries(t)
+	assert.Equal(t, "queued", entries[1].Status, "capped entry stays queued")
+}
+
+func TestPublish_TagCapPassesOver(t *testing.T) {
+	withTempHome(t)
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer srv.Close()
+
+	cfg := config.Config{Schedule: alwaysActiveSchedule()}
+	cfg.Schedule.PostEveryMinutes = 0
+	cfg.Schedule.TagCaps = map[string]schedule.Caps{"promo": {MaxPerDay: 1}}
+	now := time.Now().UTC().Format(time.RFC3339)
+	writeHistoryEntries(t, []history.Entry{
+		{ID: "p1", Message: "Sale", Status: "published", Tags: []string{"promo"}, CreatedAt: now, PublishedAt: now},
+		{ID: "q1", Message: "Another sale", Status: "queued", Tags: []string{"promo"}, CreatedAt: now},
+		{ID: "q2", Message: "Untagged", Status: "queued", CreatedAt: now},
+	})
+
+	output := captureStdout(t, func() {
+		require.NoError(t, (&PublishCmd{}).publishOne(srv.URL, cfg, &Globals{JSON: true}, false))
+	})
+	var resp map[string]string
+	require.NoError(t, json.Unmarshal([]byte(output), &resp))
+	assert.Equal(t, "ok", resp["status"])
+	assert.Equal(t, "q2", resp["id"], "untagged entry overtakes the capped one")
+
+	entries := readHistoryEntries(t)
+	assert.Equal(t, "queued", entries[1].Status, "capped entry stays queued")
+	assert.Equal(t, "published", entries[2].Status)
+}
+
+func TestPublish_CapSurvivesClearedHistory(t *testing.T) {
+	withTempHome(t)
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer srv.Close()
+
+	cfg := config.Config{Schedule: alwaysActiveSchedule()}
+	cfg.Schedule.PostEveryMinutes = 0
+	cfg.Schedule.MaxPerDay = 1
+	_, err := history.Append("first", "queued", time.Time{})
+	require.NoError(t, err)
+	captureStdout(t, func() {
+		require.NoError(t, (&PublishCmd{}).publishOne(srv.URL, cfg, &Globals{JSON: true}, false))
+	})
+
+	require.NoError(t, history.ClearPublished())
+	_, err = history.Append("second", "queued", time.Time{})
+	require.NoError(t, err)
+
+	output := captureStdout(t, func() {
+		require.NoError(t, (&PublishCmd{}).publishOne(srv.URL, cfg, &Globals{JSON: true}, false))
+	})
+	var resp map[string]any
+	require.NoError(t, json.Unmarshal([]byte(output), &resp))
+	assert.Equal(t, "cap_reached", resp["status"], "clearing history must not reset the cap")
+	assert.Equal(t, "queued", readHistoryEntries(t)[0].Status)
+}
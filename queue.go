@@ -2,8 +2,12 @@ package main
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
+	"math"
 	"os"
+	"slices"
+	"strconv"
 	"strings"
 	"time"
 
@@ -17,92 +21,304 @@ type QueueCmd struct {
 	Show    QueueShowCmd    `cmd:"" default:"withargs" help:"Show queued messages with predicted publish times."`
 	Inspect QueueInspectCmd `cmd:"" help:"Interactive queue editor — browse and delete items."`
 	Remove  QueueRemoveCmd  `cmd:"" help:"Remove a queued message by ID."`
+	Move    QueueMoveCmd    `cmd:"" help:"Reorder a queued message (--to N, --top, --bottom, --before <id>)."`
+	Edit    QueueEditCmd    `cmd:"" help:"Edit a queued message in place ($EDITOR, stdin, or --message)."`
+	Approve QueueApproveCmd `cmd:"" help:"Approve drafts so they can be published."`
+	Import  QueueImportCmd  `cmd:"" help:"Queue many posts at once from JSONL or a directory of .txt/.md files."`
+	Hold    QueueHoldCmd    `cmd:"" help:"Keep a queued message in place but don't publish it until released."`
+	Release QueueReleaseCmd `cmd:"" help:"Let a held message publish again."`
+	Snooze  QueueSnoozeCmd  `cmd:"" help:"Push a queued message back by a duration from now."`
+	Pause   QueuePauseCmd   `cmd:"" help:"Stop publishing until resumed (or --until a time)."`
+	Resume  QueueResumeCmd  `cmd:"" help:"Resume publishing after queue pause."`
 }
 
 // QueueShowCmd displays the queue with predicted publish times.
-type QueueShowCmd struct{}
+type QueueShowCmd struct {
+	Tag []string `help:"Show only entries with this tag (repeatable)." short:"t"`
+}
 
 func (cmd *QueueShowCmd) Run(globals *Globals) error {
-	entries, err := history.Queued()
+	cfg, _ := config.Load()
+
+	// Predict over the full queue so filtered positions and times stay accurate.
+	predictions, err := loadPredictions(cfg, true)
 	if err != nil {
-		return newCLIError(ExitRuntimeError, "load_queue",
-			fmt.Sprintf("Failed to load queue: %s", err))
+		return err
+	}
+	tags := normalizeTags(cmd.Tag)
+	drafts, err := history.Drafts()
+	if err != nil {
+		return historyFailure(err, "load_queue",
+			fmt.Sprintf("Failed to load drafts: %s", err))
 	}
 
-	cfg, _ := config.Load()
-	lastPublished, _ := history.LastPublishedTime()
-	now := time.Now().UTC()
+	v := queueView{
+		predictions: filterPredictionsByTags(predictions, tags),
+		drafts:      history.FilterByTags(drafts, tags),
+		sched:       cfg.Schedule,
+	}
+	if globals.JSON {
+		return cmd.printJSON(v)
+	}
+	return cmd.printHuman(v)
+}
 
-	predictions := schedule.PredictPublishTimes(entries, cfg.Schedule, lastPublished, now)
+// queueView is everything `queue` displays.
+type queueView struct {
+	predictions []schedule.Prediction
+	drafts      []history.Entry // awaiting approval; not predicted
+	sched       schedule.Schedule
+}
 
-	if globals.JSON {
-		return cmd.printJSON(predictions, cfg.Schedule)
+// loadPredictions loads the queue and predicts publish times for every entry.
+// With recurring set, the next instance of each recurring post follows.
+func loadPredictions(cfg config.Config, recurring bool) ([]schedule.Prediction, error) {
+	entries, err := history.Queued()
+	if err != nil {
+		return nil, historyFailure(err, "load_queue",
+			fmt.Sprintf("Failed to load queue: %s", err))
 	}
-	return cmd.printHuman(predictions, cfg.Schedule)
+	if recurring {
+		templates, err := history.Recurrences()
+		if err != nil {
+			return nil, historyFailure(err, "load_queue",
+				fmt.Sprintf("Failed to load recurring posts: %s", err))
+		}
+		entries = append(entries, templates...)
+	}
+	if cfg.Schedule.HasCaps() {
+		published, _ := history.PublishedRecord()
+		entries = append(entries, published...)
+	}
+	lastPublished, _ := history.LastPublishedTime()
+	now := time.Now().In(cfg.Schedule.Location())
+	return schedule.PredictPublishTimes(entries, cfg.Schedule, lastPublished, now), nil
 }
 
-func (cmd *QueueShowCmd) printJSON(predictions []schedule.Prediction, sched schedule.Schedule) error {
-	type jsonPrediction struct {
-		Position         int    `json:"position"`
-		ID               string `json:"id"`
-		Message          string `json:"message"`
-		PredictedPublish string `json:"predicted_publish_at"`
-		Approximate      bool   `json:"approximate"`
-		CreatedAt        string `json:"created_at"`
-		ScheduledAt      string `json:"scheduled_at,omitempty"`
+// jsonQueueItem is the JSON shape of a queued or draft entry.
+type jsonQueueItem struct {
+	Position         int      `json:"position,omitempty"`
+	ID               string   `json:"id"`
+	Status           string   `json:"status"`
+	Message          string   `json:"message"`
+	PredictedPublish string   `json:"predicted_publish_at,omitempty"`
+	Approximate      bool     `json:"approximate"`
+	CreatedAt        string   `json:"created_at"`
+	ScheduledAt      string   `json:"scheduled_at,omitempty"`
+	Tags             []string `json:"tags,omitempty"`
+	Source           string   `json:"source,omitempty"`
+	Agent            string   `json:"agent,omitempty"`
+	SessionID        string   `json:"session_id,omitempty"`
+	Priority         int      `json:"priority,omitempty"`
+	ExpiresAt        string   `json:"expires_at,omitempty"`
+	RecurrenceID     string   `json:"recurrence_id,omitempty"`
+	// Held is set on held entries and on later parts of their series; they
+	// have no predicted publish time.
+	Held bool `json:"held,omitempty"`
+	// Upcoming is set on the next instance of a recurring post, which has no
+	// ID until it is spawned.
+	Upcoming bool `json:"upcoming,omitempty"`
+	// ExpiresFirst is set when the entry will expire before its predicted publish time.
+	ExpiresFirst bool `json:"expires_before_publish,omitempty"`
+}
+
+func newJSONQueueItem(e history.Entry) jsonQueueItem {
+	return jsonQueueItem{
+		ID:           e.ID,
+		Status:       e.Status,
+		Message:      e.Message,
+		CreatedAt:    e.CreatedAt,
+		ScheduledAt:  e.ScheduledAt,
+		Tags:         e.Tags,
+		Source:       e.Source,
+		Agent:        e.Agent,
+		SessionID:    e.SessionID,
+		Priority:     e.Priority,
+		ExpiresAt:    e.ExpiresAt,
+		RecurrenceID: e.RecurrenceID,
 	}
+}
 
-	items := make([]jsonPrediction, len(predictions))
-	for i, p := range predictions {
-		items[i] = jsonPrediction{
-			Position:         p.Position,
-			ID:               p.Entry.ID,
-			Message:          p.Entry.Message,
-			PredictedPublish: p.PublishAt.Format(time.RFC3339),
-			Approximate:      p.Approximate,
-			CreatedAt:        p.Entry.CreatedAt,
-			ScheduledAt:      p.Entry.ScheduledAt,
+func (cmd *QueueShowCmd) printJSON(v queueView) error {
+	items := make([]jsonQueueItem, len(v.predictions))
+	for i, p := range v.predictions {
+		items[i] = newJSONQueueItem(p.Entry)
+		items[i].Position = p.Position
+		if !p.Held {
+			items[i].PredictedPublish = p.PublishAt.UTC().Format(time.RFC3339)
 		}
+		items[i].Held = p.Held
+		items[i].Approximate = p.Approximate
+		items[i].ExpiresFirst = p.Entry.ExpiredAt(p.PublishAt)
+		items[i].Upcoming = p.Recurring
+	}
+	drafts := make([]jsonQueueItem, len(v.drafts))
+	for i, e := range v.drafts {
+		drafts[i] = newJSONQueueItem(e)
 	}
 
 	resp := map[string]any{
 		"queue":    items,
 		"count":    len(items),
-		"schedule": formatScheduleSummary(sched),
+		"drafts":   drafts,
+		"schedule": formatScheduleSummary(v.sched),
+	}
+	if paused, until := v.sched.PausedAt(time.Now()); paused {
+		resp["paused"] = true
+		if !until.IsZero() {
+			resp["paused_until"] = until.UTC().Format(time.RFC3339)
+		}
 	}
 
 	return json.NewEncoder(os.Stdout).Encode(resp)
 }
 
-func (cmd *QueueShowCmd) printHuman(predictions []schedule.Prediction, sched schedule.Schedule) error {
-	if len(predictions) == 0 {
+func (cmd *QueueShowCmd) printHuman(v queueView) error {
+	if banner := formatPause(v.sched, time.Now()); banner != "" {
+		fmt.Fprintf(os.Stdout, "%s Resume with `slack-social-ai queue resume`.\n\n", banner)
+	}
+	if len(v.predictions) == 0 && len(v.drafts) == 0 {
 		fmt.Fprintln(os.Stdout, "Queue is empty.")
 		return nil
 	}
 
-	fmt.Fprintf(os.Stdout, "Queue (%d messages):\n\n", len(predictions))
-	fmt.Fprintf(os.Stdout, " %-4s %-19s %s\n", "#", "Publish At", "Message")
-	fmt.Fprintf(os.Stdout, " %-4s %-19s %s\n", "\u2500", strings.Repeat("\u2500", 18), strings.Repeat("\u2500", 40))
-
+	loc := v.sched.Location()
 	indent := strings.Repeat(" ", 26) // 1 space + 4 pos + 1 space + 19 time + 1 space
-	for _, p := range predictions {
-		timeStr := formatPredictedTime(p.PublishAt)
-		if p.Approximate {
-			timeStr = "~" + timeStr
+	if len(v.predictions) > 0 {
+		if n := countUpcoming(v.predictions); n > 0 {
+			fmt.Fprintf(os.Stdout, "Queue (%d messages, %d upcoming recurring):\n\n", len(v.predictions)-n, n)
+		} else {
+			fmt.Fprintf(os.Stdout, "Queue (%d messages):\n\n", len(v.predictions))
+		}
+		fmt.Fprintf(os.Stdout, " %-4s %-19s %s\n", "#", "Publish At", "Message")
+		fmt.Fprintf(os.Stdout, " %-4s %-19s %s\n", "\u2500", strings.Repeat("\u2500", 18), strings.Repeat("\u2500", 40))
+
+		for _, p := range v.predictions {
+			timeStr := formatPredictionTime(p, loc)
+			printQueueItem(strconv.Itoa(p.Position), timeStr, indent, p.Entry, p.Entry.ExpiredAt(p.PublishAt), loc)
 		}
+		if n := countExpiring(v.predictions); n > 0 {
+			fmt.Fprintf(os.Stdout, "Warning: %d message(s) will expire before their predicted publish time.\n\n", n)
+		}
+	} else {
+		fmt.Fprintln(os.Stdout, "Queue is empty.")
+		fmt.Fprintln(os.Stdout)
+	}
 
-		preview := messagePreview(p.Entry.Message, 3, 2, 60)
-		fmt.Fprintf(os.Stdout, " %-4d %-19s %s\n", p.Position, timeStr, preview[0])
-		for _, line := range preview[1:] {
-			fmt.Fprintf(os.Stdout, "%s%s\n", indent, line)
+	if len(v.drafts) > 0 {
+		fmt.Fprintf(os.Stdout, "Drafts awaiting approval (%d):\n\n", len(v.drafts))
+		for _, e := range v.drafts {
+			printQueueItem("-", "id "+e.ID, indent, e, false, loc)
 		}
+		fmt.Fprintln(os.Stdout, "Approve with `slack-social-ai queue approve <id>`.")
 		fmt.Fprintln(os.Stdout)
 	}
 
-	fmt.Fprintf(os.Stdout, "%s\n", formatScheduleSummary(sched))
+	fmt.Fprintf(os.Stdout, "%s\n", formatScheduleSummary(v.sched))
 	return nil
 }
 
+// printQueueItem prints one row of the human queue table plus its metadata lines.
+// expiresFirst flags an entry that will expire before it is published.
+func printQueueItem(pos, timeStr, indent string, e history.Entry, expiresFirst bool, loc *time.Location) {
+	preview := messagePreview(e.Message, 3, 2, 60)
+	fmt.Fprintf(os.Stdout, " %-4s %-19s %s\n", pos, timeStr, preview[0])
+	for _, line := range preview[1:] {
+		fmt.Fprintf(os.Stdout, "%s%s\n", indent, line)
+	}
+	if len(e.Tags) > 0 {
+		fmt.Fprintf(os.Stdout, "%stags: %s\n", indent, formatTags(e.Tags))
+	}
+	if prov := formatProvenance(e.Agent, e.SessionID, e.Source); prov != "" {
+		fmt.Fprintf(os.Stdout, "%s%s\n", indent, prov)
+	}
+	if e.Priority != 0 {
+		fmt.Fprintf(os.Stdout, "%spriority: %d\n", indent, e.Priority)
+	}
+	if e.Held {
+		fmt.Fprintf(os.Stdout, "%sheld: release with `slack-social-ai queue release %s`\n", indent, e.ID)
+	}
+	if e.RecurrenceID != "" {
+		label := "from recurring post "
+		if e.ID == "" {
+			label = "upcoming from recurring post "
+		}
+		fmt.Fprintf(os.Stdout, "%s%s%s\n", indent, label, e.RecurrenceID)
+	}
+	if e.ExpiresAt != "" {
+		line := "expires: " + formatExpiry(e.ExpiresAt, loc)
+		if expiresFirst {
+			line = "warning: expires before publishing (" + formatExpiry(e.ExpiresAt, loc) + ")"
+		}
+		fmt.Fprintf(os.Stdout, "%s%s\n", indent, line)
+	}
+	fmt.Fprintln(os.Stdout)
+}
+
+// formatPredictionTime renders a prediction's publish time for the queue
+// table: "held" for held entries, "waiting" for series parts behind one,
+// and "~" marking approximate times.
+func formatPredictionTime(p schedule.Prediction, loc *time.Location) string {
+	switch {
+	case p.Entry.Held:
+		return "held"
+	case p.Held:
+		return "waiting"
+	case p.Approximate:
+		return "~" + formatPredictedTime(p.PublishAt, loc)
+	}
+	return formatPredictedTime(p.PublishAt, loc)
+}
+
+// formatExpiry renders an RFC3339 expiry like a predicted publish time.
+func formatExpiry(expiresAt string, loc *time.Location) string {
+	t, err := time.Parse(time.RFC3339, expiresAt)
+	if err != nil {
+		return expiresAt
+	}
+	return formatPredictedTime(t, loc)
+}
+
+// countExpiring counts predictions whose entry expires before its publish time.
+func countExpiring(predictions []schedule.Prediction) int {
+	n := 0
+	for _, p := range predictions {
+		if p.Entry.ExpiredAt(p.PublishAt) {
+			n++
+		}
+	}
+	return n
+}
+
+// countUpcoming counts predictions of recurring instances not yet spawned.
+func countUpcoming(predictions []schedule.Prediction) int {
+	n := 0
+	for _, p := range predictions {
+		if p.Recurring {
+			n++
+		}
+	}
+	return n
+}
+
+// filterPredictionsByTags keeps predictions whose entry has at least one of tags.
+// Positions are left untouched so they still reflect the full queue.
+func filterPredictionsByTags(predictions []schedule.Prediction, tags []string) []schedule.Prediction {
+	if len(tags) == 0 {
+		return predictions
+	}
+	var result []schedule.Prediction
+	for _, p := range predictions {
+		for _, t := range tags {
+			if p.Entry.HasTag(t) {
+				result = append(result, p)
+				break
+			}
+		}
+	}
+	return result
+}
+
 // messagePreview returns a multi-line preview of a message.
 // If the message has more than headN+tailN lines, the middle is replaced with "...".
 // Each line is truncated to maxWidth.
@@ -129,10 +345,11 @@ func messagePreview(s string, headN, tailN, maxWidth int) []string { //nolint:un
 	return result
 }
 
-// formatPredictedTime formats a predicted publish time as a human-friendly string.
-func formatPredictedTime(t time.Time) string {
-	now := time.Now()
-	local := t.Local()
+// formatPredictedTime formats a predicted publish time as a human-friendly
+// string in loc, normally the schedule's time zone.
+func formatPredictedTime(t time.Time, loc *time.Location) string {
+	now := time.Now().In(loc)
+	local := t.In(loc)
 
 	// Same day: "Today 14:30"
 	if local.Year() == now.Year() && local.YearDay() == now.YearDay() {
@@ -170,7 +387,7 @@ type QueueRemoveCmd struct {
 func (cmd *QueueRemoveCmd) Run(globals *Globals) error {
 	found, err := history.Remove(cmd.ID)
 	if err != nil {
-		return newCLIError(ExitRuntimeError, "remove_failed",
+		return historyFailure(err, "remove_failed",
 			fmt.Sprintf("Failed to remove entry: %s", err))
 	}
 	if !found {
@@ -178,7 +395,7 @@ func (cmd *QueueRemoveCmd) Run(globals *Globals) error {
 			fmt.Sprintf("Entry %q not found in queue.", cmd.ID))
 	}
 
-	msg := fmt.Sprintf("Removed entry %s from queue.", cmd.ID)
+	msg := fmt.Sprintf("Removed entry %s from queue. Run `slack-social-ai undo` to restore.", cmd.ID)
 	if globals.JSON {
 		printSuccessJSON(msg)
 	} else {
@@ -186,3 +403,125 @@ func (cmd *QueueRemoveCmd) Run(globals *Globals) error {
 	}
 	return nil
 }
+
+// QueueMoveCmd reorders a queued message.
+type QueueMoveCmd struct {
+	ID     string `arg:"" help:"ID of the message to move."`
+	To     int    `help:"Move to this 1-based queue position." xor:"target"`
+	Top    bool   `help:"Move to the front of the queue." xor:"target"`
+	Bottom bool   `help:"Move to the end of the queue." xor:"target"`
+	Before string `help:"Move directly in front of this message ID." xor:"target"`
+}
+
+func (cmd *QueueMoveCmd) Run(globals *Globals) error {
+	// A move can change the entry's priority (see history.MoveTo), which is
+	// reported so an explicit --priority is not lost silently.
+	before, _ := history.Get(cmd.ID)
+
+	var err error
+	switch {
+	case cmd.Top:
+		err = history.MoveTo(cmd.ID, 0)
+	case cmd.Bottom:
+		err = history.MoveTo(cmd.ID, math.MaxInt)
+	case cmd.Before != "":
+		err = history.MoveBefore(cmd.ID, cmd.Before)
+	case cmd.To > 0:
+		err = history.MoveTo(cmd.ID, cmd.To-1)
+	default:
+		return newCLIError(ExitInvalidInput, "missing_target",
+			"Specify where to move the message: --to N, --top, --bottom, or --before <id>.")
+	}
+	if err != nil {
+		return moveError(err)
+	}
+
+	queued, err := history.Queued()
+	if err != nil {
+		return historyFailure(err, "load_queue",
+			fmt.Sprintf("Failed to load queue: %s", err))
+	}
+	pos := slices.IndexFunc(queued, func(e history.Entry) bool { return e.ID == cmd.ID }) + 1
+	priority := before.Priority
+	if pos > 0 {
+		priority = queued[pos-1].Priority
+	}
+
+	if globals.JSON {
+		resp := map[string]any{"status": "ok", "id": cmd.ID, "position": pos, "priority": priority}
+		if priority != before.Priority {
+			resp["previous_priority"] = before.Priority
+		}
+		b, _ := json.Marshal(resp)
+		fmt.Fprintln(os.Stdout, string(b))
+	} else if priority != before.Priority {
+		fmt.Fprintf(os.Stdout, "Moved entry %s to position %d; its priority changed from %d to %d to keep it there.\n",
+			cmd.ID, pos, before.Priority, priority)
+	} else {
+		fmt.Fprintf(os.Stdout, "Moved entry %s to position %d.\n", cmd.ID, pos)
+	}
+	return nil
+}
+
+// moveError maps history move errors to CLI errors.
+func moveError(err error) error {
+	switch {
+	case errors.Is(err, history.ErrNotFound):
+		return newCLIError(ExitInvalidInput, "not_found", err.Error())
+	case errors.Is(err, history.ErrNotQueued):
+		return newCLIError(ExitInvalidInput, "not_queued", err.Error())
+	default:
+		return historyFailure(err, "move_failed",
+			fmt.Sprintf("Failed to move entry: %s", err))
+	}
+}
+
+// QueueApproveCmd moves drafts into the publishing queue.
+type QueueApproveCmd struct {
+	IDs []string `arg:"" optional:"" name:"id" help:"IDs of drafts to approve."`
+	All bool     `help:"Approve every draft."`
+}
+
+func (cmd *QueueApproveCmd) Run(globals *Globals) error {
+	ids := cmd.IDs
+	if cmd.All {
+		drafts, err := history.Drafts()
+		if err != nil {
+			return historyFailure(err, "load_queue",
+				fmt.Sprintf("Failed to load drafts: %s", err))
+		}
+		for _, d := range drafts {
+			ids = append(ids, d.ID)
+		}
+	}
+	if len(ids) == 0 && !cmd.All {
+		return newCLIError(ExitInvalidInput, "missing_id",
+			"Specify draft IDs to approve, or --all.")
+	}
+
+	// All or nothing: one bad ID leaves every draft as it was.
+	if err := history.Approve(ids...); err != nil {
+		switch {
+		case errors.Is(err, history.ErrNotFound):
+			return newCLIError(ExitInvalidInput, "not_found", err.Error()+"; no drafts were approved.")
+		case errors.Is(err, history.ErrNotDraft):
+			return newCLIError(ExitInvalidInput, "not_draft", err.Error()+"; no drafts were approved.")
+		default:
+			return historyFailure(err, "approve_failed",
+				fmt.Sprintf("Failed to approve drafts: %s", err))
+		}
+	}
+
+	msg := fmt.Sprintf("Approved %d draft(s).", len(ids))
+	if globals.JSON {
+		resp := map[string]any{"status": "ok", "approved": ids, "message": msg}
+		if ids == nil {
+			resp["approved"] = []string{}
+		}
+		b, _ := json.Marshal(resp)
+		fmt.Fprintln(os.Stdout, string(b))
+	} else {
+		printSuccessHuman(msg)
+	}
+	return nil
+}
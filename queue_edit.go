@@ -0,0 +1,192 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/lvrach/slack-social-ai/internal/history"
+)
+
+// QueueEditCmd edits a queued message in place, keeping its queue position.
+type QueueEditCmd struct {
+	ID      string   `arg:"" help:"ID of the message to edit."`
+	Message string   `help:"Replace the message text." short:"m"`
+	Stdin   bool     `help:"Read the new message text from stdin."`
+	At      string   `help:"Reschedule (HH:MM, duration like 2h, RFC3339, or \"now\" to clear)." short:"a"`
+	Tag     []string `help:"Replace the post's tags (repeatable)." short:"t"`
+}
+
+func (cmd *QueueEditCmd) Run(globals *Globals) error {
+	current, err := history.Get(cmd.ID)
+	if err != nil {
+		return editError(err)
+	}
+	if current.Status == "publishing" {
+		return editError(fmt.Errorf("entry %q: %w", cmd.ID, history.ErrPublishing))
+	}
+
+	// 1. Resolve the new message: flag, stdin, or $EDITOR when nothing else is given.
+	message, err := cmd.resolveMessage(current.Message)
+	if err != nil {
+		return err
+	}
+
+	// 2. Resolve the new schedule; "now" clears it (a zero time).
+	var scheduledAt *time.Time
+	if cmd.At != "" {
+		var t time.Time
+		if cmd.At != "now" {
+			if t, err = parseAt(cmd.At); err != nil {
+				return err
+			}
+		}
+		scheduledAt = &t
+	}
+
+	// 3. Resolve tags: explicit --tag replaces them; a changed r/<topic>
+	// header swaps the topic tag and keeps the rest.
+	tags := current.Tags
+	switch {
+	case len(cmd.Tag) > 0:
+		tags, err = resolveTags(message, cmd.Tag)
+	case message != current.Message:
+		kept := slices.DeleteFunc(slices.Clone(current.Tags), func(t string) bool {
+			return t == parseTopic(current.Message)
+		})
+		tags, err = resolveTags(message, kept)
+	}
+	if err != nil {
+		return err
+	}
+
+	// 4. Apply under the history lock, failing if the entry changed meanwhile.
+	entry, err := history.Edit(cmd.ID, current.Message, func(e *history.Entry) error {
+		e.Message = message
+		e.Tags = tags
+		if scheduledAt != nil {
+			e.Reschedule(*scheduledAt)
+		}
+		return nil
+	})
+	if err != nil {
+		return editError(err)
+	}
+
+	changed := len(entry.Revisions) > len(current.Revisions)
+	if globals.JSON {
+		resp := map[string]any{
+			"status":    "ok",
+			"id":        entry.ID,
+			"changed":   changed,
+			"revisions": len(entry.Revisions),
+		}
+		if entry.ScheduledAt != "" {
+			resp["scheduled_at"] = entry.ScheduledAt
+		}
+		if entry.ExpiresAt != "" {
+			resp["expires_at"] = entry.ExpiresAt
+		}
+		b, _ := json.Marshal(resp)
+		fmt.Fprintln(os.Stdout, string(b))
+		return nil
+	}
+	if !changed {
+		fmt.Fprintf(os.Stdout, "Entry %s unchanged.\n", entry.ID)
+		return nil
+	}
+	fmt.Fprintf(os.Stdout, "Entry %s updated (revision %d).\n", entry.ID, len(entry.Revisions))
+	return nil
+}
+
+// resolveMessage returns the new message text, or current if only
+// metadata (--at, --tag) is being changed.
+func (cmd *QueueEditCmd) resolveMessage(current string) (string, error) {
+	switch {
+	case cmd.Message != "":
+		return cmd.Message, nil
+	case cmd.Stdin:
+		return readStdin()
+	case cmd.At != "" || len(cmd.Tag) > 0:
+		return current, nil
+	}
+
+	// Piped stdin without --stdin.
+	if fi, err := os.Stdin.Stat(); err == nil && (fi.Mode()&os.ModeCharDevice) == 0 {
+		return readStdin()
+	}
+	return editInEditor(current)
+}
+
+// editInEditor opens $VISUAL or $EDITOR (default vi) on a temp file
+// containing text and returns the saved result.
+func editInEditor(text string) (string, error) {
+	editor := os.Getenv("VISUAL")
+	if editor == "" {
+		editor = os.Getenv("EDITOR")
+	}
+	if editor == "" {
+		editor = "vi"
+	}
+
+	f, err := os.CreateTemp("", "slack-social-ai-*.md")
+	if err != nil {
+		return "", fmt.Errorf("create temp file: %w", err)
+	}
+	path := f.Name()
+	defer func() { _ = os.Remove(path) }()
+	if _, err := f.WriteString(text + "\n"); err != nil {
+		_ = f.Close()
+		return "", fmt.Errorf("write temp file: %w", err)
+	}
+	if err := f.Close(); err != nil {
+		return "", fmt.Errorf("write temp file: %w", err)
+	}
+
+	// $EDITOR may carry arguments (e.g. "code --wait"), so run it via the shell.
+	c := exec.Command("sh", "-c", editor+` "$1"`, "sh", path) //nolint:gosec // editor comes from the user's own environment
+	c.Stdin, c.Stdout, c.Stderr = os.Stdin, os.Stdout, os.Stderr
+	if err := c.Run(); err != nil {
+		return "", newCLIError(ExitRuntimeError, "editor_failed",
+			fmt.Sprintf("Editor %q failed: %s", editor, err))
+	}
+
+	data, err := os.ReadFile(path) //nolint:gosec // temp file created above
+	if err != nil {
+		return "", fmt.Errorf("read temp file: %w", err)
+	}
+	msg := strings.TrimRight(string(data), "\n")
+	if strings.TrimSpace(msg) == "" {
+		return "", newCLIError(ExitInvalidInput, "empty_message",
+			"Edited message is empty; entry left unchanged.")
+	}
+	return msg, nil
+}
+
+// editError maps history edit errors to CLI errors.
+func editError(err error) error {
+	switch {
+	case errors.Is(err, history.ErrNotFound):
+		return newCLIError(ExitInvalidInput, "not_found", err.Error())
+	case errors.Is(err, history.ErrPublishing):
+		return newCLIError(ExitInvalidInput, "entry_publishing",
+			fmt.Sprintf("%s; try again once publishing finishes.", err))
+	case errors.Is(err, history.ErrNotQueued):
+		return newCLIError(ExitInvalidInput, "not_queued", err.Error())
+	case errors.Is(err, history.ErrStrictSeries):
+		return newCLIError(ExitInvalidInput, "strict_series",
+			fmt.Sprintf("Cannot hold %s: nothing publishes between its parts, so holding one would stop all publishing. "+
+				"Make the series loose with `slack-social-ai series set <name> --loose`, or drop it with `series remove`.", err))
+	case errors.Is(err, history.ErrConflict):
+		return newCLIError(ExitRuntimeError, "edit_conflict",
+			fmt.Sprintf("%s; re-run the edit.", err))
+	default:
+		return historyFailure(err, "edit_failed",
+			fmt.Sprintf("Failed to edit entry: %s", err))
+	}
+}
@@ -0,0 +1,105 @@
+package main
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/lvrach/slack-social-ai/internal/history"
+)
+
+func TestQueueEdit_Message(t *testing.T) {
+	withTempHome(t)
+
+	first, err := history.AppendEntry(history.Entry{Message: "r/go\nold", Status: "queued", Tags: []string{"go", "til"}})
+	require.NoError(t, err)
+	_, err = history.Append("second", "queued", time.Time{})
+	require.NoError(t, err)
+
+	cmd := &QueueEditCmd{ID: first.ID, Message: "r/ai\nnew"}
+	output := captureStdout(t, func() {
+		assert.NoError(t, cmd.Run(&Globals{JSON: true}))
+	})
+
+	var resp map[string]any
+	require.NoError(t, json.Unmarshal([]byte(output), &resp))
+	assert.Equal(t, true, resp["changed"])
+	assert.Equal(t, float64(1), resp["revisions"])
+
+	queued, err := history.Queued()
+	require.NoError(t, err)
+	require.Len(t, queued, 2)
+	assert.Equal(t, first.ID, queued[0].ID, "edit keeps the queue position")
+	assert.Equal(t, "r/ai\nnew", queued[0].Message)
+	assert.Equal(t, []string{"ai", "til"}, queued[0].Tags, "topic tag follows the header")
+}
+
+func TestQueueEdit_AtAndTags(t *testing.T) {
+	withTempHome(t)
+
+	e, err := history.Append("body", "queued", time.Now().Add(time.Hour))
+	require.NoError(t, err)
+
+	cmd := &QueueEditCmd{ID: e.ID, At: "now", Tag: []string{"ops"}}
+	captureStdout(t, func() {
+		assert.NoError(t, cmd.Run(&Globals{}))
+	})
+
+	got, err := history.Get(e.ID)
+	require.NoError(t, err)
+	assert.Equal(t, "body", got.Message)
+	assert.Empty(t, got.ScheduledAt)
+	assert.Equal(t, []string{"ops"}, got.Tags)
+	require.Len(t, got.Revisions, 1)
+	assert.Equal(t, e.ScheduledAt, got.Revisions[0].ScheduledAt)
+}
+
+func TestQueueEdit_AtMovesExpiry(t *testing.T) {
+	withTempHome(t)
+
+	created := time.Now().UTC().Truncate(time.Second)
+	writeHistoryEntries(t, []history.Entry{{
+		ID: "e1", Message: "expires tomorrow", Status: "queued",
+		CreatedAt: created.Format(time.RFC3339), ExpiresAt: created.Add(24 * time.Hour).Format(time.RFC3339),
+	}})
+
+	at := created.Add(72 * time.Hour)
+	captureStdout(t, func() {
+		require.NoError(t, (&QueueEditCmd{ID: "e1", At: at.Format(time.RFC3339)}).Run(&Globals{JSON: true}))
+	})
+
+	got, err := history.Get("e1")
+	require.NoError(t, err)
+	assert.Equal(t, at.Add(24*time.Hour).Format(time.RFC3339), got.ExpiresAt, "keeps its day to live")
+	assert.False(t, got.ExpiredAt(at), "due before it expires")
+}
+
+func TestQueueEdit_RefusesPublishing(t *testing.T) {
+	withTempHome(t)
+
+	_, err := history.Append("in flight", "queued", time.Time{})
+	require.NoError(t, err)
+	claimed, err := history.ClaimNextReady()
+	require.NoError(t, err)
+
+	err = (&QueueEditCmd{ID: claimed.ID, Message: "changed"}).Run(&Globals{})
+	var cliErr *CLIError
+	require.True(t, asCLIError(err, &cliErr))
+	assert.Equal(t, "entry_publishing", cliErr.Code)
+}
+
+func TestEditInEditor(t *testing.T) {
+	script := filepath.Join(t.TempDir(), "editor.sh")
+	require.NoError(t, os.WriteFile(script, []byte("#!/bin/sh\nprintf 'edited text\\n' > \"$1\"\n"), 0o700)) //nolint:gosec // test script must be executable
+	t.Setenv("VISUAL", "")
+	t.Setenv("EDITOR", script)
+
+	got, err := editInEditor("original")
+	require.NoError(t, err)
+	assert.Equal(t, "edited text", got)
+}
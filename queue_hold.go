@@ -0,0 +1,77 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/lvrach/slack-social-ai/internal/history"
+)
+
+// QueueHoldCmd holds a queued message: it keeps its position but is not
+// published until released.
+type QueueHoldCmd struct {
+	ID string `arg:"" help:"ID of the message to hold."`
+}
+
+func (cmd *QueueHoldCmd) Run(globals *Globals) error {
+	if _, err := history.Hold(cmd.ID); err != nil {
+		return editError(err)
+	}
+	msg := fmt.Sprintf("Held %s. Release with `slack-social-ai queue release %s`.", cmd.ID, cmd.ID)
+	if globals.JSON {
+		printSuccessJSON(msg)
+	} else {
+		printSuccessHuman(msg)
+	}
+	return nil
+}
+
+// QueueReleaseCmd releases a held message.
+type QueueReleaseCmd struct {
+	ID string `arg:"" help:"ID of the message to release."`
+}
+
+func (cmd *QueueReleaseCmd) Run(globals *Globals) error {
+	if _, err := history.Release(cmd.ID); err != nil {
+		return editError(err)
+	}
+	msg := fmt.Sprintf("Released %s.", cmd.ID)
+	if globals.JSON {
+		printSuccessJSON(msg)
+	} else {
+		printSuccessHuman(msg)
+	}
+	return nil
+}
+
+// QueueSnoozeCmd reschedules a queued message to a duration from now.
+type QueueSnoozeCmd struct {
+	ID       string `arg:"" help:"ID of the message to snooze."`
+	Duration string `arg:"" help:"How long from now to wait (e.g. 2h, 3d, 1w)."`
+}
+
+func (cmd *QueueSnoozeCmd) Run(globals *Globals) error {
+	d, err := parseTTL(cmd.Duration)
+	if err != nil {
+		return newCLIError(ExitInvalidInput, "invalid_duration",
+			fmt.Sprintf("Cannot parse duration %q. Use a positive duration like 2h, 3d, or 1w.", cmd.Duration))
+	}
+	e, err := history.Snooze(cmd.ID, time.Now().Add(d))
+	if err != nil {
+		return editError(err)
+	}
+
+	if globals.JSON {
+		resp := map[string]string{"status": "ok", "id": e.ID, "scheduled_at": e.ScheduledAt}
+		if e.ExpiresAt != "" {
+			resp["expires_at"] = e.ExpiresAt
+		}
+		b, _ := json.Marshal(resp)
+		fmt.Fprintln(os.Stdout, string(b))
+	} else {
+		printSuccessHuman(fmt.Sprintf("Snoozed %s until %s.", e.ID, formatExpiry(e.ScheduledAt, scheduleLocation())))
+	}
+	return nil
+}
@@ -0,0 +1,94 @@
+package main
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/lvrach/slack-social-ai/internal/history"
+)
+
+func TestQueueHoldAndRelease(t *testing.T) {
+	withTempHome(t)
+	entry, err := history.Append("Wait for the PR", "queued", time.Time{})
+	require.NoError(t, err)
+
+	captureStdout(t, func() {
+		require.NoError(t, (&QueueHoldCmd{ID: entry.ID}).Run(&Globals{JSON: true}))
+	})
+
+	output := captureStdout(t, func() {
+		require.NoError(t, (&QueueShowCmd{}).Run(&Globals{JSON: true}))
+	})
+	var resp struct {
+		Queue []jsonQueueItem `json:"queue"`
+	}
+	require.NoError(t, json.Unmarshal([]byte(output), &resp))
+	require.Len(t, resp.Queue, 1)
+	assert.True(t, resp.Queue[0].Held)
+	assert.Empty(t, resp.Queue[0].PredictedPublish)
+
+	output = captureStdout(t, func() {
+		require.NoError(t, (&QueueShowCmd{}).Run(&Globals{}))
+	})
+	assert.Contains(t, output, "held")
+	assert.Contains(t, output, "queue release "+entry.ID)
+
+	captureStdout(t, func() {
+		require.NoError(t, (&QueueReleaseCmd{ID: entry.ID}).Run(&Globals{JSON: true}))
+	})
+	got, err := history.Get(entry.ID)
+	require.NoError(t, err)
+	assert.False(t, got.Held)
+}
+
+func TestQueueHold_NotQueued(t *testing.T) {
+	withTempHome(t)
+	writeHistoryEntries(t, []history.Entry{{ID: "pub00001", Message: "out", Status: "published"}})
+
+	var cliErr *CLIError
+	err := (&QueueHoldCmd{ID: "pub00001"}).Run(&Globals{})
+	require.True(t, asCLIError(err, &cliErr))
+	assert.Equal(t, "not_queued", cliErr.Code)
+
+	err = (&QueueHoldCmd{ID: "missing"}).Run(&Globals{})
+	require.True(t, asCLIError(err, &cliErr))
+	assert.Equal(t, "not_found", cliErr.Code)
+}
+
+func TestQueueSnooze(t *testing.T) {
+	withTempHome(t)
+	entry, err := history.Append("Blog post goes live later", "queued", time.Time{})
+	require.NoError(t, err)
+
+	output := captureStdout(t, func() {
+		require.NoError(t, (&QueueSnoozeCmd{ID: entry.ID, Duration: "3d"}).Run(&Globals{JSON: true}))
+	})
+	var resp map[string]string
+	require.NoError(t, json.Unmarshal([]byte(output), &resp))
+	scheduled, err := time.Parse(time.RFC3339, resp["scheduled_at"])
+	require.NoError(t, err)
+	assert.WithinDuration(t, time.Now().Add(72*time.Hour), scheduled, time.Minute)
+
+	var cliErr *CLIError
+	err = (&QueueSnoozeCmd{ID: entry.ID, Duration: "later"}).Run(&Globals{})
+	require.True(t, asCLIError(err, &cliErr))
+	assert.Equal(t, "invalid_duration", cliErr.Code)
+}
+
+func TestQueueHold_StrictSeries(t *testing.T) {
+	withTempHome(t)
+	writeHistoryEntries(t, []history.Entry{
+		{ID: "s1", Message: "one", Status: "queued", Series: &history.SeriesPart{Name: "launch", Part: 1, Strict: true}},
+		{ID: "s2", Message: "two", Status: "queued", Series: &history.SeriesPart{Name: "launch", Part: 2, Strict: true}},
+	})
+
+	var cliErr *CLIError
+	err := (&QueueHoldCmd{ID: "s2"}).Run(&Globals{})
+	require.True(t, asCLIError(err, &cliErr))
+	assert.Equal(t, "strict_series", cliErr.Code)
+	assert.Contains(t, cliErr.Message, "--loose")
+}
@@ -0,0 +1,363 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"cmp"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/lvrach/slack-social-ai/internal/config"
+	"github.com/lvrach/slack-social-ai/internal/history"
+)
+
+// QueueImportCmd enqueues many posts at once from JSONL or a directory.
+type QueueImportCmd struct {
+	Path       string `arg:"" optional:"" help:"JSONL file, or a directory of .txt/.md files (one post each). Reads JSONL from stdin if omitted."`
+	Provenance `embed:""`
+	Tag        []string `help:"Tag every imported post (repeatable)." short:"t"`
+	Priority   int      `help:"Priority for items that do not set one." short:"P"`
+	Draft      bool     `help:"Import every post as a draft." short:"D"`
+	Series     string   `help:"Import the posts as the next parts of this series, in order." short:"S"`
+	Expires    string   `help:"Expiry for items that do not set one, counted from their \"at\" if set (e.g. 3d, or \"never\"). Defaults to config default_ttl."`
+	Force      bool     `help:"Skip the near-duplicate check." short:"f"`
+	DryRun     bool     `help:"Validate every item without queuing anything." short:"n"`
+}
+
+// importItem is one JSONL line. Unknown fields are rejected so typos do
+// not silently drop settings.
+type importItem struct {
+	Message   string   `json:"message"`
+	At        string   `json:"at,omitempty"`
+	Tags      []string `json:"tags,omitempty"`
+	Priority  *int     `json:"priority,omitempty"`
+	Expires   string   `json:"expires,omitempty"`
+	Draft     bool     `json:"draft,omitempty"`
+	Series    string   `json:"series,omitempty"`
+	Source    string   `json:"source,omitempty"`
+	Agent     string   `json:"agent,omitempty"`
+	SessionID string   `json:"session_id,omitempty"`
+}
+
+// importResult reports what happened to one item.
+type importResult struct {
+	Index  int    `json:"index"`  // 1-based
+	Source string `json:"source"` // "line N" or the file name
+	Status string `json:"status"` // "queued" | "draft" | "valid" (dry run) | "invalid" | "skipped"
+	ID     string `json:"id,omitempty"`
+	Series string `json:"series,omitempty"`
+	Part   int    `json:"part,omitempty"`
+	Code   string `json:"code,omitempty"`
+	Error  string `json:"error,omitempty"`
+}
+
+// sourcedItem is an item with where it was read from, or why it could not
+// be read.
+type sourcedItem struct {
+	source string
+	item   importItem
+	err    error
+}
+
+func (cmd *QueueImportCmd) Run(globals *Globals) error {
+	items, err := cmd.readItems()
+	if err != nil {
+		return err
+	}
+	if len(items) == 0 {
+		return newCLIError(ExitInvalidInput, "empty_import", "Nothing to import.")
+	}
+
+	// Validate everything before touching the queue.
+	cfg, _ := config.Load()
+	now := time.Now().In(cfg.Schedule.Location())
+	results := make([]importResult, len(items))
+	entries := make([]history.Entry, len(items))
+	for i, it := range items {
+		results[i] = importResult{Index: i + 1, Source: it.source}
+		err := it.err
+		if err == nil {
+			entries[i], err = cmd.buildEntry(it.item, cfg, now)
+		}
+		if err != nil {
+			setInvalid(&results[i], err)
+			continue
+		}
+		results[i].Status = "valid"
+		if entries[i].Series != nil {
+			results[i].Series = entries[i].Series.Name
+		}
+	}
+
+	// Near-duplicates are checked under the same lock that adds the batch,
+	// so a concurrent post cannot slip in between. A dry run, or an import
+	// already refused, only reads history to report them.
+	if cmd.DryRun || countInvalid(results) > 0 {
+		existing, _ := history.Load()
+		cmd.checkDuplicates(existing, entries, results, cfg, now)
+		if invalid := countInvalid(results); invalid > 0 {
+			return invalidItems(globals, results, invalid)
+		}
+		cmd.printResults(globals, results)
+		return nil
+	}
+
+	slots := predictSlots(cfg, entries)
+	for i := range entries {
+		if entries[i].Status == "queued" {
+			entries[i].PredictedAt = slots[i]
+		}
+	}
+	added, err := history.AppendEntries(entries, func(existing []history.Entry) error {
+		cmd.checkDuplicates(existing, entries, results, cfg, now)
+		if countInvalid(results) > 0 {
+			return errInvalidItems
+		}
+		return nil
+	})
+	if errors.Is(err, errInvalidItems) {
+		return invalidItems(globals, results, countInvalid(results))
+	}
+	if err != nil {
+		return historyFailure(err, "queue_failed",
+			fmt.Sprintf("Failed to import: %s", err))
+	}
+	for i, e := range added {
+		results[i].Status, results[i].ID = e.Status, e.ID
+		if e.Series != nil {
+			results[i].Part = e.Series.Part
+		}
+	}
+
+	cmd.printResults(globals, results)
+	return nil
+}
+
+// errInvalidItems aborts AppendEntries when an item fails the duplicate
+// check; the items are reported through their results.
+var errInvalidItems = errors.New("invalid import items")
+
+// checkDuplicates marks each valid item that repeats an entry in existing,
+// or an item accepted before it in the same import, as invalid. With
+// --force it does nothing.
+func (cmd *QueueImportCmd) checkDuplicates(existing, entries []history.Entry, results []importResult, cfg config.Config, now time.Time) {
+	if cmd.Force {
+		return
+	}
+	candidates := duplicateCandidates(existing, now)
+	for i := range entries {
+		if results[i].Status != "valid" {
+			continue
+		}
+		if err := duplicateError(entries[i].Message, candidates, cfg); err != nil {
+			setInvalid(&results[i], err)
+			continue
+		}
+		accepted := entries[i]
+		accepted.ID, accepted.Status = results[i].Source, "imported"
+		candidates = append(candidates, accepted)
+	}
+}
+
+// setInvalid records err as the reason r's item is invalid.
+func setInvalid(r *importResult, err error) {
+	r.Status = "invalid"
+	r.Error = err.Error()
+	var cliErr *CLIError
+	if asCLIError(err, &cliErr) {
+		r.Code, r.Error = cliErr.Code, cliErr.Message
+	}
+}
+
+func countInvalid(results []importResult) int {
+	n := 0
+	for _, r := range results {
+		if r.Status == "invalid" {
+			n++
+		}
+	}
+	return n
+}
+
+// invalidItems reports the invalid items of an import that was refused as
+// a whole, marking the valid ones as skipped.
+func invalidItems(globals *Globals, results []importResult, invalid int) error {
+	for i := range results {
+		if results[i].Status == "valid" {
+			results[i].Status = "skipped"
+		}
+	}
+	if !globals.JSON {
+		for _, r := range results {
+			if r.Status == "invalid" {
+				fmt.Fprintf(os.Stderr, "%s: %s\n", r.Source, r.Error)
+			}
+		}
+	}
+	cliErr := newCLIError(ExitInvalidInput, "invalid_items",
+		fmt.Sprintf("%d of %d item%s invalid; nothing was imported.",
+			invalid, len(results), plural(len(results), " is", "s are")))
+	cliErr.Details = map[string]any{"results": results}
+	return cliErr
+}
+
+// printResults reports the outcome of a successful import or dry run.
+func (cmd *QueueImportCmd) printResults(globals *Globals, results []importResult) {
+	if globals.JSON {
+		status := "ok"
+		if cmd.DryRun {
+			status = "dry_run"
+		}
+		b, _ := json.Marshal(map[string]any{"status": status, "count": len(results), "results": results})
+		fmt.Fprintln(os.Stdout, string(b))
+		return
+	}
+	if cmd.DryRun {
+		fmt.Fprintf(os.Stdout, "[dry-run] %d item%s valid; nothing was imported.\n",
+			len(results), plural(len(results), " is", "s are"))
+		return
+	}
+	for _, r := range results {
+		line := fmt.Sprintf("%-10s %-8s %s", r.Source, r.ID, r.Status)
+		if r.Series != "" {
+			line += fmt.Sprintf(" (series %s, part %d)", r.Series, r.Part)
+		}
+		fmt.Fprintln(os.Stdout, line)
+	}
+	fmt.Fprintf(os.Stdout, "Imported %d post%s.\n", len(results), plural(len(results), "", "s"))
+}
+
+// buildEntry validates an item and turns it into a history entry, applying
+// the command's defaults the way post does.
+func (cmd *QueueImportCmd) buildEntry(it importItem, cfg config.Config, now time.Time) (history.Entry, error) {
+	message := strings.TrimRight(it.Message, "\n")
+	if strings.TrimSpace(message) == "" {
+		return history.Entry{}, newCLIError(ExitInvalidInput, "empty_message", "Message is empty.")
+	}
+	tags, err := resolveTags(message, append(slices.Clone(cmd.Tag), it.Tags...))
+	if err != nil {
+		return history.Entry{}, err
+	}
+
+	prov := cmd.Provenance
+	if it.Source != "" {
+		prov.Source = it.Source
+	}
+	if it.Agent != "" {
+		prov.Agent = it.Agent
+	}
+	if it.SessionID != "" {
+		prov.SessionID = it.SessionID
+	}
+	prov = resolveProvenance(prov, message)
+
+	status := "queued"
+	if cmd.Draft || it.Draft || (cfg.RequireApproval && nonInteractive(prov)) {
+		status = "draft"
+	}
+	entry := newEntry(message, status, tags, prov)
+
+	entry.Priority = cmd.Priority
+	if it.Priority != nil {
+		entry.Priority = *it.Priority
+	}
+	var at time.Time
+	if it.At != "" {
+		at, err = parseAtFrom(it.At, now)
+		if err != nil {
+			return history.Entry{}, err
+		}
+		entry.ScheduledAt = at.UTC().Format(time.RFC3339)
+	}
+	expiresAt, err := resolveExpiry(cmp.Or(it.Expires, cmd.Expires), cfg, expiryBase(at, now))
+	if err != nil {
+		return history.Entry{}, err
+	}
+	if !expiresAt.IsZero() {
+		entry.ExpiresAt = expiresAt.UTC().Format(time.RFC3339)
+	}
+	if series := cmp.Or(it.Series, cmd.Series); series != "" {
+		entry.Series = &history.SeriesPart{Name: series}
+	}
+	return entry, nil
+}
+
+// readItems reads the import source: a directory, a JSONL file, or stdin.
+func (cmd *QueueImportCmd) readItems() ([]sourcedItem, error) {
+	if cmd.Path == "" || cmd.Path == "-" {
+		return readJSONL(os.Stdin)
+	}
+	fi, err := os.Stat(cmd.Path)
+	if err != nil {
+		return nil, newCLIError(ExitInvalidInput, "read_file_failed",
+			fmt.Sprintf("Cannot read %q: %s", cmd.Path, err))
+	}
+	if fi.IsDir() {
+		return readMessageDir(cmd.Path)
+	}
+	f, err := os.Open(cmd.Path) //nolint:gosec // user-provided path via CLI argument
+	if err != nil {
+		return nil, newCLIError(ExitRuntimeError, "read_file_failed",
+			fmt.Sprintf("Failed to read file %q: %s", cmd.Path, err))
+	}
+	defer f.Close()
+	return readJSONL(f)
+}
+
+// readJSONL reads one item per non-blank line. Lines that do not parse are
+// returned with their error so they can be reported with the rest.
+func readJSONL(r io.Reader) ([]sourcedItem, error) {
+	var items []sourcedItem
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for n := 1; scanner.Scan(); n++ {
+		line := bytes.TrimSpace(scanner.Bytes())
+		if len(line) == 0 {
+			continue
+		}
+		it := sourcedItem{source: fmt.Sprintf("line %d", n)}
+		dec := json.NewDecoder(bytes.NewReader(line))
+		dec.DisallowUnknownFields()
+		if err := dec.Decode(&it.item); err != nil {
+			it.err = newCLIError(ExitInvalidInput, "invalid_json", fmt.Sprintf("Invalid JSON: %s", err))
+		}
+		items = append(items, it)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, newCLIError(ExitRuntimeError, "read_file_failed",
+			fmt.Sprintf("Failed to read import: %s", err))
+	}
+	return items, nil
+}
+
+// readMessageDir reads each .txt and .md file in dir, by file name, as one
+// message.
+func readMessageDir(dir string) ([]sourcedItem, error) {
+	dirEntries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, newCLIError(ExitRuntimeError, "read_file_failed",
+			fmt.Sprintf("Failed to read directory %q: %s", dir, err))
+	}
+	var items []sourcedItem
+	for _, de := range dirEntries {
+		ext := strings.ToLower(filepath.Ext(de.Name()))
+		if de.IsDir() || (ext != ".txt" && ext != ".md") {
+			continue
+		}
+		it := sourcedItem{source: de.Name()}
+		data, err := os.ReadFile(filepath.Join(dir, de.Name())) //nolint:gosec // listed from the user's directory
+		if err != nil {
+			it.err = newCLIError(ExitRuntimeError, "read_file_failed", err.Error())
+		}
+		it.item.Message = string(data)
+		items = append(items, it)
+	}
+	return items, nil
+}
@@ -0,0 +1,155 @@
+package main
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/lvrach/slack-social-ai/internal/history"
+)
+
+// writeImportFile writes content to a file in a temp dir and returns its path.
+func writeImportFile(t *testing.T, name, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), name)
+	require.NoError(t, os.WriteFile(path, []byte(content), 0o600))
+	return path
+}
+
+func TestQueueImport_JSONL(t *testing.T) {
+	withTempHome(t)
+	path := writeImportFile(t, "posts.jsonl", `{"message": "first import", "tags": ["go"], "priority": 3}
+
+{"message": "second import", "draft": true, "at": "2099-01-02T10:00:00Z"}
+`)
+
+	output := captureStdout(t, func() {
+		require.NoError(t, (&QueueImportCmd{Path: path, Force: true}).Run(&Globals{JSON: true}))
+	})
+
+	var resp struct {
+		Status  string         `json:"status"`
+		Count   int            `json:"count"`
+		Results []importResult `json:"results"`
+	}
+	require.NoError(t, json.Unmarshal([]byte(output), &resp))
+	assert.Equal(t, "ok", resp.Status)
+	require.Len(t, resp.Results, 2)
+	assert.Equal(t, "line 1", resp.Results[0].Source)
+	assert.Equal(t, "queued", resp.Results[0].Status)
+	assert.Equal(t, "line 3", resp.Results[1].Source, "blank lines are skipped but keep numbering")
+	assert.Equal(t, "draft", resp.Results[1].Status)
+
+	entries := readHistoryEntries(t)
+	require.Len(t, entries, 2)
+	assert.Equal(t, resp.Results[0].ID, entries[0].ID)
+	assert.Equal(t, []string{"go"}, entries[0].Tags)
+	assert.Equal(t, 3, entries[0].Priority)
+	assert.NotEmpty(t, entries[0].PredictedAt)
+	assert.Equal(t, "2099-01-02T10:00:00Z", entries[1].ScheduledAt)
+}
+
+func TestQueueImport_ExpiryCountsFromAt(t *testing.T) {
+	withTempHome(t)
+	path := writeImportFile(t, "posts.jsonl", `{"message": "far future", "at": "2099-01-02T10:00:00Z", "expires": "1d"}
+`)
+
+	captureStdout(t, func() {
+		require.NoError(t, (&QueueImportCmd{Path: path, Force: true}).Run(&Globals{JSON: true}))
+	})
+
+	entries := readHistoryEntries(t)
+	require.Len(t, entries, 1)
+	assert.Equal(t, "2099-01-03T10:00:00Z", entries[0].ExpiresAt)
+}
+
+func TestQueueImport_InvalidItemImportsNothing(t *testing.T) {
+	withTempHome(t)
+	path := writeImportFile(t, "posts.jsonl", `{"message": "fine"}
+{"message": "bad time", "at": "whenever"}
+{"mesage": "typo"}
+`)
+
+	err := (&QueueImportCmd{Path: path, Force: true}).Run(&Globals{JSON: true})
+	var cliErr *CLIError
+	require.True(t, asCLIError(err, &cliErr))
+	assert.Equal(t, "invalid_items", cliErr.Code)
+	assert.Equal(t, ExitInvalidInput, cliErr.ExitCode)
+
+	results, ok := cliErr.Details["results"].([]importResult)
+	require.True(t, ok)
+	require.Len(t, results, 3)
+	assert.Equal(t, "skipped", results[0].Status)
+	assert.Equal(t, "invalid", results[1].Status)
+	assert.Equal(t, "invalid_json", results[2].Code)
+
+	assert.Empty(t, readHistoryEntries(t))
+}
+
+func TestQueueImport_DirectoryAsSeries(t *testing.T) {
+	withTempHome(t)
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "02-second.md"), []byte("second part\n"), 0o600))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "01-first.txt"), []byte("first part\n"), 0o600))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "notes.json"), []byte("{}"), 0o600))
+	_, err := history.AppendSeriesPart(history.Entry{Message: "already queued", Status: "queued"}, "dive", nil)
+	require.NoError(t, err)
+
+	captureStdout(t, func() {
+		require.NoError(t, (&QueueImportCmd{Path: dir, Series: "dive", Force: true}).Run(&Globals{}))
+	})
+
+	entries := readHistoryEntries(t)
+	require.Len(t, entries, 3)
+	assert.Equal(t, "first part", entries[1].Message)
+	assert.Equal(t, 2, entries[1].Series.Part)
+	assert.Equal(t, "second part", entries[2].Message)
+	assert.Equal(t, 3, entries[2].Series.Part)
+}
+
+func TestQueueImport_DryRun(t *testing.T) {
+	withTempHome(t)
+	path := writeImportFile(t, "posts.jsonl", `{"message": "just checking"}`+"\n")
+
+	output := captureStdout(t, func() {
+		require.NoError(t, (&QueueImportCmd{Path: path, DryRun: true, Force: true}).Run(&Globals{}))
+	})
+	assert.Contains(t, output, "nothing was imported")
+	assert.Empty(t, readHistoryEntries(t))
+}
+
+func TestQueueImport_DuplicatesWithinBatch(t *testing.T) {
+	withTempHome(t)
+	writeHistoryEntries(t, []history.Entry{
+		{ID: "old00001", Message: "Go 1.24 ships generic type aliases", Status: "queued"},
+	})
+	path := writeImportFile(t, "posts.jsonl", `{"message": "Go 1.24 ships generic type aliases"}
+{"message": "Range over func iterators are great"}
+{"message": "Range over func iterators are great!"}
+`)
+
+	err := (&QueueImportCmd{Path: path}).Run(&Globals{JSON: true})
+	var cliErr *CLIError
+	require.True(t, asCLIError(err, &cliErr))
+	assert.Equal(t, "invalid_items", cliErr.Code)
+
+	results, ok := cliErr.Details["results"].([]importResult)
+	require.True(t, ok)
+	require.Len(t, results, 3)
+	assert.Equal(t, "duplicate_suspected", results[0].Code, "repeats history")
+	assert.Contains(t, results[0].Error, "old00001")
+	assert.Equal(t, "skipped", results[1].Status)
+	assert.Equal(t, "duplicate_suspected", results[2].Code, "repeats an earlier item")
+	assert.Contains(t, results[2].Error, "line 2")
+	assert.Len(t, readHistoryEntries(t), 1)
+
+	// --force imports them all.
+	captureStdout(t, func() {
+		require.NoError(t, (&QueueImportCmd{Path: path, Force: true}).Run(&Globals{JSON: true}))
+	})
+	assert.Len(t, readHistoryEntries(t), 4)
+}
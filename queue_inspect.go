@@ -3,6 +3,8 @@ package main
 import (
 	"fmt"
 	"os"
+	"slices"
+	"strconv"
 	"strings"
 	"time"
 
@@ -25,24 +27,18 @@ func (cmd *QueueInspectCmd) Run(globals *Globals) error {
 		return (&QueueShowCmd{}).Run(globals)
 	}
 
-	entries, err := history.Queued()
+	cfg, _ := config.Load()
+	predictions, err := loadInspectItems(cfg)
 	if err != nil {
-		return newCLIError(ExitRuntimeError, "load_queue",
-			fmt.Sprintf("Failed to load queue: %s", err))
+		return err
 	}
 
-	if len(entries) == 0 {
+	if len(predictions) == 0 {
 		fmt.Fprintln(os.Stdout, "Queue is empty.")
 		return nil
 	}
 
-	cfg, _ := config.Load()
-	lastPublished, _ := history.LastPublishedTime()
-	now := time.Now().UTC()
-
-	predictions := schedule.PredictPublishTimes(entries, cfg.Schedule, lastPublished, now)
-
-	m := newInspectModel(predictions)
+	m := newInspectModel(predictions, cfg)
 	p := tea.NewProgram(m, tea.WithAltScreen())
 	finalModel, err := p.Run()
 	if err != nil {
@@ -53,6 +49,18 @@ func (cmd *QueueInspectCmd) Run(globals *Globals) error {
 	if fm.deleted > 0 {
 		fmt.Fprintf(os.Stdout, "Removed %d item(s) from queue.\n", fm.deleted)
 	}
+	if fm.approved > 0 {
+		fmt.Fprintf(os.Stdout, "Approved %d draft(s).\n", fm.approved)
+	}
+	if fm.moved > 0 {
+		fmt.Fprintf(os.Stdout, "Reordered queue (%d move(s)).\n", fm.moved)
+	}
+	if fm.held > 0 {
+		fmt.Fprintf(os.Stdout, "Held or released %d item(s).\n", fm.held)
+	}
+	if fm.snoozed > 0 {
+		fmt.Fprintf(os.Stdout, "Snoozed %d item(s).\n", fm.snoozed)
+	}
 	return nil
 }
 
@@ -60,14 +68,24 @@ const (
 	inspectLeftPaneWidth = 26 // width of the list pane
 	inspectSepWidth      = 3  // " │ " separator between panes
 	minSplitWidth        = 60 // minimum terminal width for horizontal split
+
+	inspectDetailHeaderRows = 3 // header + meta + divider above the detail viewport
+
+	inspectShortSnooze = time.Hour      // "s" snoozes the selected item by this much
+	inspectLongSnooze  = 24 * time.Hour // "S" snoozes the selected item by this much
 )
 
 // inspectModel is the Bubble Tea model for the queue inspector.
 type inspectModel struct {
+	cfg             config.Config
 	predictions     []schedule.Prediction
 	renderedContent []string // pre-cached glamour output per item
 	cursor          int
 	deleted         int
+	moved           int
+	approved        int
+	held            int // holds and releases
+	snoozed         int
 	width, height   int
 	message         string // transient status message
 	detailViewport  viewport.Model
@@ -76,7 +94,7 @@ type inspectModel struct {
 	listOffset      int
 }
 
-func newInspectModel(predictions []schedule.Prediction) inspectModel {
+func newInspectModel(predictions []schedule.Prediction, cfg config.Config) inspectModel {
 	vp := viewport.New(80, 10)
 	// Remove "d" from half-page-down (conflicts with delete key).
 	vp.KeyMap.HalfPageDown = key.NewBinding(
@@ -87,6 +105,7 @@ func newInspectModel(predictions []schedule.Prediction) inspectModel {
 	vp.KeyMap.Right.SetEnabled(false)
 
 	return inspectModel{
+		cfg:            cfg,
 		predictions:    predictions,
 		detailViewport: vp,
 	}
@@ -126,6 +145,42 @@ func (m inspectModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 				m.confirmDelete = true
 			}
 			return m, nil
+
+		case "a":
+			if !m.focusDetail {
+				return m.doApprove()
+			}
+			return m, nil
+
+		case "h":
+			if !m.focusDetail {
+				return m.doHold()
+			}
+			return m, nil
+
+		case "s":
+			if !m.focusDetail {
+				return m.doSnooze(inspectShortSnooze)
+			}
+			return m, nil
+
+		case "S":
+			if !m.focusDetail {
+				return m.doSnooze(inspectLongSnooze)
+			}
+			return m, nil
+
+		case "K", "shift+up":
+			if !m.focusDetail {
+				return m.doMove(-1)
+			}
+			return m, nil
+
+		case "J", "shift+down":
+			if !m.focusDetail {
+				return m.doMove(1)
+			}
+			return m, nil
 		}
 
 		// 3. Route to focused pane (viewport handles its own keys).
@@ -199,6 +254,158 @@ func (m inspectModel) doDelete() (tea.Model, tea.Cmd) {
 	return m, nil
 }
 
+// doApprove approves the selected draft and reloads the list.
+func (m inspectModel) doApprove() (tea.Model, tea.Cmd) {
+	if m.cursor >= len(m.predictions) {
+		return m, nil
+	}
+	entry := m.predictions[m.cursor].Entry
+	if entry.Status != "draft" {
+		m.message = "Only drafts need approval."
+		return m, nil
+	}
+	if err := history.Approve(entry.ID); err != nil {
+		m.message = fmt.Sprintf("Cannot approve: %s", err)
+		return m, nil
+	}
+	if err := m.reload(); err != nil {
+		m.message = fmt.Sprintf("Reload failed: %s", err)
+		return m, nil
+	}
+	m.cursor = m.indexOf(entry.ID)
+	m.approved++
+	m.message = fmt.Sprintf("Approved: %s", truncate(firstLine(entry.Message), 40))
+	m.syncDetailContent()
+	m.syncListScroll()
+	return m, nil
+}
+
+// doHold holds the selected entry, or releases it if already held, and
+// reloads the list.
+func (m inspectModel) doHold() (tea.Model, tea.Cmd) {
+	if m.cursor >= len(m.predictions) {
+		return m, nil
+	}
+	entry := m.predictions[m.cursor].Entry
+	if entry.Status == "draft" {
+		m.message = "Drafts are not in the queue yet; approve first."
+		return m, nil
+	}
+	update, action, done := history.Hold, "hold", "Held"
+	if entry.Held {
+		update, action, done = history.Release, "release", "Released"
+	}
+	if _, err := update(entry.ID); err != nil {
+		m.message = fmt.Sprintf("Cannot %s: %s", action, err)
+		return m, nil
+	}
+	if err := m.reload(); err != nil {
+		m.message = fmt.Sprintf("Reload failed: %s", err)
+		return m, nil
+	}
+	m.cursor = m.indexOf(entry.ID)
+	m.held++
+	m.message = fmt.Sprintf("%s: %s", done, truncate(firstLine(entry.Message), 40))
+	m.syncDetailContent()
+	m.syncListScroll()
+	return m, nil
+}
+
+// doSnooze reschedules the selected entry to d from now and reloads the list.
+func (m inspectModel) doSnooze(d time.Duration) (tea.Model, tea.Cmd) {
+	if m.cursor >= len(m.predictions) {
+		return m, nil
+	}
+	entry := m.predictions[m.cursor].Entry
+	snoozed, err := history.Snooze(entry.ID, time.Now().Add(d))
+	if err != nil {
+		m.message = fmt.Sprintf("Cannot snooze: %s", err)
+		return m, nil
+	}
+	if err := m.reload(); err != nil {
+		m.message = fmt.Sprintf("Reload failed: %s", err)
+		return m, nil
+	}
+	m.cursor = m.indexOf(entry.ID)
+	m.snoozed++
+	m.message = fmt.Sprintf("Snoozed until %s: %s", formatExpiry(snoozed.ScheduledAt, m.cfg.Schedule.Location()), truncate(firstLine(entry.Message), 40))
+	m.syncDetailContent()
+	m.syncListScroll()
+	return m, nil
+}
+
+// indexOf returns the list index of the entry with id, or 0 if absent.
+func (m inspectModel) indexOf(id string) int {
+	return max(slices.IndexFunc(m.predictions, func(p schedule.Prediction) bool {
+		return p.Entry.ID == id
+	}), 0)
+}
+
+// doMove shifts the selected entry up (delta < 0) or down (delta > 0) past
+// its neighbouring queued entry, persists the new order, and reloads
+// predictions. The list is in predicted order, with recurring instances
+// mixed in, so the move is made relative to the neighbour's ID rather than
+// by position.
+func (m inspectModel) doMove(delta int) (tea.Model, tea.Cmd) {
+	if m.cursor >= len(m.predictions) {
+		return m, nil
+	}
+	if m.predictions[m.cursor].Recurring {
+		m.message = "Recurring posts are not in the queue yet; they are queued when due."
+		return m, nil
+	}
+	target := m.cursor + delta
+	for target >= 0 && target < len(m.predictions) && m.predictions[target].Recurring {
+		target += delta
+	}
+	if target < 0 || target >= len(m.predictions) {
+		return m, nil
+	}
+
+	entry := m.predictions[m.cursor].Entry
+	neighbour := m.predictions[target].Entry
+	if entry.Status == "draft" || neighbour.Status == "draft" {
+		m.message = "Drafts are not in the queue yet; approve first."
+		return m, nil
+	}
+	move := history.MoveAfter
+	if delta < 0 {
+		move = history.MoveBefore
+	}
+	if err := move(entry.ID, neighbour.ID); err != nil {
+		m.message = fmt.Sprintf("Cannot move: %s", err)
+		return m, nil
+	}
+	if err := m.reload(); err != nil {
+		m.message = fmt.Sprintf("Reload failed: %s", err)
+		return m, nil
+	}
+
+	m.cursor = m.indexOf(entry.ID)
+	m.moved++
+	m.message = fmt.Sprintf("Moved to #%d: %s", m.cursor+1, truncate(firstLine(entry.Message), 40))
+	if p := m.predictions[m.cursor].Entry.Priority; p != entry.Priority {
+		m.message += fmt.Sprintf(" (priority %d → %d)", entry.Priority, p)
+	}
+	m.syncDetailContent()
+	m.syncListScroll()
+	return m, nil
+}
+
+// reload re-reads the queue and re-renders the detail content.
+func (m *inspectModel) reload() error {
+	predictions, err := loadInspectItems(m.cfg)
+	if err != nil {
+		return err
+	}
+	m.predictions = predictions
+	m.renderAllContent()
+	if m.cursor >= len(m.predictions) {
+		m.cursor = max(len(m.predictions)-1, 0)
+	}
+	return nil
+}
+
 // contentRows returns the number of rows available for the content area.
 func (m inspectModel) contentRows() int {
 	overhead := 2 // title + help
@@ -235,7 +442,7 @@ func (m *inspectModel) updateViewportSize() {
 		return
 	}
 	rows := m.contentRows()
-	vpHeight := max(rows-2, 1) // subtract header + divider in right pane
+	vpHeight := max(rows-inspectDetailHeaderRows, 1) // subtract header + meta + divider in right pane
 	m.detailViewport.Width = m.rightPaneWidth()
 	m.detailViewport.Height = vpHeight
 }
@@ -307,13 +514,9 @@ func (m inspectModel) viewNarrow(b *strings.Builder) {
 	end := min(m.listOffset+rows, len(m.predictions))
 	for i := m.listOffset; i < end; i++ {
 		p := m.predictions[i]
-		timeStr := formatPredictedTime(p.PublishAt)
-		if p.Approximate {
-			timeStr = "~" + timeStr
-		}
 		msg := truncate(firstLine(p.Entry.Message), max(m.width-26, 10))
 
-		line := fmt.Sprintf("  %-4d %-19s %s", p.Position, timeStr, msg)
+		line := fmt.Sprintf("  %-4s %-19s %s", inspectPos(p), inspectTime(p, m.cfg.Schedule.Location()), msg)
 		if i == m.cursor {
 			sel := "> " + line[2:]
 			if m.confirmDelete {
@@ -363,13 +566,14 @@ func (m inspectModel) viewSplit(b *strings.Builder) {
 
 	// Right pane: fixed header + divider + viewport lines.
 	p := m.predictions[m.cursor]
-	timeStr := formatPredictedTime(p.PublishAt)
+	timeStr := inspectTime(p, m.cfg.Schedule.Location())
 	idShort := p.Entry.ID
 	if len(idShort) > 8 {
 		idShort = idShort[:8]
 	}
 	header := inspectDimStyle.Render(
-		fmt.Sprintf("#%d · %s · %s", p.Position, timeStr, idShort))
+		fmt.Sprintf("#%s · %s · %s", inspectPos(p), timeStr, idShort))
+	meta := inspectDimStyle.Render(truncate(inspectMeta(p.Entry, m.cfg.Schedule.Location()), rightW))
 	divider := inspectDimStyle.Render(strings.Repeat("─", rightW))
 
 	vpLines := strings.Split(m.detailViewport.View(), "\n")
@@ -382,9 +586,11 @@ func (m inspectModel) viewSplit(b *strings.Builder) {
 		case 0:
 			b.WriteString(header)
 		case 1:
+			b.WriteString(meta)
+		case 2:
 			b.WriteString(divider)
 		default:
-			vpIdx := i - 2
+			vpIdx := i - inspectDetailHeaderRows
 			if vpIdx < len(vpLines) {
 				b.WriteString(vpLines[vpIdx])
 			}
@@ -398,14 +604,60 @@ func (m inspectModel) viewSplit(b *strings.Builder) {
 	b.WriteString("\n")
 }
 
+// inspectMeta returns the tags and provenance line for the detail pane.
+func inspectMeta(e history.Entry, loc *time.Location) string {
+	var parts []string
+	if len(e.Tags) > 0 {
+		parts = append(parts, formatTags(e.Tags))
+	}
+	if prov := formatProvenance(e.Agent, e.SessionID, e.Source); prov != "" {
+		parts = append(parts, prov)
+	}
+	if e.ExpiresAt != "" {
+		parts = append(parts, "expires "+formatExpiry(e.ExpiresAt, loc))
+	}
+	return strings.Join(parts, " · ")
+}
+
+// loadInspectItems returns queue predictions followed by drafts. Drafts
+// have no position or publish time until approved.
+func loadInspectItems(cfg config.Config) ([]schedule.Prediction, error) {
+	predictions, err := loadPredictions(cfg, false)
+	if err != nil {
+		return nil, err
+	}
+	drafts, err := history.Drafts()
+	if err != nil {
+		return nil, historyFailure(err, "load_queue",
+			fmt.Sprintf("Failed to load drafts: %s", err))
+	}
+	for _, d := range drafts {
+		predictions = append(predictions, schedule.Prediction{Entry: d})
+	}
+	return predictions, nil
+}
+
+// inspectPos returns the queue position label ("-" for drafts).
+func inspectPos(p schedule.Prediction) string {
+	if p.Entry.Status == "draft" {
+		return "-"
+	}
+	return strconv.Itoa(p.Position)
+}
+
+// inspectTime returns the predicted publish label ("draft" for drafts,
+// "held" for held entries).
+func inspectTime(p schedule.Prediction, loc *time.Location) string {
+	if p.Entry.Status == "draft" {
+		return "draft"
+	}
+	return formatPredictionTime(p, loc)
+}
+
 // renderListItem renders a single list entry for the left pane.
 func (m inspectModel) renderListItem(idx int, baseStyle lipgloss.Style) string {
 	p := m.predictions[idx]
-	timeStr := formatPredictedTime(p.PublishAt)
-	if p.Approximate {
-		timeStr = "~" + timeStr
-	}
-	content := fmt.Sprintf("%d  %s", p.Position, timeStr)
+	content := fmt.Sprintf("%s  %s", inspectPos(p), inspectTime(p, m.cfg.Schedule.Location()))
 
 	if idx == m.cursor {
 		color := lipgloss.Color("212")
@@ -422,10 +674,10 @@ func (m inspectModel) helpText() string {
 		return "y: confirm   n: cancel"
 	}
 	if m.width < minSplitWidth {
-		return "↑↓: navigate   d: delete   q: quit"
+		return "↑↓: navigate   J/K: move   a: approve   h: hold   s/S: snooze 1h/1d   d: delete   q: quit"
 	}
 	if m.focusDetail {
 		return "↑↓: scroll   tab: list   d: delete   q: quit"
 	}
-	return "↑↓: navigate   tab: detail   d: delete   q: quit"
+	return "↑↓: navigate   J/K: move   a: approve   h: hold   s/S: snooze 1h/1d   tab: detail   d: delete   q: quit"
 }
@@ -0,0 +1,108 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/lvrach/slack-social-ai/internal/config"
+	"github.com/lvrach/slack-social-ai/internal/schedule"
+)
+
+// QueuePauseCmd stops publishing without uninstalling the timer.
+type QueuePauseCmd struct {
+	Until string `help:"Resume automatically at this time (HH:MM, duration like 2h or 3d, or RFC3339). Default: until resumed." short:"u"`
+}
+
+func (cmd *QueuePauseCmd) Run(globals *Globals) error {
+	cfg, err := config.Load()
+	if err != nil {
+		return newCLIError(ExitRuntimeError, "config_error",
+			fmt.Sprintf("Failed to load config: %s", err))
+	}
+
+	now := time.Now().In(cfg.Schedule.Location())
+	var until time.Time
+	if cmd.Until != "" {
+		t, err := parseAtFrom(cmd.Until, now)
+		if err != nil {
+			d, ttlErr := parseTTL(cmd.Until)
+			if ttlErr != nil {
+				return err
+			}
+			t = now.Add(d)
+		}
+		if !t.After(now) {
+			return newCLIError(ExitInvalidInput, "invalid_time",
+				fmt.Sprintf("Pause end %q is in the past.", cmd.Until))
+		}
+		until = t
+	}
+
+	cfg.Schedule.Paused = true
+	cfg.Schedule.PausedUntil = ""
+	if !until.IsZero() {
+		cfg.Schedule.PausedUntil = until.UTC().Format(time.RFC3339)
+	}
+	if err := config.Save(cfg); err != nil {
+		return newCLIError(ExitRuntimeError, "config_error",
+			fmt.Sprintf("Failed to save config: %s", err))
+	}
+
+	if globals.JSON {
+		resp := map[string]any{"status": "ok", "paused": true}
+		if !until.IsZero() {
+			resp["paused_until"] = cfg.Schedule.PausedUntil
+		}
+		b, _ := json.Marshal(resp)
+		fmt.Fprintln(os.Stdout, string(b))
+	} else {
+		printSuccessHuman(formatPause(cfg.Schedule, now) + " Resume with `slack-social-ai queue resume`.")
+	}
+	return nil
+}
+
+// QueueResumeCmd lifts a pause set with `queue pause`.
+type QueueResumeCmd struct{}
+
+func (cmd *QueueResumeCmd) Run(globals *Globals) error {
+	cfg, err := config.Load()
+	if err != nil {
+		return newCLIError(ExitRuntimeError, "config_error",
+			fmt.Sprintf("Failed to load config: %s", err))
+	}
+
+	msg := "Publishing was not paused."
+	if paused, _ := cfg.Schedule.PausedAt(time.Now()); paused {
+		msg = "Publishing resumed."
+	}
+	if cfg.Schedule.Paused {
+		cfg.Schedule.Paused = false
+		cfg.Schedule.PausedUntil = ""
+		if err := config.Save(cfg); err != nil {
+			return newCLIError(ExitRuntimeError, "config_error",
+				fmt.Sprintf("Failed to save config: %s", err))
+		}
+	}
+
+	if globals.JSON {
+		printSuccessJSON(msg)
+	} else {
+		printSuccessHuman(msg)
+	}
+	return nil
+}
+
+// formatPause describes a pause in effect at now, or returns "" if
+// publishing is not paused.
+func formatPause(sched schedule.Schedule, now time.Time) string {
+	paused, until := sched.PausedAt(now)
+	switch {
+	case !paused:
+		return ""
+	case until.IsZero():
+		return "Publishing paused until resumed."
+	}
+	return fmt.Sprintf("Publishing paused until %s.", formatPredictedTime(until, sched.Location()))
+}
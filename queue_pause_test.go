@@ -0,0 +1,102 @@
+package main
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/lvrach/slack-social-ai/internal/config"
+	"github.com/lvrach/slack-social-ai/internal/history"
+)
+
+func TestQueuePauseAndResume(t *testing.T) {
+	withTempHome(t)
+
+	output := captureStdout(t, func() {
+		require.NoError(t, (&QueuePauseCmd{Until: "2h"}).Run(&Globals{JSON: true}))
+	})
+	var resp map[string]any
+	require.NoError(t, json.Unmarshal([]byte(output), &resp))
+	assert.Equal(t, true, resp["paused"])
+
+	cfg, err := config.Load()
+	require.NoError(t, err)
+	assert.True(t, cfg.Schedule.Paused)
+	until, err := time.Parse(time.RFC3339, cfg.Schedule.PausedUntil)
+	require.NoError(t, err)
+	assert.WithinDuration(t, time.Now().Add(2*time.Hour), until, time.Minute)
+
+	output = captureStdout(t, func() {
+		require.NoError(t, (&QueueShowCmd{}).Run(&Globals{}))
+	})
+	assert.Contains(t, output, "Publishing paused until")
+
+	captureStdout(t, func() {
+		require.NoError(t, (&QueueResumeCmd{}).Run(&Globals{JSON: true}))
+	})
+	cfg, err = config.Load()
+	require.NoError(t, err)
+	assert.False(t, cfg.Schedule.Paused)
+	assert.Empty(t, cfg.Schedule.PausedUntil)
+}
+
+func TestQueuePause_UntilScheduleTimezone(t *testing.T) {
+	withTempHome(t)
+	cfg := config.Default()
+	cfg.Schedule.Timezone = "Asia/Tokyo"
+	require.NoError(t, config.Save(cfg))
+
+	captureStdout(t, func() {
+		require.NoError(t, (&QueuePauseCmd{Until: "10:00"}).Run(&Globals{JSON: true}))
+	})
+	cfg, err := config.Load()
+	require.NoError(t, err)
+	until, err := time.Parse(time.RFC3339, cfg.Schedule.PausedUntil)
+	require.NoError(t, err)
+	assert.Equal(t, 10, until.In(cfg.Schedule.Location()).Hour())
+	assert.Equal(t, 0, until.In(cfg.Schedule.Location()).Minute())
+}
+
+func TestQueuePause_InvalidUntil(t *testing.T) {
+	withTempHome(t)
+
+	var cliErr *CLIError
+	err := (&QueuePauseCmd{Until: "whenever"}).Run(&Globals{})
+	require.True(t, asCLIError(err, &cliErr))
+	assert.Equal(t, "invalid_time", cliErr.Code)
+
+	err = (&QueuePauseCmd{Until: "2020-01-01T00:00:00Z"}).Run(&Globals{})
+	require.True(t, asCLIError(err, &cliErr))
+	assert.Equal(t, "invalid_time", cliErr.Code)
+}
+
+func TestPublish_Paused(t *testing.T) {
+	withTempHome(t)
+	_, err := history.Append("Hold this", "queued", time.Time{})
+	require.NoError(t, err)
+
+	cfg := config.Config{Schedule: alwaysActiveSchedule()}
+	cfg.Schedule.Paused = true
+	output := captureStdout(t, func() {
+		require.NoError(t, (&PublishCmd{}).publishOne("http://unused", cfg, &Globals{JSON: true}, false))
+	})
+	var resp map[string]string
+	require.NoError(t, json.Unmarshal([]byte(output), &resp))
+	assert.Equal(t, "paused", resp["status"])
+	assert.Empty(t, resp["paused_until"])
+
+	cfg.Schedule.PausedUntil = time.Now().Add(time.Hour).UTC().Format(time.RFC3339)
+	output = captureStdout(t, func() {
+		require.NoError(t, (&PublishCmd{}).publishOne("http://unused", cfg, &Globals{JSON: true}, false))
+	})
+	require.NoError(t, json.Unmarshal([]byte(output), &resp))
+	assert.Equal(t, "paused", resp["status"])
+	assert.Equal(t, cfg.Schedule.PausedUntil, resp["paused_until"])
+
+	entries := readHistoryEntries(t)
+	require.Len(t, entries, 1)
+	assert.Equal(t, "queued", entries[0].Status)
+}
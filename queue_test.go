@@ -116,7 +116,7 @@ func TestQueueRemove_NotFound(t *testing.T) {
 func TestFormatPredictedTime_Today(t *testing.T) {
 	now := time.Now()
 	todayAt1430 := time.Date(now.Year(), now.Month(), now.Day(), 14, 30, 0, 0, now.Location())
-	result := formatPredictedTime(todayAt1430)
+	result := formatPredictedTime(todayAt1430, time.Local)
 	assert.Contains(t, result, "Today")
 	assert.Contains(t, result, "14:30")
 }
@@ -124,7 +124,7 @@ func TestFormatPredictedTime_Today(t *testing.T) {
 func TestFormatPredictedTime_Tomorrow(t *testing.T) {
 	tomorrow := time.Now().AddDate(0, 0, 1)
 	tomorrowAt9 := time.Date(tomorrow.Year(), tomorrow.Month(), tomorrow.Day(), 9, 0, 0, 0, tomorrow.Location())
-	result := formatPredictedTime(tomorrowAt9)
+	result := formatPredictedTime(tomorrowAt9, time.Local)
 	assert.Contains(t, result, "Tomorrow")
 	assert.Contains(t, result, "09:00")
 }
@@ -194,3 +194,222 @@ func TestQueueShow_MultiLine_Human(t *testing.T) {
 	assert.NotContains(t, output, "Line four")
 	assert.NotContains(t, output, "Line five")
 }
+
+func TestQueueShow_TagFilter_JSON(t *testing.T) {
+	withTempHome(t)
+
+	_, err := history.AppendEntry(history.Entry{Message: "r/go\nfirst", Status: "queued", Tags: []string{"go"}})
+	require.NoError(t, err)
+	second, err := history.AppendEntry(history.Entry{Message: "r/ai\nsecond", Status: "queued", Tags: []string{"ai"}})
+	require.NoError(t, err)
+
+	cmd := &QueueShowCmd{Tag: []string{"r/ai"}}
+	globals := &Globals{JSON: true}
+
+	output := captureStdout(t, func() {
+		assert.NoError(t, cmd.Run(globals))
+	})
+
+	var resp map[string]any
+	require.NoError(t, json.Unmarshal([]byte(output), &resp))
+	queue := resp["queue"].([]any)
+	require.Len(t, queue, 1)
+	item := queue[0].(map[string]any)
+	assert.Equal(t, second.ID, item["id"])
+	assert.Equal(t, float64(2), item["position"], "position reflects the full queue")
+	assert.Equal(t, []any{"ai"}, item["tags"])
+}
+
+func TestHistoryStats_TagCounts(t *testing.T) {
+	withTempHome(t)
+
+	for _, tags := range [][]string{{"go"}, {"go", "til"}, {"ai"}, nil} {
+		_, err := history.AppendEntry(history.Entry{Message: "msg", Status: "published", Tags: tags})
+		require.NoError(t, err)
+	}
+
+	cmd := &HistoryStatsCmd{}
+	output := captureStdout(t, func() {
+		assert.NoError(t, cmd.Run(&Globals{JSON: true}))
+	})
+
+	var resp struct {
+		Total    int        `json:"total"`
+		Tags     []tagCount `json:"tags"`
+		Untagged int        `json:"untagged"`
+	}
+	require.NoError(t, json.Unmarshal([]byte(output), &resp))
+	assert.Equal(t, 4, resp.Total)
+	assert.Equal(t, 1, resp.Untagged)
+	assert.Equal(t, []tagCount{{"go", 2}, {"ai", 1}, {"til", 1}}, resp.Tags)
+}
+
+func TestQueueMove_Top(t *testing.T) {
+	withTempHome(t)
+
+	first, err := history.Append("first", "queued", time.Time{})
+	require.NoError(t, err)
+	second, err := history.Append("second", "queued", time.Time{})
+	require.NoError(t, err)
+
+	cmd := &QueueMoveCmd{ID: second.ID, Top: true}
+	output := captureStdout(t, func() {
+		assert.NoError(t, cmd.Run(&Globals{JSON: true}))
+	})
+
+	var resp map[string]any
+	require.NoError(t, json.Unmarshal([]byte(output), &resp))
+	assert.Equal(t, float64(1), resp["position"])
+
+	queued, err := history.Queued()
+	require.NoError(t, err)
+	require.Len(t, queued, 2)
+	assert.Equal(t, second.ID, queued[0].ID)
+	assert.Equal(t, first.ID, queued[1].ID)
+}
+
+func TestQueueMove_ReportsPriorityChange(t *testing.T) {
+	withTempHome(t)
+
+	_, err := history.AppendEntry(history.Entry{Message: "urgent", Status: "queued", Priority: 5})
+	require.NoError(t, err)
+	low, err := history.Append("routine", "queued", time.Time{})
+	require.NoError(t, err)
+
+	output := captureStdout(t, func() {
+		require.NoError(t, (&QueueMoveCmd{ID: low.ID, Top: true}).Run(&Globals{}))
+	})
+	assert.Contains(t, output, "priority changed from 0 to 5")
+
+	output = captureStdout(t, func() {
+		require.NoError(t, (&QueueMoveCmd{ID: low.ID, To: 1}).Run(&Globals{JSON: true}))
+	})
+	var resp map[string]any
+	require.NoError(t, json.Unmarshal([]byte(output), &resp))
+	assert.InDelta(t, 5, resp["priority"], 0)
+	assert.NotContains(t, resp, "previous_priority", "unchanged priority is not reported")
+}
+
+func TestQueueMove_NotFound(t *testing.T) {
+	withTempHome(t)
+
+	err := (&QueueMoveCmd{ID: "nope", Top: true}).Run(&Globals{})
+	require.Error(t, err)
+	var cliErr *CLIError
+	require.True(t, asCLIError(err, &cliErr))
+	assert.Equal(t, "not_found", cliErr.Code)
+}
+
+func TestQueueMove_MissingTarget(t *testing.T) {
+	withTempHome(t)
+
+	err := (&QueueMoveCmd{ID: "abc"}).Run(&Globals{})
+	var cliErr *CLIError
+	require.True(t, asCLIError(err, &cliErr))
+	assert.Equal(t, "missing_target", cliErr.Code)
+}
+
+func TestQueueShow_Drafts_JSON(t *testing.T) {
+	withTempHome(t)
+
+	_, err := history.Append("queued post", "queued", time.Time{})
+	require.NoError(t, err)
+	draft, err := history.Append("draft post", "draft", time.Time{})
+	require.NoError(t, err)
+
+	output := captureStdout(t, func() {
+		assert.NoError(t, (&QueueShowCmd{}).Run(&Globals{JSON: true}))
+	})
+
+	var resp map[string]any
+	require.NoError(t, json.Unmarshal([]byte(output), &resp))
+	assert.Equal(t, float64(1), resp["count"])
+	drafts := resp["drafts"].([]any)
+	require.Len(t, drafts, 1)
+	item := drafts[0].(map[string]any)
+	assert.Equal(t, draft.ID, item["id"])
+	assert.Equal(t, "draft", item["status"])
+	assert.Nil(t, item["predicted_publish_at"])
+}
+
+func TestQueueShow_Drafts_Human(t *testing.T) {
+	withTempHome(t)
+
+	_, err := history.Append("draft post", "draft", time.Time{})
+	require.NoError(t, err)
+
+	output := captureStdout(t, func() {
+		assert.NoError(t, (&QueueShowCmd{}).Run(&Globals{}))
+	})
+
+	assert.Contains(t, output, "Queue is empty.")
+	assert.Contains(t, output, "Drafts awaiting approval (1)")
+	assert.Contains(t, output, "draft post")
+}
+
+func TestQueueApprove(t *testing.T) {
+	withTempHome(t)
+
+	d1, err := history.Append("one", "draft", time.Time{})
+	require.NoError(t, err)
+	_, err = history.Append("two", "draft", time.Time{})
+	require.NoError(t, err)
+
+	captureStdout(t, func() {
+		assert.NoError(t, (&QueueApproveCmd{IDs: []string{d1.ID}}).Run(&Globals{}))
+	})
+	queued, err := history.Queued()
+	require.NoError(t, err)
+	require.Len(t, queued, 1)
+	assert.Equal(t, d1.ID, queued[0].ID)
+
+	captureStdout(t, func() {
+		assert.NoError(t, (&QueueApproveCmd{All: true}).Run(&Globals{}))
+	})
+	drafts, err := history.Drafts()
+	require.NoError(t, err)
+	assert.Empty(t, drafts)
+}
+
+func TestQueueApprove_NotDraft(t *testing.T) {
+	withTempHome(t)
+
+	e, err := history.Append("queued", "queued", time.Time{})
+	require.NoError(t, err)
+
+	err = (&QueueApproveCmd{IDs: []string{e.ID}}).Run(&Globals{})
+	var cliErr *CLIError
+	require.True(t, asCLIError(err, &cliErr))
+	assert.Equal(t, "not_draft", cliErr.Code)
+}
+
+func TestQueueShow_ExpiryWarning(t *testing.T) {
+	withTempHome(t)
+
+	// Expires in a minute but the second post can't go out until a full
+	// post interval after the first.
+	_, err := history.Append("first", "queued", time.Time{})
+	require.NoError(t, err)
+	soon := time.Now().Add(time.Minute).UTC().Format(time.RFC3339)
+	_, err = history.AppendEntry(history.Entry{Message: "stale soon", Status: "queued", ExpiresAt: soon})
+	require.NoError(t, err)
+
+	output := captureStdout(t, func() {
+		assert.NoError(t, (&QueueShowCmd{}).Run(&Globals{JSON: true}))
+	})
+
+	var resp map[string]any
+	require.NoError(t, json.Unmarshal([]byte(output), &resp))
+	items := resp["queue"].([]any)
+	require.Len(t, items, 2)
+	assert.Nil(t, items[0].(map[string]any)["expires_before_publish"])
+	second := items[1].(map[string]any)
+	assert.Equal(t, soon, second["expires_at"])
+	assert.Equal(t, true, second["expires_before_publish"])
+
+	output = captureStdout(t, func() {
+		assert.NoError(t, (&QueueShowCmd{}).Run(&Globals{}))
+	})
+	assert.Contains(t, output, "warning: expires before publishing")
+	assert.Contains(t, output, "1 message(s) will expire")
+}
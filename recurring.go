@@ -0,0 +1,180 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/lvrach/slack-social-ai/internal/history"
+)
+
+// RecurringCmd manages recurring post templates created with `post --every`.
+type RecurringCmd struct {
+	List   RecurringListCmd   `cmd:"" default:"withargs" help:"List recurring posts and their next occurrence."`
+	Pause  RecurringPauseCmd  `cmd:"" help:"Stop a recurring post from spawning until resumed."`
+	Resume RecurringResumeCmd `cmd:"" help:"Resume a paused recurring post from its next occurrence."`
+	Delete RecurringDeleteCmd `cmd:"" help:"Delete a recurring post (already spawned posts stay queued)."`
+}
+
+// RecurringListCmd lists recurring templates.
+type RecurringListCmd struct{}
+
+// jsonRecurrence is the JSON shape of a recurring template.
+type jsonRecurrence struct {
+	ID          string   `json:"id"`
+	Message     string   `json:"message"`
+	Every       string   `json:"every"`
+	NextAt      string   `json:"next_at,omitempty"`
+	Paused      bool     `json:"paused"`
+	Count       int      `json:"count"`
+	ExpireAfter string   `json:"expire_after,omitempty"`
+	Draft       bool     `json:"draft,omitempty"`
+	Tags        []string `json:"tags,omitempty"`
+	Priority    int      `json:"priority,omitempty"`
+}
+
+func newJSONRecurrence(e history.Entry) jsonRecurrence {
+	rec := e.Recurrence
+	return jsonRecurrence{
+		ID:          e.ID,
+		Message:     e.Message,
+		Every:       rec.Every,
+		NextAt:      rec.NextAt,
+		Paused:      rec.Paused,
+		Count:       rec.Count,
+		ExpireAfter: rec.ExpireAfter,
+		Draft:       rec.Draft,
+		Tags:        e.Tags,
+		Priority:    e.Priority,
+	}
+}
+
+func (cmd *RecurringListCmd) Run(globals *Globals) error {
+	templates, err := history.Recurrences()
+	if err != nil {
+		return historyFailure(err, "load_failed",
+			fmt.Sprintf("Failed to load recurring posts: %s", err))
+	}
+
+	if globals.JSON {
+		items := make([]jsonRecurrence, 0, len(templates))
+		for _, e := range templates {
+			if e.Recurrence != nil {
+				items = append(items, newJSONRecurrence(e))
+			}
+		}
+		b, _ := json.Marshal(map[string]any{"recurring": items, "count": len(items)})
+		fmt.Fprintln(os.Stdout, string(b))
+		return nil
+	}
+
+	if len(templates) == 0 {
+		fmt.Fprintln(os.Stdout, "No recurring posts. Create one with `slack-social-ai post --every \"mon 10:00\"`.")
+		return nil
+	}
+	loc := scheduleLocation()
+	fmt.Fprintf(os.Stdout, " %-8s %-16s %-19s %s\n", "ID", "Every", "Next", "Message")
+	for _, e := range templates {
+		rec := e.Recurrence
+		if rec == nil {
+			continue
+		}
+		fmt.Fprintf(os.Stdout, " %-8s %-16s %-19s %s\n",
+			e.ID, truncate(rec.Every, 16), formatNextOccurrence(rec, loc), messagePreview(e.Message, 1, 0, 50)[0])
+	}
+	return nil
+}
+
+// formatNextOccurrence renders when a template next spawns.
+func formatNextOccurrence(rec *history.Recurrence, loc *time.Location) string {
+	switch {
+	case rec.Paused:
+		return "paused"
+	case rec.NextAt == "":
+		return "never"
+	}
+	return formatExpiry(rec.NextAt, loc)
+}
+
+// RecurringPauseCmd pauses a recurring template.
+type RecurringPauseCmd struct {
+	ID string `arg:"" help:"ID of the recurring post."`
+}
+
+func (cmd *RecurringPauseCmd) Run(globals *Globals) error {
+	if _, err := history.PauseRecurrence(cmd.ID); err != nil {
+		return recurrenceError(err)
+	}
+	msg := fmt.Sprintf("Paused recurring post %s.", cmd.ID)
+	if globals.JSON {
+		printSuccessJSON(msg)
+	} else {
+		printSuccessHuman(msg)
+	}
+	return nil
+}
+
+// RecurringResumeCmd resumes a paused recurring template.
+type RecurringResumeCmd struct {
+	ID string `arg:"" help:"ID of the recurring post."`
+}
+
+func (cmd *RecurringResumeCmd) Run(globals *Globals) error {
+	loc := scheduleLocation()
+	e, err := history.ResumeRecurrence(cmd.ID, time.Now().In(loc))
+	if err != nil {
+		return recurrenceError(err)
+	}
+	if globals.JSON {
+		b, _ := json.Marshal(map[string]string{"status": "ok", "id": e.ID, "next_at": e.Recurrence.NextAt})
+		fmt.Fprintln(os.Stdout, string(b))
+	} else {
+		printSuccessHuman(fmt.Sprintf("Resumed recurring post %s. Next: %s.", e.ID, formatNextOccurrence(e.Recurrence, loc)))
+	}
+	return nil
+}
+
+// RecurringDeleteCmd deletes a recurring template.
+type RecurringDeleteCmd struct {
+	ID string `arg:"" help:"ID of the recurring post."`
+}
+
+func (cmd *RecurringDeleteCmd) Run(globals *Globals) error {
+	e, err := history.Get(cmd.ID)
+	if err != nil {
+		return recurrenceError(err)
+	}
+	if e.Status != "recurring" {
+		return recurrenceError(fmt.Errorf("entry %q (%s): %w", cmd.ID, e.Status, history.ErrNotRecurring))
+	}
+	found, err := history.Remove(cmd.ID)
+	if err != nil {
+		return historyFailure(err, "remove_failed",
+			fmt.Sprintf("Failed to delete recurring post: %s", err))
+	}
+	if !found {
+		return recurrenceError(fmt.Errorf("entry %q: %w", cmd.ID, history.ErrNotFound))
+	}
+
+	msg := fmt.Sprintf("Deleted recurring post %s. Run `slack-social-ai undo` to restore.", cmd.ID)
+	if globals.JSON {
+		printSuccessJSON(msg)
+	} else {
+		printSuccessHuman(msg)
+	}
+	return nil
+}
+
+// recurrenceError maps errors from recurrence operations to CLIErrors.
+func recurrenceError(err error) error {
+	switch {
+	case errors.Is(err, history.ErrNotFound):
+		return newCLIError(ExitInvalidInput, "not_found", err.Error())
+	case errors.Is(err, history.ErrNotRecurring):
+		return newCLIError(ExitInvalidInput, "not_recurring", err.Error())
+	}
+	return historyFailure(err, "recurring_failed",
+		fmt.Sprintf("Failed to update recurring post: %s", err))
+}
@@ -0,0 +1,205 @@
+package main
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/lvrach/slack-social-ai/internal/config"
+	"github.com/lvrach/slack-social-ai/internal/history"
+)
+
+func TestPostSaveRecurring(t *testing.T) {
+	withTempHome(t)
+
+	cmd := &PostCmd{Every: "fri 16:00", Expires: "12h", Priority: 1}
+	entry := newEntry("Fun friday #{{n}}", "recurring", []string{"fun"}, Provenance{})
+	output := captureStdout(t, func() {
+		require.NoError(t, cmd.saveRecurring(&Globals{JSON: true}, config.Config{}, entry, false, false))
+	})
+
+	var resp map[string]string
+	require.NoError(t, json.Unmarshal([]byte(output), &resp))
+	assert.Equal(t, "recurring", resp["status"])
+	assert.Equal(t, "fri 16:00", resp["every"])
+
+	next, err := time.Parse(time.RFC3339, resp["next_at"])
+	require.NoError(t, err)
+	assert.Equal(t, time.Friday, next.Local().Weekday())
+	assert.True(t, next.After(time.Now()))
+
+	templates, err := history.Recurrences()
+	require.NoError(t, err)
+	require.Len(t, templates, 1)
+	assert.Equal(t, resp["id"], templates[0].ID)
+	assert.Equal(t, "12h0m0s", templates[0].Recurrence.ExpireAfter)
+	assert.Equal(t, 1, templates[0].Priority)
+
+	queued, err := history.Queued()
+	require.NoError(t, err)
+	assert.Empty(t, queued, "the template itself is not queued")
+}
+
+func TestPostSaveRecurring_ScheduleTimezone(t *testing.T) {
+	withTempHome(t)
+	cfg := config.Default()
+	cfg.Schedule.Timezone = "America/New_York"
+
+	entry := newEntry("Weekly", "recurring", nil, Provenance{})
+	output := captureStdout(t, func() {
+		require.NoError(t, (&PostCmd{Every: "mon 10:00"}).saveRecurring(&Globals{JSON: true}, cfg, entry, false, false))
+	})
+	var resp map[string]string
+	require.NoError(t, json.Unmarshal([]byte(output), &resp))
+	next, err := time.Parse(time.RFC3339, resp["next_at"])
+	require.NoError(t, err)
+	local := next.In(cfg.Schedule.Location())
+	assert.Equal(t, time.Monday, local.Weekday())
+	assert.Equal(t, 10, local.Hour())
+}
+
+func TestPostSaveRecurring_Invalid(t *testing.T) {
+	withTempHome(t)
+
+	for _, every := range []string{"someday 10:00", "0 0 30 2 *"} {
+		cmd := &PostCmd{Every: every}
+		err := cmd.saveRecurring(&Globals{}, config.Config{}, newEntry("m", "recurring", nil, Provenance{}), false, false)
+		var cliErr *CLIError
+		require.True(t, asCLIError(err, &cliErr), every)
+		assert.Equal(t, "invalid_recurrence", cliErr.Code)
+		assert.Equal(t, ExitInvalidInput, cliErr.ExitCode)
+	}
+}
+
+func appendRecurring(t *testing.T, message, every, nextAt string) history.Entry {
+	t.Helper()
+	e, err := history.AppendEntry(history.Entry{
+		Message:    message,
+		Status:     "recurring",
+		Recurrence: &history.Recurrence{Every: every, NextAt: nextAt},
+	})
+	require.NoError(t, err)
+	return e
+}
+
+func TestRecurringList_JSON(t *testing.T) {
+	withTempHome(t)
+	tmpl := appendRecurring(t, "Sprint {{week}} learnings", "mon 10:00", "2026-03-02T10:00:00Z")
+
+	output := captureStdout(t, func() {
+		require.NoError(t, (&RecurringListCmd{}).Run(&Globals{JSON: true}))
+	})
+
+	var resp struct {
+		Recurring []jsonRecurrence `json:"recurring"`
+		Count     int              `json:"count"`
+	}
+	require.NoError(t, json.Unmarshal([]byte(output), &resp))
+	require.Equal(t, 1, resp.Count)
+	assert.Equal(t, tmpl.ID, resp.Recurring[0].ID)
+	assert.Equal(t, "mon 10:00", resp.Recurring[0].Every)
+	assert.Equal(t, "2026-03-02T10:00:00Z", resp.Recurring[0].NextAt)
+	assert.False(t, resp.Recurring[0].Paused)
+}
+
+func TestRecurringPauseResume(t *testing.T) {
+	withTempHome(t)
+	tmpl := appendRecurring(t, "m", "mon 10:00", "2020-01-06T10:00:00Z")
+
+	captureStdout(t, func() {
+		require.NoError(t, (&RecurringPauseCmd{ID: tmpl.ID}).Run(&Globals{}))
+	})
+	got, err := history.Get(tmpl.ID)
+	require.NoError(t, err)
+	assert.True(t, got.Recurrence.Paused)
+
+	output := captureStdout(t, func() {
+		require.NoError(t, (&RecurringResumeCmd{ID: tmpl.ID}).Run(&Globals{JSON: true}))
+	})
+	var resp map[string]string
+	require.NoError(t, json.Unmarshal([]byte(output), &resp))
+	next, err := time.Parse(time.RFC3339, resp["next_at"])
+	require.NoError(t, err)
+	assert.True(t, next.After(time.Now()), "resume skips missed occurrences")
+}
+
+func TestRecurringDelete(t *testing.T) {
+	withTempHome(t)
+	tmpl := appendRecurring(t, "m", "daily 09:00", "2026-03-02T09:00:00Z")
+	plain, err := history.Append("plain", "queued", time.Time{})
+	require.NoError(t, err)
+
+	var cliErr *CLIError
+	err = (&RecurringDeleteCmd{ID: plain.ID}).Run(&Globals{})
+	require.True(t, asCLIError(err, &cliErr))
+	assert.Equal(t, "not_recurring", cliErr.Code)
+
+	err = (&RecurringDeleteCmd{ID: "missing"}).Run(&Globals{})
+	require.True(t, asCLIError(err, &cliErr))
+	assert.Equal(t, "not_found", cliErr.Code)
+
+	captureStdout(t, func() {
+		require.NoError(t, (&RecurringDeleteCmd{ID: tmpl.ID}).Run(&Globals{}))
+	})
+	templates, err := history.Recurrences()
+	require.NoError(t, err)
+	assert.Empty(t, templates)
+}
+
+func TestQueueShow_UpcomingRecurring_JSON(t *testing.T) {
+	withTempHome(t)
+	next := time.Now().Add(48 * time.Hour).UTC().Truncate(time.Minute)
+	tmpl := appendRecurring(t, "Week {{week}}", "daily 09:00", next.Format(time.RFC3339))
+	queued, err := history.Append("queued", "queued", time.Time{})
+	require.NoError(t, err)
+
+	output := captureStdout(t, func() {
+		require.NoError(t, (&QueueShowCmd{}).Run(&Globals{JSON: true}))
+	})
+
+	var resp struct {
+		Queue []jsonQueueItem `json:"queue"`
+	}
+	require.NoError(t, json.Unmarshal([]byte(output), &resp))
+	require.Len(t, resp.Queue, 2)
+	assert.Equal(t, queued.ID, resp.Queue[0].ID)
+
+	upcoming := resp.Queue[1]
+	assert.True(t, upcoming.Upcoming)
+	assert.Empty(t, upcoming.ID)
+	assert.Equal(t, tmpl.ID, upcoming.RecurrenceID)
+	_, week := next.Local().ISOWeek()
+	assert.Equal(t, "Week "+strconv.Itoa(week), upcoming.Message)
+	assert.Equal(t, 2, upcoming.Position)
+}
+
+func TestPublish_SpawnsDueRecurring(t *testing.T) {
+	withTempHome(t)
+	tmpl := appendRecurring(t, "Occurrence {{n}}", "daily 09:00", time.Now().Add(-time.Hour).UTC().Format(time.RFC3339))
+
+	var receivedBody string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		receivedBody = string(body)
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer srv.Close()
+
+	cfg := config.Config{Schedule: alwaysActiveSchedule()}
+	captureStdout(t, func() {
+		require.NoError(t, (&PublishCmd{}).publishOne(srv.URL, cfg, &Globals{JSON: true}, false))
+	})
+	assert.Contains(t, receivedBody, "Occurrence 1")
+
+	got, err := history.Get(tmpl.ID)
+	require.NoError(t, err)
+	assert.Equal(t, "recurring", got.Status)
+	assert.Equal(t, 1, got.Recurrence.Count)
+}
@@ -3,8 +3,11 @@ package main
 import (
 	"encoding/json"
 	"fmt"
+	"maps"
 	"os"
 	"path/filepath"
+	"slices"
+	"strconv"
 	"strings"
 	"time"
 
@@ -22,19 +25,27 @@ type ScheduleCmd struct {
 	Status    ScheduleStatusCmd    `cmd:"" help:"Show current schedule and queue."`
 	Install   ScheduleInstallCmd   `cmd:"" help:"Install background timer for automatic publishing."`
 	Uninstall ScheduleUninstallCmd `cmd:"" help:"Remove the background timer."`
+	Blackout  ScheduleBlackoutCmd  `cmd:"" help:"Manage blackout dates (holidays, shutdowns) when nothing publishes."`
 }
 
 // ScheduleSetCmd configures the schedule.
 // With flags: saves config directly. Without flags: interactive setup.
 type ScheduleSetCmd struct {
-	PostEvery string `help:"Minimum time between posts (e.g. 3h, 30m)." short:"p"`
-	Hours     string `help:"Active hours range (e.g. 9-17)." short:"H"`
-	Weekdays  string `help:"Active weekdays (e.g. mon-fri)." short:"w"`
+	PostEvery  string   `help:"Minimum time between posts (e.g. 3h, 30m)." short:"p"`
+	Jitter     string   `help:"Delay each post by a random amount up to this long (e.g. 20m; 0 to disable)."`
+	Hours      string   `help:"Active hours, one or more ranges (e.g. 9-17 or 09:30-12:00,14:00-17:30)." short:"H" xor:"hours"`
+	Weekdays   string   `help:"Active weekdays (e.g. mon-fri)." short:"w" xor:"weekdays"`
+	Windows    string   `help:"Active hours per weekday (e.g. \"mon-thu 09:30-12:00,14:00-17:30; fri 10:00-13:00\")." xor:"hours,weekdays"`
+	Timezone   string   `help:"IANA time zone of the active hours (e.g. Europe/Berlin), or \"local\" for this machine's." short:"z"`
+	MaxPerDay  *int     `help:"Most posts per day for this profile (0 for no limit). Caps count only this profile's posts, not posts other profiles send to the same channel."`
+	MaxPerWeek *int     `help:"Most posts per week for this profile, Monday to Sunday (0 for no limit)."`
+	TagCap     []string `help:"Cap this profile's posts with a tag, as TAG=N/day or TAG=N/week (0 for no limit), or TAG=off. Capped posts are passed over for others. Repeatable."`
 }
 
 func (cmd *ScheduleSetCmd) Run(globals *Globals) error {
 	// If flags provided, save directly.
-	if cmd.PostEvery != "" || cmd.Hours != "" || cmd.Weekdays != "" {
+	if cmd.PostEvery != "" || cmd.Jitter != "" || cmd.Hours != "" || cmd.Weekdays != "" || cmd.Windows != "" || cmd.Timezone != "" ||
+		cmd.MaxPerDay != nil || cmd.MaxPerWeek != nil || len(cmd.TagCap) > 0 {
 		return cmd.saveFromFlags(globals)
 	}
 
@@ -62,28 +73,152 @@ func (cmd *ScheduleSetCmd) saveFromFlags(globals *Globals) error {
 		sched.PostEveryMinutes = int(dur.Minutes())
 	}
 
-	if cmd.Hours != "" {
-		start, end, err := schedule.ParseHours(cmd.Hours)
+	if cmd.Jitter != "" {
+		dur, err := time.ParseDuration(cmd.Jitter)
 		if err != nil {
-			return newCLIError(ExitInvalidInput, "invalid_hours",
-				fmt.Sprintf("Invalid --hours value: %s", err))
+			return newCLIError(ExitInvalidInput, "invalid_jitter",
+				fmt.Sprintf("Invalid --jitter value %q: %s", cmd.Jitter, err))
 		}
-		sched.StartHour = start
-		sched.EndHour = end
+		if dur < 0 {
+			return newCLIError(ExitInvalidInput, "invalid_jitter",
+				"The --jitter value must not be negative.")
+		}
+		sched.JitterMinutes = int(dur.Minutes())
+	}
+
+	if cmd.MaxPerDay != nil {
+		if *cmd.MaxPerDay < 0 {
+			return newCLIError(ExitInvalidInput, "invalid_cap",
+				"The --max-per-day value must not be negative.")
+		}
+		sched.MaxPerDay = *cmd.MaxPerDay
+	}
+	if cmd.MaxPerWeek != nil {
+		if *cmd.MaxPerWeek < 0 {
+			return newCLIError(ExitInvalidInput, "invalid_cap",
+				"The --max-per-week value must not be negative.")
+		}
+		sched.MaxPerWeek = *cmd.MaxPerWeek
+	}
+	for _, spec := range cmd.TagCap {
+		tagCaps, err := applyTagCap(sched.TagCaps, spec)
+		if err != nil {
+			return newCLIError(ExitInvalidInput, "invalid_tag_cap",
+				fmt.Sprintf("Invalid --tag-cap value: %s", err))
+		}
+		sched.TagCaps = tagCaps
 	}
 
-	if cmd.Weekdays != "" {
-		days, err := schedule.ParseWeekdays(cmd.Weekdays)
+	if cmd.Timezone != "" {
+		tz, err := parseTimezone(cmd.Timezone)
 		if err != nil {
-			return newCLIError(ExitInvalidInput, "invalid_weekdays",
-				fmt.Sprintf("Invalid --weekdays value: %s", err))
+			return newCLIError(ExitInvalidInput, "invalid_timezone",
+				fmt.Sprintf("Invalid --timezone value: %s", err))
 		}
-		sched.Weekdays = days
+		sched.Timezone = tz
+	}
+
+	if cmd.Windows != "" {
+		windows, err := schedule.ParseWindows(cmd.Windows)
+		if err != nil {
+			return newCLIError(ExitInvalidInput, "invalid_windows",
+				fmt.Sprintf("Invalid --windows value: %s", err))
+		}
+		sched.Windows = windows
+	}
+
+	// --hours and --weekdays set the same hours on every active day. Each
+	// keeps the other's current value when given alone.
+	if cmd.Hours != "" || cmd.Weekdays != "" {
+		days := sched.ActiveDays()
+		if cmd.Weekdays != "" {
+			parsed, err := schedule.ParseWeekdays(cmd.Weekdays)
+			if err != nil {
+				return newCLIError(ExitInvalidInput, "invalid_weekdays",
+					fmt.Sprintf("Invalid --weekdays value: %s", err))
+			}
+			days = parsed
+		}
+		hours := defaultWindows(sched)
+		if cmd.Hours != "" {
+			parsed, err := schedule.ParseRanges(cmd.Hours)
+			if err != nil {
+				return newCLIError(ExitInvalidInput, "invalid_hours",
+					fmt.Sprintf("Invalid --hours value: %s", err))
+			}
+			hours = parsed
+		}
+		sched.Windows = schedule.SameWindows(days, hours...)
 	}
 
 	return saveSchedule(globals, sched)
 }
 
+// parseTimezone validates an IANA zone name, returning "" for "local".
+func parseTimezone(s string) (string, error) {
+	s = strings.TrimSpace(s)
+	if s == "" || strings.EqualFold(s, "local") {
+		return "", nil
+	}
+	if _, err := schedule.LoadLocation(s); err != nil {
+		return "", err
+	}
+	return s, nil
+}
+
+// applyTagCap returns tagCaps updated by spec: "TAG=N/day" or "TAG=N/week"
+// sets one of the tag's caps (0 removes it), "TAG=off" removes both.
+func applyTagCap(tagCaps map[string]schedule.Caps, spec string) (map[string]schedule.Caps, error) {
+	tag, value, ok := strings.Cut(spec, "=")
+	tag = strings.ToLower(strings.TrimSpace(tag))
+	if !ok || tag == "" {
+		return nil, fmt.Errorf("%q, expected TAG=N/day, TAG=N/week, or TAG=off", spec)
+	}
+	tagCaps = maps.Clone(tagCaps)
+	if tagCaps == nil {
+		tagCaps = make(map[string]schedule.Caps)
+	}
+
+	value = strings.TrimSpace(value)
+	if strings.EqualFold(value, "off") {
+		delete(tagCaps, tag)
+		return tagCaps, nil
+	}
+	countStr, period, ok := strings.Cut(value, "/")
+	n, err := strconv.Atoi(countStr)
+	if !ok || err != nil || n < 0 {
+		return nil, fmt.Errorf("%q, expected TAG=N/day, TAG=N/week, or TAG=off", spec)
+	}
+	c := tagCaps[tag]
+	switch strings.ToLower(period) {
+	case "day":
+		c.MaxPerDay = n
+	case "week":
+		c.MaxPerWeek = n
+	default:
+		return nil, fmt.Errorf("%q: period must be day or week", spec)
+	}
+	if c.IsZero() {
+		delete(tagCaps, tag)
+	} else {
+		tagCaps[tag] = c
+	}
+	return tagCaps, nil
+}
+
+// defaultWindows returns the windows to apply to newly selected weekdays:
+// the windows every active day shares, else the first active day's, else
+// the default schedule's.
+func defaultWindows(sched schedule.Schedule) []schedule.Window {
+	if windows, ok := sched.UniformWindows(); ok {
+		return windows
+	}
+	if days := sched.ActiveDays(); len(days) > 0 {
+		return sched.Windows[days[0]]
+	}
+	return schedule.DefaultSchedule().Windows["mon"]
+}
+
 func (cmd *ScheduleSetCmd) interactive(globals *Globals) error {
 	// Start from existing config or defaults.
 	sched := schedule.DefaultSchedule()
@@ -105,27 +240,88 @@ func (cmd *ScheduleSetCmd) interactive(globals *Globals) error {
 		return err
 	}
 
+	// Time zone: free-form, since there are hundreds of IANA names.
+	timezone := sched.Timezone
+	if err := runField(
+		huh.NewInput().
+			Title("Time zone of the active hours:").
+			Placeholder("local").
+			Description(fmt.Sprintf("IANA name such as Europe/Berlin; empty for this machine's (%s).", time.Local)).
+			Value(&timezone).
+			Validate(func(v string) error {
+				_, err := parseTimezone(v)
+				return err
+			}),
+	); err != nil {
+		return err
+	}
+	tz, err := parseTimezone(timezone)
+	if err != nil {
+		return newCLIError(ExitInvalidInput, "invalid_timezone",
+			fmt.Sprintf("Invalid time zone: %s", err))
+	}
+	sched.Timezone = tz
+
+	// Different hours per day: a single free-form field in the --windows
+	// syntax, instead of the hours and weekdays questions below.
+	_, uniform := sched.UniformWindows()
+	perDay := !uniform && len(sched.ActiveDays()) > 0
+	if err := runField(
+		huh.NewConfirm().
+			Title("Different hours on some days?").
+			Value(&perDay),
+	); err != nil {
+		return err
+	}
+	if perDay {
+		spec := schedule.FormatWindows(sched.Windows)
+		if err := runField(
+			huh.NewInput().
+				Title("Active hours per weekday:").
+				Placeholder("mon-thu 09:30-12:00,14:00-17:30; fri 10:00-13:00").
+				Description("Groups of DAYS RANGES separated by semicolons. Days not listed are inactive.").
+				Value(&spec).
+				Validate(func(v string) error {
+					_, err := schedule.ParseWindows(v)
+					return err
+				}),
+		); err != nil {
+			return err
+		}
+		windows, err := schedule.ParseWindows(spec)
+		if err != nil {
+			return newCLIError(ExitInvalidInput, "invalid_windows",
+				fmt.Sprintf("Invalid windows value: %s", err))
+		}
+		sched.PostEveryMinutes = frequency
+		sched.Windows = windows
+		return saveSchedule(globals, sched)
+	}
+
 	// Hours: Input fields (avoids huh Select viewport scroll bug with 24 items).
-	hours := fmt.Sprintf("%d-%d", sched.StartHour, sched.EndHour)
-	err := runField(
+	hours := schedule.FormatRanges(defaultWindows(sched))
+	err = runField(
 		huh.NewInput().
-			Title("Active hours range:").
+			Title("Active hours:").
 			Placeholder("9-17").
-			Description(fmt.Sprintf("24-hour format START-END. Currently: %s–%s.",
-				formatHourLabel(sched.StartHour), formatHourLabel(sched.EndHour))).
-			Value(&hours),
+			Description("24-hour format START-END, e.g. 9-17 or 09:30-12:00,14:00-17:30.").
+			Value(&hours).
+			Validate(func(v string) error {
+				_, err := schedule.ParseRanges(v)
+				return err
+			}),
 	)
 	if err != nil {
 		return err
 	}
-	start, end, parseErr := schedule.ParseHours(hours)
+	ranges, parseErr := schedule.ParseRanges(hours)
 	if parseErr != nil {
 		return newCLIError(ExitInvalidInput, "invalid_hours",
 			fmt.Sprintf("Invalid hours value: %s", parseErr))
 	}
 
 	// Weekdays: MultiSelect (7 items, all visible).
-	weekdays := sched.Weekdays
+	weekdays := sched.ActiveDays()
 	weekdayOptions := []huh.Option[string]{
 		huh.NewOption("Monday", "mon"),
 		huh.NewOption("Tuesday", "tue"),
@@ -153,29 +349,18 @@ func (cmd *ScheduleSetCmd) interactive(globals *Globals) error {
 	}
 
 	sched.PostEveryMinutes = frequency
-	sched.StartHour = start
-	sched.EndHour = end
-	sched.Weekdays = weekdays
+	sched.Windows = schedule.SameWindows(weekdays, ranges...)
 
 	return saveSchedule(globals, sched)
 }
 
-// formatHourLabel converts a 24-hour int to a human-readable label.
-func formatHourLabel(h int) string {
-	switch {
-	case h == 0:
-		return "12am (midnight)"
-	case h < 12:
-		return fmt.Sprintf("%dam", h)
-	case h == 12:
-		return "12pm (noon)"
-	default:
-		return fmt.Sprintf("%dpm", h-12)
-	}
-}
-
 func saveSchedule(globals *Globals, sched schedule.Schedule) error {
-	cfg := config.Config{Schedule: sched}
+	// Preserve non-schedule settings.
+	cfg, err := config.Load()
+	if err != nil {
+		cfg = config.Default()
+	}
+	cfg.Schedule = sched
 	if err := config.Save(cfg); err != nil {
 		return fmt.Errorf("save config: %w", err)
 	}
@@ -220,9 +405,13 @@ func (cmd *ScheduleStatusCmd) Run(globals *Globals) error { //nolint:unparam //
 			"status": "configured",
 			"schedule": map[string]any{
 				"post_every_minutes": cfg.Schedule.PostEveryMinutes,
-				"start_hour":         cfg.Schedule.StartHour,
-				"end_hour":           cfg.Schedule.EndHour,
-				"weekdays":           cfg.Schedule.Weekdays,
+				"jitter_minutes":     cfg.Schedule.JitterMinutes,
+				"windows":            cfg.Schedule.Windows,
+				"timezone":           cfg.Schedule.Location().String(),
+				"blackouts":          len(cfg.Schedule.Blackouts),
+				"max_per_day":        cfg.Schedule.MaxPerDay,
+				"max_per_week":       cfg.Schedule.MaxPerWeek,
+				"tag_caps":           cfg.Schedule.TagCaps,
 			},
 			"queued_count":    len(queued),
 			"timer_installed": launchd.IsInstalled(),
@@ -230,6 +419,12 @@ func (cmd *ScheduleStatusCmd) Run(globals *Globals) error { //nolint:unparam //
 			"plist_path":      launchd.PlistPath(),
 			"log_path":        launchd.LogPath(),
 		}
+		if paused, until := cfg.Schedule.PausedAt(time.Now()); paused {
+			resp["paused"] = true
+			if !until.IsZero() {
+				resp["paused_until"] = until.UTC().Format(time.RFC3339)
+			}
+		}
 		if !lastPublished.IsZero() {
 			resp["last_published"] = lastPublished.UTC().Format(time.RFC3339)
 		}
@@ -237,11 +432,17 @@ func (cmd *ScheduleStatusCmd) Run(globals *Globals) error { //nolint:unparam //
 		fmt.Fprintln(os.Stdout, string(b))
 	} else {
 		fmt.Fprintf(os.Stdout, "Schedule: %s\n", formatScheduleSummary(cfg.Schedule))
+		if pause := formatPause(cfg.Schedule, time.Now()); pause != "" {
+			fmt.Fprintln(os.Stdout, pause)
+		}
+		if next, ok := nextBlackout(cfg.Schedule, time.Now()); ok {
+			fmt.Fprintf(os.Stdout, "Next blackout: %s\n", next)
+		}
 		fmt.Fprintf(os.Stdout, "Queued messages: %d\n", len(queued))
 		if !lastPublished.IsZero() {
 			ago := time.Since(lastPublished).Truncate(time.Minute)
 			fmt.Fprintf(os.Stdout, "Last published: %s (%s ago)\n",
-				lastPublished.Local().Format("2006-01-02 15:04"), ago)
+				lastPublished.In(cfg.Schedule.Location()).Format("2006-01-02 15:04"), ago)
 		} else {
 			fmt.Fprintln(os.Stdout, "Last published: never")
 		}
@@ -281,7 +482,7 @@ func (cmd *ScheduleInstallCmd) Run(globals *Globals) error {
 
 	// Save default schedule if no config exists.
 	if !config.Exists() {
-		cfg := config.Config{Schedule: schedule.DefaultSchedule()}
+		cfg := config.Default()
 		if err := config.Save(cfg); err != nil {
 			return fmt.Errorf("save default config: %w", err)
 		}
@@ -334,17 +535,102 @@ func (cmd *ScheduleUninstallCmd) Run(globals *Globals) error {
 
 // formatScheduleSummary returns a human-readable schedule description.
 func formatScheduleSummary(s schedule.Schedule) string {
-	days := formatWeekdays(s.Weekdays)
-	hours := fmt.Sprintf("%02d:00–%02d:00", s.StartHour, s.EndHour)
-
-	summary := fmt.Sprintf("Publishing: %s %s", days, hours)
+	summary := "Publishing: " + formatActiveHours(s)
 	if s.PostEveryMinutes > 0 {
 		dur := time.Duration(s.PostEveryMinutes) * time.Minute
 		summary += fmt.Sprintf(", max every %s", dur)
 	}
+	if s.JitterMinutes > 0 {
+		summary += fmt.Sprintf(", jitter up to %s", s.Jitter())
+	}
+	if !s.Caps.IsZero() {
+		summary += ", max " + formatCaps(s.Caps)
+	}
+	tags := slices.Sorted(maps.Keys(s.TagCaps))
+	for _, tag := range tags {
+		summary += fmt.Sprintf(", max %s tagged %s", formatCaps(s.TagCaps[tag]), tag)
+	}
 	return summary
 }
 
+// formatCaps describes caps, e.g. "3/day, 10/week".
+func formatCaps(c schedule.Caps) string {
+	var parts []string
+	if c.MaxPerDay > 0 {
+		parts = append(parts, fmt.Sprintf("%d/day", c.MaxPerDay))
+	}
+	if c.MaxPerWeek > 0 {
+		parts = append(parts, fmt.Sprintf("%d/week", c.MaxPerWeek))
+	}
+	return strings.Join(parts, ", ")
+}
+
+// formatCapHit describes a reached cap, e.g. "weekly cap of 5 posts tagged
+// release".
+func formatCapHit(hit schedule.CapHit) string {
+	period := "daily"
+	if hit.Period == "week" {
+		period = "weekly"
+	}
+	s := fmt.Sprintf("%s cap of %d posts", period, hit.Limit)
+	if hit.Tag != "" {
+		s += " tagged " + hit.Tag
+	}
+	return s
+}
+
+// nextBlackout returns the blackout in effect at now or, failing that, the
+// next one to start.
+func nextBlackout(s schedule.Schedule, now time.Time) (schedule.Blackout, bool) {
+	today := now.In(s.Location()).Format(time.DateOnly)
+	var next schedule.Blackout
+	found := false
+	for _, b := range s.Blackouts {
+		if b.To >= today && (!found || b.From < next.From) {
+			next, found = b, true
+		}
+	}
+	return next, found
+}
+
+// formatActiveHours describes when the schedule is active, e.g.
+// "Mon–Fri 09:00–17:00" or "Mon–Thu 09:30–12:00, 14:00–17:30; Fri 10:00–13:00".
+func formatActiveHours(s schedule.Schedule) string {
+	days := s.ActiveDays()
+	if len(days) == 0 {
+		return "no active hours"
+	}
+	zone := ""
+	if s.Timezone != "" {
+		zone = " " + s.Timezone
+	}
+	if windows, ok := s.UniformWindows(); ok {
+		return formatWeekdays(days) + " " + formatWindowList(windows) + zone
+	}
+
+	// Group consecutive days that share the same windows.
+	var groups []string
+	for i := 0; i < len(days); {
+		j := i + 1
+		for j < len(days) && slices.Equal(s.Windows[days[j]], s.Windows[days[i]]) &&
+			isConsecutiveWeekdays(days[j-1:j+1]) {
+			j++
+		}
+		groups = append(groups, formatWeekdays(days[i:j])+" "+formatWindowList(s.Windows[days[i]]))
+		i = j
+	}
+	return strings.Join(groups, "; ") + zone
+}
+
+// formatWindowList renders windows for display, e.g. "09:30–12:00, 14:00–17:30".
+func formatWindowList(windows []schedule.Window) string {
+	parts := make([]string, len(windows))
+	for i, w := range windows {
+		parts[i] = w.Start.String() + "–" + w.End.String()
+	}
+	return strings.Join(parts, ", ")
+}
+
 // formatWeekdays converts a list of day abbreviations to a human-readable string.
 // Consecutive days use range notation ("Mon–Fri"); non-consecutive days are listed ("Mon, Wed, Fri").
 func formatWeekdays(days []string) string {
@@ -0,0 +1,201 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"time"
+
+	"github.com/lvrach/slack-social-ai/internal/config"
+	"github.com/lvrach/slack-social-ai/internal/schedule"
+)
+
+// ScheduleBlackoutCmd manages days on which nothing publishes.
+type ScheduleBlackoutCmd struct {
+	List   ScheduleBlackoutListCmd   `cmd:"" default:"withargs" help:"List blackout dates."`
+	Add    ScheduleBlackoutAddCmd    `cmd:"" help:"Add a blackout date or date range."`
+	Remove ScheduleBlackoutRemoveCmd `cmd:"" help:"Remove the blackouts covering a date."`
+	Import ScheduleBlackoutImportCmd `cmd:"" help:"Import the events of an .ics calendar (e.g. company holidays) as blackouts."`
+}
+
+// ScheduleBlackoutListCmd lists blackouts.
+type ScheduleBlackoutListCmd struct {
+	All bool `help:"Include blackouts that have ended." short:"a"`
+}
+
+func (cmd *ScheduleBlackoutListCmd) Run(globals *Globals) error {
+	cfg, err := config.Load()
+	if err != nil {
+		return newCLIError(ExitRuntimeError, "config_error",
+			fmt.Sprintf("Failed to load config: %s", err))
+	}
+
+	today := time.Now().In(cfg.Schedule.Location()).Format(time.DateOnly)
+	blackouts := slices.DeleteFunc(slices.Clone(cfg.Schedule.Blackouts), func(b schedule.Blackout) bool {
+		return !cmd.All && b.To < today
+	})
+	schedule.SortBlackouts(blackouts)
+
+	if globals.JSON {
+		if blackouts == nil {
+			blackouts = []schedule.Blackout{}
+		}
+		b, _ := json.Marshal(map[string]any{"blackouts": blackouts, "count": len(blackouts)})
+		fmt.Fprintln(os.Stdout, string(b))
+		return nil
+	}
+
+	if len(blackouts) == 0 {
+		fmt.Fprintln(os.Stdout, "No blackout dates. Add one with `slack-social-ai schedule blackout add 2026-12-24 2026-12-26`.")
+		return nil
+	}
+	fmt.Fprintf(os.Stdout, " %-10s  %-10s  %s\n", "From", "To", "Name")
+	for _, b := range blackouts {
+		name := b.Name
+		if b.Source != "" {
+			name += " (" + filepath.Base(b.Source) + ")"
+		}
+		fmt.Fprintf(os.Stdout, " %-10s  %-10s  %s\n", b.From, b.To, name)
+	}
+	return nil
+}
+
+// ScheduleBlackoutAddCmd adds a blackout.
+type ScheduleBlackoutAddCmd struct {
+	From string `arg:"" help:"First day (YYYY-MM-DD)."`
+	To   string `arg:"" optional:"" help:"Last day, inclusive (YYYY-MM-DD). Default: the first day."`
+	Name string `help:"What the blackout is for (e.g. \"Christmas\")." short:"n"`
+}
+
+func (cmd *ScheduleBlackoutAddCmd) Run(globals *Globals) error {
+	b, err := schedule.NewBlackout(cmd.From, cmd.To, cmd.Name)
+	if err != nil {
+		return newCLIError(ExitInvalidInput, "invalid_date", fmt.Sprintf("Invalid blackout: %s", err))
+	}
+
+	if err := updateBlackouts(func(blackouts []schedule.Blackout) []schedule.Blackout {
+		return append(blackouts, b)
+	}); err != nil {
+		return err
+	}
+
+	msg := fmt.Sprintf("Added blackout %s.", b)
+	if globals.JSON {
+		printSuccessJSON(msg)
+	} else {
+		printSuccessHuman(msg)
+	}
+	return nil
+}
+
+// ScheduleBlackoutRemoveCmd removes the blackouts covering a date.
+type ScheduleBlackoutRemoveCmd struct {
+	Date string `arg:"" help:"A day (YYYY-MM-DD) within the blackouts to remove."`
+}
+
+func (cmd *ScheduleBlackoutRemoveCmd) Run(globals *Globals) error {
+	if _, err := time.Parse(time.DateOnly, cmd.Date); err != nil {
+		return newCLIError(ExitInvalidInput, "invalid_date",
+			fmt.Sprintf("Invalid date %q, expected YYYY-MM-DD.", cmd.Date))
+	}
+
+	removed := 0
+	if err := updateBlackouts(func(blackouts []schedule.Blackout) []schedule.Blackout {
+		kept := slices.DeleteFunc(blackouts, func(b schedule.Blackout) bool { return b.Covers(cmd.Date) })
+		removed = len(blackouts) - len(kept)
+		return kept
+	}); err != nil {
+		return err
+	}
+	if removed == 0 {
+		return newCLIError(ExitInvalidInput, "not_found",
+			fmt.Sprintf("No blackout covers %s.", cmd.Date))
+	}
+
+	msg := fmt.Sprintf("Removed %d blackout(s) covering %s.", removed, cmd.Date)
+	if globals.JSON {
+		printSuccessJSON(msg)
+	} else {
+		printSuccessHuman(msg)
+	}
+	return nil
+}
+
+// ScheduleBlackoutImportCmd imports an .ics calendar as blackouts.
+type ScheduleBlackoutImportCmd struct {
+	File string `arg:"" help:"iCalendar (.ics) file, e.g. a holiday calendar exported to disk."`
+}
+
+func (cmd *ScheduleBlackoutImportCmd) Run(globals *Globals) error {
+	source, err := filepath.Abs(cmd.File)
+	if err != nil {
+		return newCLIError(ExitInvalidInput, "invalid_file", fmt.Sprintf("Invalid path %q: %s", cmd.File, err))
+	}
+	f, err := os.Open(source)
+	if err != nil {
+		return newCLIError(ExitInvalidInput, "invalid_file", fmt.Sprintf("Cannot read %s: %s", cmd.File, err))
+	}
+	defer f.Close()
+
+	cfg, err := config.Load()
+	if err != nil {
+		return newCLIError(ExitRuntimeError, "config_error",
+			fmt.Sprintf("Failed to load config: %s", err))
+	}
+	loc := cfg.Schedule.Location()
+	imported, warnings, err := schedule.ParseICS(f, source, loc, time.Now().In(loc))
+	if err != nil {
+		return newCLIError(ExitInvalidInput, "invalid_calendar",
+			fmt.Sprintf("Cannot parse %s: %s", cmd.File, err))
+	}
+
+	// Replace what an earlier import of the same file added.
+	replaced := 0
+	if err := updateBlackouts(func(blackouts []schedule.Blackout) []schedule.Blackout {
+		kept := slices.DeleteFunc(blackouts, func(b schedule.Blackout) bool { return b.Source == source })
+		replaced = len(blackouts) - len(kept)
+		return append(kept, imported...)
+	}); err != nil {
+		return err
+	}
+
+	if globals.JSON {
+		resp := map[string]any{
+			"status": "ok", "source": source, "imported": len(imported), "replaced": replaced,
+		}
+		if len(warnings) > 0 {
+			resp["warnings"] = warnings
+		}
+		b, _ := json.Marshal(resp)
+		fmt.Fprintln(os.Stdout, string(b))
+		return nil
+	}
+	for _, w := range warnings {
+		fmt.Fprintf(os.Stderr, "Warning: %s\n", w)
+	}
+	msg := fmt.Sprintf("Imported %d blackout(s) from %s.", len(imported), filepath.Base(source))
+	if replaced > 0 {
+		msg += fmt.Sprintf(" Replaced %d from an earlier import.", replaced)
+	}
+	printSuccessHuman(msg)
+	return nil
+}
+
+// updateBlackouts applies fn to the configured blackouts and saves them
+// in date order.
+func updateBlackouts(fn func([]schedule.Blackout) []schedule.Blackout) error {
+	cfg, err := config.Load()
+	if err != nil {
+		return newCLIError(ExitRuntimeError, "config_error",
+			fmt.Sprintf("Failed to load config: %s", err))
+	}
+	cfg.Schedule.Blackouts = fn(cfg.Schedule.Blackouts)
+	schedule.SortBlackouts(cfg.Schedule.Blackouts)
+	if err := config.Save(cfg); err != nil {
+		return newCLIError(ExitRuntimeError, "config_error",
+			fmt.Sprintf("Failed to save config: %s", err))
+	}
+	return nil
+}
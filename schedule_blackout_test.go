@@ -0,0 +1,121 @@
+package main
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/lvrach/slack-social-ai/internal/config"
+	"github.com/lvrach/slack-social-ai/internal/schedule"
+)
+
+func TestScheduleBlackout_AddListRemove(t *testing.T) {
+	withTempHome(t)
+
+	captureStdout(t, func() {
+		require.NoError(t, (&ScheduleBlackoutAddCmd{From: "2099-12-24", To: "2099-12-26", Name: "Christmas"}).Run(&Globals{JSON: true}))
+		require.NoError(t, (&ScheduleBlackoutAddCmd{From: "2099-01-01"}).Run(&Globals{JSON: true}))
+		require.NoError(t, (&ScheduleBlackoutAddCmd{From: "2000-01-01"}).Run(&Globals{JSON: true}))
+	})
+
+	output := captureStdout(t, func() {
+		require.NoError(t, (&ScheduleBlackoutListCmd{}).Run(&Globals{JSON: true}))
+	})
+	var resp struct {
+		Blackouts []schedule.Blackout `json:"blackouts"`
+		Count     int                 `json:"count"`
+	}
+	require.NoError(t, json.Unmarshal([]byte(output), &resp))
+	require.Equal(t, 2, resp.Count, "past blackouts are hidden")
+	assert.Equal(t, "2099-01-01", resp.Blackouts[0].From, "sorted by date")
+	assert.Equal(t, "Christmas", resp.Blackouts[1].Name)
+
+	output = captureStdout(t, func() {
+		require.NoError(t, (&ScheduleBlackoutListCmd{All: true}).Run(&Globals{}))
+	})
+	assert.Contains(t, output, "2000-01-01")
+	assert.Contains(t, output, "Christmas")
+
+	captureStdout(t, func() {
+		require.NoError(t, (&ScheduleBlackoutRemoveCmd{Date: "2099-12-25"}).Run(&Globals{JSON: true}))
+	})
+	cfg, err := config.Load()
+	require.NoError(t, err)
+	assert.Len(t, cfg.Schedule.Blackouts, 2)
+
+	var cliErr *CLIError
+	err = (&ScheduleBlackoutRemoveCmd{Date: "2099-12-25"}).Run(&Globals{JSON: true})
+	require.True(t, asCLIError(err, &cliErr))
+	assert.Equal(t, "not_found", cliErr.Code)
+}
+
+func TestScheduleBlackout_AddInvalid(t *testing.T) {
+	withTempHome(t)
+
+	var cliErr *CLIError
+	err := (&ScheduleBlackoutAddCmd{From: "2099-12-26", To: "2099-12-24"}).Run(&Globals{})
+	require.True(t, asCLIError(err, &cliErr))
+	assert.Equal(t, "invalid_date", cliErr.Code)
+}
+
+func TestScheduleBlackout_Import(t *testing.T) {
+	withTempHome(t)
+	home := t.TempDir()
+
+	path := filepath.Join(home, "holidays.ics")
+	writeICS := func(events ...string) {
+		ics := "BEGIN:VCALENDAR\n"
+		for _, day := range events {
+			ics += "BEGIN:VEVENT\nDTSTART;VALUE=DATE:" + day + "\nSUMMARY:Holiday\nEND:VEVENT\n"
+		}
+		require.NoError(t, os.WriteFile(path, []byte(ics+"END:VCALENDAR\n"), 0o600))
+	}
+
+	captureStdout(t, func() {
+		require.NoError(t, (&ScheduleBlackoutAddCmd{From: "2099-06-01", Name: "Offsite"}).Run(&Globals{JSON: true}))
+	})
+	writeICS("20991224", "20991225")
+	captureStdout(t, func() {
+		require.NoError(t, (&ScheduleBlackoutImportCmd{File: path}).Run(&Globals{JSON: true}))
+	})
+
+	// Re-importing replaces the earlier import but keeps manual blackouts.
+	writeICS("20991231")
+	output := captureStdout(t, func() {
+		require.NoError(t, (&ScheduleBlackoutImportCmd{File: path}).Run(&Globals{JSON: true}))
+	})
+	var resp map[string]any
+	require.NoError(t, json.Unmarshal([]byte(output), &resp))
+	assert.InDelta(t, 1, resp["imported"], 0)
+	assert.InDelta(t, 2, resp["replaced"], 0)
+
+	cfg, err := config.Load()
+	require.NoError(t, err)
+	var days []string
+	for _, b := range cfg.Schedule.Blackouts {
+		days = append(days, b.From)
+	}
+	assert.Equal(t, []string{"2099-06-01", "2099-12-31"}, days)
+
+	var cliErr *CLIError
+	err = (&ScheduleBlackoutImportCmd{File: filepath.Join(home, "missing.ics")}).Run(&Globals{})
+	require.True(t, asCLIError(err, &cliErr))
+	assert.Equal(t, "invalid_file", cliErr.Code)
+}
+
+func TestPublishCmd_ExitOutsideSchedule_Blackout(t *testing.T) {
+	sched := alwaysActiveSchedule()
+	today := time.Now().Format(time.DateOnly)
+	sched.Blackouts = []schedule.Blackout{{From: today, To: today, Name: "Founders' Day"}}
+
+	output := captureStdout(t, func() {
+		require.NoError(t, (&PublishCmd{}).exitOutsideSchedule(&Globals{}, sched))
+	})
+	assert.Contains(t, output, "Skipped: blackout")
+	assert.Contains(t, output, "Founders' Day")
+}
@@ -5,6 +5,9 @@ import (
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+
+	"github.com/lvrach/slack-social-ai/internal/config"
+	"github.com/lvrach/slack-social-ai/internal/schedule"
 )
 
 func TestBuildFreqOptions_PresetCurrentFirst(t *testing.T) {
@@ -54,28 +57,136 @@ func TestBuildFreqOptions_CustomValue(t *testing.T) {
 	assert.True(t, values[0])
 }
 
-func TestFormatHourLabel(t *testing.T) {
+func TestFormatActiveHours(t *testing.T) {
+	windows, err := schedule.ParseWindows("mon-thu 09:30-12:00,14:00-17:30; fri 10:00-13:00; sun 10:00-13:00")
+	require.NoError(t, err)
+
 	tests := []struct {
-		hour     int
+		name     string
+		sched    schedule.Schedule
 		expected string
 	}{
-		{0, "12am (midnight)"},
-		{1, "1am"},
-		{9, "9am"},
-		{11, "11am"},
-		{12, "12pm (noon)"},
-		{13, "1pm"},
-		{17, "5pm"},
-		{23, "11pm"},
+		{"default", schedule.DefaultSchedule(), "Mon–Fri 09:00–17:00"},
+		{"none", schedule.Schedule{}, "no active hours"},
+		{"timezone", schedule.Schedule{Windows: schedule.DefaultSchedule().Windows, Timezone: "Europe/Berlin"},
+			"Mon–Fri 09:00–17:00 Europe/Berlin"},
+		{"per day", schedule.Schedule{Windows: windows},
+			"Mon–Thu 09:30–12:00, 14:00–17:30; Fri 10:00–13:00; Sun 10:00–13:00"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, formatActiveHours(tt.sched))
+		})
 	}
+}
 
+func TestScheduleSet_Flags(t *testing.T) {
+	tests := []struct {
+		name     string
+		cmd      ScheduleSetCmd
+		expected string
+	}{
+		{"hours keep weekdays", ScheduleSetCmd{Hours: "09:30-12:00,14:00-17:30"}, "mon-fri 09:30-12:00,14:00-17:30"},
+		{"weekdays keep hours", ScheduleSetCmd{Weekdays: "sat-sun"}, "sat-sun 09:00-17:00"},
+		{"both", ScheduleSetCmd{Hours: "8-12", Weekdays: "mon,wed"}, "mon 08:00-12:00; wed 08:00-12:00"},
+		{"windows", ScheduleSetCmd{Windows: "mon-thu 9-17; fri 10:00-13:00"}, "mon-thu 09:00-17:00; fri 10:00-13:00"},
+		{"timezone keeps windows", ScheduleSetCmd{Timezone: "Europe/Berlin"}, "mon-fri 09:00-17:00"},
+	}
 	for _, tt := range tests {
-		t.Run(tt.expected, func(t *testing.T) {
-			assert.Equal(t, tt.expected, formatHourLabel(tt.hour))
+		t.Run(tt.name, func(t *testing.T) {
+			withTempHome(t)
+			captureStdout(t, func() {
+				require.NoError(t, tt.cmd.Run(&Globals{JSON: true}))
+			})
+			cfg, err := config.Load()
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, schedule.FormatWindows(cfg.Schedule.Windows))
 		})
 	}
 }
 
+func TestScheduleSet_Timezone(t *testing.T) {
+	withTempHome(t)
+
+	captureStdout(t, func() {
+		require.NoError(t, (&ScheduleSetCmd{Timezone: "Europe/Berlin"}).Run(&Globals{JSON: true}))
+	})
+	cfg, err := config.Load()
+	require.NoError(t, err)
+	assert.Equal(t, "Europe/Berlin", cfg.Schedule.Timezone)
+
+	captureStdout(t, func() {
+		require.NoError(t, (&ScheduleSetCmd{Timezone: "local"}).Run(&Globals{JSON: true}))
+	})
+	cfg, err = config.Load()
+	require.NoError(t, err)
+	assert.Empty(t, cfg.Schedule.Timezone)
+}
+
+func TestScheduleSet_Jitter(t *testing.T) {
+	withTempHome(t)
+
+	output := captureStdout(t, func() {
+		require.NoError(t, (&ScheduleSetCmd{Jitter: "20m"}).Run(&Globals{}))
+	})
+	assert.Contains(t, output, "jitter up to 20m0s")
+	cfg, err := config.Load()
+	require.NoError(t, err)
+	assert.Equal(t, 20, cfg.Schedule.JitterMinutes)
+}
+
+func TestScheduleSet_Caps(t *testing.T) {
+	withTempHome(t)
+
+	day, week := 3, 10
+	output := captureStdout(t, func() {
+		require.NoError(t, (&ScheduleSetCmd{
+			MaxPerDay:  &day,
+			MaxPerWeek: &week,
+			TagCap:     []string{"Release=1/day", "release=2/week", "tips=1/day"},
+		}).Run(&Globals{}))
+	})
+	assert.Contains(t, output, "max 3/day, 10/week")
+	assert.Contains(t, output, "max 1/day, 2/week tagged release")
+	cfg, err := config.Load()
+	require.NoError(t, err)
+	assert.Equal(t, schedule.Caps{MaxPerDay: 3, MaxPerWeek: 10}, cfg.Schedule.Caps)
+	assert.Equal(t, map[string]schedule.Caps{
+		"release": {MaxPerDay: 1, MaxPerWeek: 2},
+		"tips":    {MaxPerDay: 1},
+	}, cfg.Schedule.TagCaps)
+
+	// 0 and off remove caps.
+	zero := 0
+	captureStdout(t, func() {
+		require.NoError(t, (&ScheduleSetCmd{MaxPerWeek: &zero, TagCap: []string{"release=off", "tips=0/day"}}).Run(&Globals{JSON: true}))
+	})
+	cfg, err = config.Load()
+	require.NoError(t, err)
+	assert.Equal(t, schedule.Caps{MaxPerDay: 3}, cfg.Schedule.Caps)
+	assert.Empty(t, cfg.Schedule.TagCaps)
+}
+
+func TestScheduleSet_InvalidWindows(t *testing.T) {
+	withTempHome(t)
+
+	negative := -1
+	for code, cmd := range map[string]ScheduleSetCmd{
+		"invalid_hours":    {Hours: "9-12,11-14"},
+		"invalid_weekdays": {Weekdays: "mon-funday"},
+		"invalid_windows":  {Windows: "mon 25:00-26:00"},
+		"invalid_timezone": {Timezone: "Mars/Olympus"},
+		"invalid_jitter":   {Jitter: "-5m"},
+		"invalid_cap":      {MaxPerDay: &negative},
+		"invalid_tag_cap":  {TagCap: []string{"release=2/month"}},
+	} {
+		err := cmd.Run(&Globals{JSON: true})
+		var cliErr *CLIError
+		require.True(t, asCLIError(err, &cliErr), code)
+		assert.Equal(t, code, cliErr.Code)
+	}
+}
+
 func TestFormatWeekdays(t *testing.T) {
 	tests := []struct {
 		name     string
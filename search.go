@@ -0,0 +1,53 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/lvrach/slack-social-ai/internal/history"
+)
+
+// SearchCmd searches post history by text and tag.
+type SearchCmd struct {
+	Query  string   `arg:"" optional:"" help:"Case-insensitive text to search for in messages."`
+	Tag    []string `help:"Match only entries with this tag (repeatable)." short:"t"`
+	Status string   `help:"Match only entries with this status (queued, published, ...)." short:"s"`
+}
+
+func (cmd *SearchCmd) Run(globals *Globals) error {
+	if cmd.Query == "" && len(cmd.Tag) == 0 && cmd.Status == "" {
+		return newCLIError(ExitInvalidInput, "empty_query",
+			"Nothing to search for. Pass a query, --tag, or --status.")
+	}
+
+	entries, err := history.Load()
+	if err != nil {
+		return fmt.Errorf("load history: %w", err)
+	}
+
+	matches := searchEntries(entries, cmd.Query, normalizeTags(cmd.Tag), cmd.Status)
+
+	// Most recent first, like history.
+	for i, j := 0, len(matches)-1; i < j; i, j = i+1, j-1 {
+		matches[i], matches[j] = matches[j], matches[i]
+	}
+
+	return printEntries(globals, matches)
+}
+
+// searchEntries filters entries by message substring, tags, and status.
+// Empty criteria match everything.
+func searchEntries(entries []history.Entry, query string, tags []string, status string) []history.Entry {
+	query = strings.ToLower(query)
+	var result []history.Entry
+	for _, e := range history.FilterByTags(entries, tags) {
+		if status != "" && e.Status != status {
+			continue
+		}
+		if query != "" && !strings.Contains(strings.ToLower(e.Message), query) {
+			continue
+		}
+		result = append(result, e)
+	}
+	return result
+}
@@ -0,0 +1,41 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/lvrach/slack-social-ai/internal/history"
+)
+
+func TestSearchEntries(t *testing.T) {
+	entries := []history.Entry{
+		{ID: "a1", Message: "r/go\nGoroutine leaks", Status: "published", Tags: []string{"go"}},
+		{ID: "a2", Message: "r/ai\nPrompt design", Status: "queued", Tags: []string{"ai"}},
+		{ID: "a3", Message: "r/go\nChannel tricks", Status: "queued", Tags: []string{"go"}},
+	}
+
+	t.Run("text query is case-insensitive", func(t *testing.T) {
+		got := searchEntries(entries, "GOROUTINE", nil, "")
+		require.Len(t, got, 1)
+		assert.Equal(t, "a1", got[0].ID)
+	})
+
+	t.Run("tag filter", func(t *testing.T) {
+		got := searchEntries(entries, "", []string{"go"}, "")
+		require.Len(t, got, 2)
+		assert.Equal(t, "a1", got[0].ID)
+		assert.Equal(t, "a3", got[1].ID)
+	})
+
+	t.Run("tag and status", func(t *testing.T) {
+		got := searchEntries(entries, "", []string{"go"}, "queued")
+		require.Len(t, got, 1)
+		assert.Equal(t, "a3", got[0].ID)
+	})
+
+	t.Run("no match", func(t *testing.T) {
+		assert.Empty(t, searchEntries(entries, "rust", nil, ""))
+	})
+}
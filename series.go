@@ -0,0 +1,240 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/lvrach/slack-social-ai/internal/history"
+	"github.com/lvrach/slack-social-ai/internal/slack"
+)
+
+// SeriesOptions are the series settings shared by `post --series` and
+// `series set`. They apply to every unpublished part of the series.
+type SeriesOptions struct {
+	Gap    string `help:"Minimum time between parts of the series (e.g. 30m, 2h)."`
+	Strict bool   `help:"Publish no other posts between parts of the series." xor:"strict"`
+	Thread string `help:"Post the parts as replies to this Slack message (timestamp or permalink)." xor:"thread"`
+}
+
+// isSet reports whether any option was given.
+func (o SeriesOptions) isSet() bool {
+	return o.Gap != "" || o.Strict || o.Thread != ""
+}
+
+// update validates the options and returns a function applying them to a
+// series part. loose and noThread clear Strict and ThreadTS.
+func (o SeriesOptions) update(loose, noThread bool) (func(s *history.SeriesPart), error) {
+	var gap time.Duration
+	if o.Gap != "" {
+		d, err := time.ParseDuration(o.Gap)
+		if err != nil || d < 0 {
+			return nil, newCLIError(ExitInvalidInput, "invalid_gap",
+				fmt.Sprintf("Cannot parse gap %q. Use a duration like 30m or 2h.", o.Gap))
+		}
+		gap = d
+	}
+	var threadTS string
+	if o.Thread != "" {
+		ts, err := slack.ParseThreadTS(o.Thread)
+		if err != nil {
+			return nil, newCLIError(ExitInvalidInput, "invalid_thread", err.Error())
+		}
+		threadTS = ts
+	}
+	return func(s *history.SeriesPart) {
+		if o.Gap != "" {
+			s.Gap = gap.String()
+		}
+		switch {
+		case o.Strict:
+			s.Strict = true
+		case loose:
+			s.Strict = false
+		}
+		switch {
+		case threadTS != "":
+			s.ThreadTS = threadTS
+		case noThread:
+			s.ThreadTS = ""
+		}
+	}, nil
+}
+
+// SeriesCmd manages post series created with `post --series`.
+type SeriesCmd struct {
+	List   SeriesListCmd   `cmd:"" default:"withargs" help:"List series, or the parts of one series."`
+	Set    SeriesSetCmd    `cmd:"" help:"Change the gap, strictness, or thread of a series."`
+	Remove SeriesRemoveCmd `cmd:"" help:"Remove the unpublished parts of a series."`
+}
+
+// SeriesListCmd lists series.
+type SeriesListCmd struct {
+	Name string `arg:"" optional:"" help:"Show the parts of this series."`
+}
+
+// jsonSeries is the JSON shape of a series.
+type jsonSeries struct {
+	Name      string           `json:"name"`
+	Parts     int              `json:"parts"`
+	Published int              `json:"published"`
+	Pending   int              `json:"pending"`
+	Gap       string           `json:"gap,omitempty"`
+	Strict    bool             `json:"strict"`
+	ThreadTS  string           `json:"thread_ts,omitempty"`
+	Entries   []jsonSeriesPart `json:"entries,omitempty"`
+}
+
+// jsonSeriesPart is the JSON shape of one part of a series.
+type jsonSeriesPart struct {
+	Part        int    `json:"part"`
+	ID          string `json:"id"`
+	Status      string `json:"status"`
+	Message     string `json:"message"`
+	PublishedAt string `json:"published_at,omitempty"`
+}
+
+func (cmd *SeriesListCmd) Run(globals *Globals) error {
+	all, err := history.Series()
+	if err != nil {
+		return historyFailure(err, "load_failed",
+			fmt.Sprintf("Failed to load series: %s", err))
+	}
+	if cmd.Name != "" {
+		var match []history.SeriesSummary
+		for _, s := range all {
+			if s.Name == cmd.Name {
+				match = append(match, s)
+			}
+		}
+		if len(match) == 0 {
+			return newCLIError(ExitInvalidInput, "not_found",
+				fmt.Sprintf("Series %q not found.", cmd.Name))
+		}
+		all = match
+	}
+
+	if globals.JSON {
+		items := make([]jsonSeries, len(all))
+		for i, s := range all {
+			items[i] = jsonSeries{
+				Name: s.Name, Parts: s.Parts, Published: s.Published, Pending: s.Pending,
+				Gap: s.Gap, Strict: s.Strict, ThreadTS: s.ThreadTS,
+			}
+			if cmd.Name != "" {
+				for _, e := range s.Entries {
+					items[i].Entries = append(items[i].Entries, jsonSeriesPart{
+						Part: e.Series.Part, ID: e.ID, Status: e.Status,
+						Message: e.Message, PublishedAt: e.PublishedAt,
+					})
+				}
+			}
+		}
+		b, _ := json.Marshal(map[string]any{"series": items, "count": len(items)})
+		fmt.Fprintln(os.Stdout, string(b))
+		return nil
+	}
+
+	if len(all) == 0 {
+		fmt.Fprintln(os.Stdout, "No series. Queue parts with `slack-social-ai post --series <name>`.")
+		return nil
+	}
+	for _, s := range all {
+		fmt.Fprintf(os.Stdout, "%s  %d/%d published  %s\n", s.Name, s.Published, s.Parts, describeSeries(s))
+		if cmd.Name == "" {
+			continue
+		}
+		for _, e := range s.Entries {
+			fmt.Fprintf(os.Stdout, "  %2d. %-8s %-10s %s\n", e.Series.Part, e.ID, e.Status,
+				messagePreview(e.Message, 1, 0, 60)[0])
+		}
+	}
+	return nil
+}
+
+// describeSeries renders a series' settings for listings.
+func describeSeries(s history.SeriesSummary) string {
+	var parts []string
+	if s.Gap != "" {
+		parts = append(parts, "gap "+s.Gap)
+	} else {
+		parts = append(parts, "no gap")
+	}
+	if s.Strict {
+		parts = append(parts, "strict")
+	}
+	if s.ThreadTS != "" {
+		parts = append(parts, "thread "+s.ThreadTS)
+	}
+	return strings.Join(parts, ", ")
+}
+
+// SeriesSetCmd changes the settings of a series.
+type SeriesSetCmd struct {
+	Name          string `arg:"" help:"Series name."`
+	SeriesOptions `embed:""`
+	Loose         bool `help:"Allow other posts between parts again." xor:"strict"`
+	NoThread      bool `help:"Post the remaining parts as regular messages." xor:"thread"`
+}
+
+func (cmd *SeriesSetCmd) Run(globals *Globals) error {
+	if !cmd.isSet() && !cmd.Loose && !cmd.NoThread {
+		return newCLIError(ExitInvalidInput, "missing_setting",
+			"Specify what to change: --gap, --strict, --loose, --thread, or --no-thread.")
+	}
+	update, err := cmd.update(cmd.Loose, cmd.NoThread)
+	if err != nil {
+		return err
+	}
+	n, err := history.UpdateSeries(cmd.Name, update)
+	if err != nil {
+		return seriesError(err)
+	}
+
+	msg := fmt.Sprintf("Updated %d unpublished part%s of series %s.", n, plural(n, "", "s"), cmd.Name)
+	if globals.JSON {
+		printSuccessJSON(msg)
+	} else {
+		printSuccessHuman(msg)
+	}
+	return nil
+}
+
+// SeriesRemoveCmd removes the unpublished parts of a series.
+type SeriesRemoveCmd struct {
+	Name string `arg:"" help:"Series name."`
+}
+
+func (cmd *SeriesRemoveCmd) Run(globals *Globals) error {
+	n, err := history.RemoveSeries(cmd.Name)
+	if err != nil {
+		return seriesError(err)
+	}
+
+	msg := fmt.Sprintf("Removed %d unpublished part%s of series %s.", n, plural(n, "", "s"), cmd.Name)
+	if n > 0 {
+		msg += " Run `slack-social-ai undo` to restore."
+	}
+	if globals.JSON {
+		printSuccessJSON(msg)
+	} else {
+		printSuccessHuman(msg)
+	}
+	return nil
+}
+
+// seriesError maps errors from series operations to CLIErrors.
+func seriesError(err error) error {
+	switch {
+	case errors.Is(err, history.ErrNoSeries):
+		return newCLIError(ExitInvalidInput, "not_found", err.Error())
+	case errors.Is(err, history.ErrPublishing):
+		return newCLIError(ExitInvalidInput, "entry_publishing",
+			fmt.Sprintf("%s; try again once publishing finishes.", err))
+	}
+	return historyFailure(err, "series_failed",
+		fmt.Sprintf("Failed to update series: %s", err))
+}
@@ -0,0 +1,152 @@
+package main
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/lvrach/slack-social-ai/internal/config"
+	"github.com/lvrach/slack-social-ai/internal/history"
+)
+
+func TestSeriesOptions_Update(t *testing.T) {
+	update, err := SeriesOptions{
+		Gap:    "90m",
+		Strict: true,
+		Thread: "https://team.slack.com/archives/C0123ABC/p1712345678123456",
+	}.update(false, false)
+	require.NoError(t, err)
+	s := history.SeriesPart{Name: "dive", Part: 2}
+	update(&s)
+	assert.Equal(t, history.SeriesPart{Name: "dive", Part: 2, Gap: "1h30m0s", Strict: true, ThreadTS: "1712345678.123456"}, s)
+
+	update, err = SeriesOptions{}.update(true, true)
+	require.NoError(t, err)
+	update(&s)
+	assert.False(t, s.Strict)
+	assert.Empty(t, s.ThreadTS)
+	assert.Equal(t, "1h30m0s", s.Gap, "unset options are left alone")
+
+	var cliErr *CLIError
+	_, err = SeriesOptions{Gap: "soon"}.update(false, false)
+	require.True(t, asCLIError(err, &cliErr))
+	assert.Equal(t, "invalid_gap", cliErr.Code)
+	_, err = SeriesOptions{Thread: "not-a-ts"}.update(false, false)
+	require.True(t, asCLIError(err, &cliErr))
+	assert.Equal(t, "invalid_thread", cliErr.Code)
+}
+
+func TestSeriesList_JSON(t *testing.T) {
+	withTempHome(t)
+	for _, msg := range []string{"part one", "part two"} {
+		_, err := history.AppendSeriesPart(history.Entry{Message: msg, Status: "queued"}, "dive", nil)
+		require.NoError(t, err)
+	}
+
+	output := captureStdout(t, func() {
+		require.NoError(t, (&SeriesListCmd{Name: "dive"}).Run(&Globals{JSON: true}))
+	})
+
+	var resp struct {
+		Series []jsonSeries `json:"series"`
+	}
+	require.NoError(t, json.Unmarshal([]byte(output), &resp))
+	require.Len(t, resp.Series, 1)
+	s := resp.Series[0]
+	assert.Equal(t, "dive", s.Name)
+	assert.Equal(t, 2, s.Parts)
+	assert.Equal(t, 2, s.Pending)
+	require.Len(t, s.Entries, 2)
+	assert.Equal(t, 2, s.Entries[1].Part)
+	assert.Equal(t, "part two", s.Entries[1].Message)
+
+	var cliErr *CLIError
+	err := (&SeriesListCmd{Name: "missing"}).Run(&Globals{})
+	require.True(t, asCLIError(err, &cliErr))
+	assert.Equal(t, "not_found", cliErr.Code)
+}
+
+func TestSeriesSetAndRemove(t *testing.T) {
+	withTempHome(t)
+	part, err := history.AppendSeriesPart(history.Entry{Message: "part one", Status: "queued"}, "dive", nil)
+	require.NoError(t, err)
+
+	var cliErr *CLIError
+	err = (&SeriesSetCmd{Name: "dive"}).Run(&Globals{})
+	require.True(t, asCLIError(err, &cliErr))
+	assert.Equal(t, "missing_setting", cliErr.Code)
+
+	captureStdout(t, func() {
+		require.NoError(t, (&SeriesSetCmd{Name: "dive", SeriesOptions: SeriesOptions{Strict: true, Gap: "5m"}}).Run(&Globals{}))
+	})
+	got, err := history.Get(part.ID)
+	require.NoError(t, err)
+	assert.True(t, got.Series.Strict)
+	assert.Equal(t, "5m0s", got.Series.Gap)
+
+	output := captureStdout(t, func() {
+		require.NoError(t, (&SeriesRemoveCmd{Name: "dive"}).Run(&Globals{}))
+	})
+	assert.Contains(t, output, "Removed 1 unpublished part of series dive")
+	queued, err := history.Queued()
+	require.NoError(t, err)
+	assert.Empty(t, queued)
+
+	err = (&SeriesRemoveCmd{Name: "dive"}).Run(&Globals{})
+	require.True(t, asCLIError(err, &cliErr))
+	assert.Equal(t, "not_found", cliErr.Code)
+}
+
+func TestPublish_SeriesThreadAndGap(t *testing.T) {
+	withTempHome(t)
+	recent := time.Now().UTC().Add(-10 * time.Minute).Format(time.RFC3339)
+	writeHistoryEntries(t, []history.Entry{
+		{ID: "p1", Message: "part 1", Status: "published", PublishedAt: recent,
+			Series: &history.SeriesPart{Name: "dive", Part: 1, Gap: "5m", ThreadTS: "1712345678.123456"}},
+		{ID: "p2", Message: "part 2", Status: "queued",
+			Series: &history.SeriesPart{Name: "dive", Part: 2, Gap: "5m", ThreadTS: "1712345678.123456"}},
+	})
+
+	var receivedBody string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		receivedBody = string(body)
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer srv.Close()
+
+	// post_every has not elapsed since part 1, but the series gap has.
+	cfg := config.Config{Schedule: alwaysActiveSchedule()}
+	cfg.Schedule.PostEveryMinutes = 180
+	captureStdout(t, func() {
+		require.NoError(t, (&PublishCmd{}).publishOne(srv.URL, cfg, &Globals{JSON: true}, false))
+	})
+	assert.JSONEq(t, `{"text": "part 2", "thread_ts": "1712345678.123456"}`, receivedBody)
+}
+
+func TestPublish_StrictSeriesTooSoon(t *testing.T) {
+	withTempHome(t)
+	recent := time.Now().UTC().Add(-time.Minute).Format(time.RFC3339)
+	writeHistoryEntries(t, []history.Entry{
+		{ID: "p1", Message: "part 1", Status: "published", PublishedAt: recent,
+			Series: &history.SeriesPart{Name: "dive", Part: 1, Gap: "30m", Strict: true}},
+		{ID: "p2", Message: "part 2", Status: "queued",
+			Series: &history.SeriesPart{Name: "dive", Part: 2, Gap: "30m", Strict: true}},
+		{ID: "x", Message: "unrelated", Status: "queued"},
+	})
+
+	cfg := config.Config{Schedule: alwaysActiveSchedule()}
+	output := captureStdout(t, func() {
+		require.NoError(t, (&PublishCmd{}).publishOne("http://127.0.0.1:0", cfg, &Globals{JSON: true}, false))
+	})
+
+	var resp map[string]string
+	require.NoError(t, json.Unmarshal([]byte(output), &resp))
+	assert.Equal(t, "too_soon", resp["status"])
+}
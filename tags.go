@@ -0,0 +1,70 @@
+package main
+
+import (
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+)
+
+var (
+	// topicHeader matches the guide's "r/<topic>" first line.
+	topicHeader = regexp.MustCompile(`^\s*r/([A-Za-z0-9][A-Za-z0-9_.+-]*)\s*$`)
+	// validTag restricts tags to lowercase slugs so filters stay predictable.
+	validTag = regexp.MustCompile(`^[a-z0-9][a-z0-9_.+-]*$`)
+)
+
+// parseTopic extracts the topic from a message's "r/<topic>" header line.
+// Returns "" if the first line is not a topic header.
+func parseTopic(message string) string {
+	m := topicHeader.FindStringSubmatch(firstLine(message))
+	if m == nil {
+		return ""
+	}
+	return strings.ToLower(m[1])
+}
+
+// normalizeTag lowercases a tag and strips an optional "r/" or "#" prefix.
+func normalizeTag(tag string) string {
+	tag = strings.ToLower(strings.TrimSpace(tag))
+	tag = strings.TrimPrefix(tag, "r/")
+	return strings.TrimPrefix(tag, "#")
+}
+
+// resolveTags combines the message's topic header with explicit --tag values.
+// The topic comes first; duplicates are dropped.
+func resolveTags(message string, explicit []string) ([]string, error) {
+	var tags []string
+	if topic := parseTopic(message); topic != "" {
+		tags = append(tags, topic)
+	}
+	for _, raw := range explicit {
+		tag := normalizeTag(raw)
+		if !validTag.MatchString(tag) {
+			return nil, newCLIError(ExitInvalidInput, "invalid_tag",
+				fmt.Sprintf("Invalid tag %q. Use letters, digits, and . _ + - only.", raw))
+		}
+		if !slices.Contains(tags, tag) {
+			tags = append(tags, tag)
+		}
+	}
+	return tags, nil
+}
+
+// normalizeTags normalizes tag filter values from the command line.
+func normalizeTags(tags []string) []string {
+	result := make([]string, 0, len(tags))
+	for _, t := range tags {
+		result = append(result, normalizeTag(t))
+	}
+	return result
+}
+
+// formatTags renders tags for human output, e.g. "r/go r/ai".
+func formatTags(tags []string) string {
+	parts := make([]string, len(tags))
+	for i, t := range tags {
+		parts[i] = "r/" + t
+	}
+	return strings.Join(parts, " ")
+}
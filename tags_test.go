@@ -0,0 +1,58 @@
+package main
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseTopic(t *testing.T) {
+	tests := []struct {
+		name    string
+		message string
+		want    string
+	}{
+		{name: "topic header", message: "r/go\n:bulb: *Headline*", want: "go"},
+		{name: "uppercase normalized", message: "r/DevOps\nbody", want: "devops"},
+		{name: "surrounding spaces", message: "  r/ai  \nbody", want: "ai"},
+		{name: "single line", message: "r/python", want: "python"},
+		{name: "no header", message: ":bulb: *Headline*\nr/go", want: ""},
+		{name: "header with trailing text", message: "r/go is great", want: ""},
+		{name: "empty", message: "", want: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, parseTopic(tt.message))
+		})
+	}
+}
+
+func TestResolveTags_TopicAndExplicit(t *testing.T) {
+	tags, err := resolveTags("r/go\nbody", []string{"Performance", "r/go", "#til"})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"go", "performance", "til"}, tags)
+}
+
+func TestResolveTags_NoTags(t *testing.T) {
+	tags, err := resolveTags("plain message", nil)
+	require.NoError(t, err)
+	assert.Empty(t, tags)
+}
+
+func TestResolveTags_Invalid(t *testing.T) {
+	_, err := resolveTags("body", []string{"not valid"})
+	require.Error(t, err)
+
+	var cliErr *CLIError
+	require.True(t, errors.As(err, &cliErr))
+	assert.Equal(t, "invalid_tag", cliErr.Code)
+	assert.Equal(t, ExitInvalidInput, cliErr.ExitCode)
+}
+
+func TestFormatTags(t *testing.T) {
+	assert.Equal(t, "r/go r/ai", formatTags([]string{"go", "ai"}))
+	assert.Empty(t, formatTags(nil))
+}
@@ -3,13 +3,28 @@ package main
 import (
 	"fmt"
 	"regexp"
+	"strconv"
 	"time"
+
+	"github.com/lvrach/slack-social-ai/internal/config"
 )
 
 // parseAt parses a time specification into an absolute time.
-// Supports: RFC3339, HH:MM (24-hour, local time), Go durations (2h, 30m).
+// Supports: RFC3339, HH:MM (24-hour, in the schedule's time zone), Go
+// durations (2h, 30m).
 func parseAt(input string) (time.Time, error) {
-	return parseAtFrom(input, time.Now())
+	return parseAtFrom(input, time.Now().In(scheduleLocation()))
+}
+
+// scheduleLocation loads the configured schedule's time zone, in which
+// HH:MM inputs are read and times are shown. Commands that don't otherwise
+// load the config call it once and pass the zone on.
+func scheduleLocation() *time.Location {
+	cfg, err := config.Load()
+	if err != nil {
+		return time.Local
+	}
+	return cfg.Schedule.Location()
 }
 
 // parseAtFrom is the testable version that accepts a reference time.
@@ -19,21 +34,9 @@ func parseAtFrom(input string, now time.Time) (time.Time, error) {
 		return t, nil
 	}
 
-	// 2. HH:MM (24-hour format, local time).
-	if matched, _ := regexp.MatchString(`^\d{1,2}:\d{2}$`, input); matched {
-		t, err := time.Parse("15:04", input)
-		if err != nil {
-			return time.Time{}, newCLIError(ExitInvalidInput, "invalid_time",
-				fmt.Sprintf("Invalid time %q: %s", input, err))
-		}
-		result := time.Date(now.Year(), now.Month(), now.Day(),
-			t.Hour(), t.Minute(), 0, 0, now.Location())
-		if result.Before(now) {
-			// Use AddDate to preserve wall-clock time across DST transitions.
-			result = time.Date(now.Year(), now.Month(), now.Day()+1,
-				t.Hour(), t.Minute(), 0, 0, now.Location())
-		}
-		return result, nil
+	// 2. HH:MM (24-hour format, in now's zone), today or else tomorrow.
+	if isClock(input) {
+		return clockFrom(input, now, 1)
 	}
 
 	// 3. Go duration ("2h", "30m").
@@ -49,3 +52,70 @@ func parseAtFrom(input string, now time.Time) (time.Time, error) {
 	return time.Time{}, newCLIError(ExitInvalidInput, "invalid_time",
 		fmt.Sprintf("Cannot parse %q. Use HH:MM, a duration (2h, 30m), or RFC3339.", input))
 }
+
+// clockPattern matches HH:MM inputs.
+var clockPattern = regexp.MustCompile(`^\d{1,2}:\d{2}$`)
+
+func isClock(input string) bool { return clockPattern.MatchString(input) }
+
+// clockFrom returns the HH:MM input on now's day, in now's zone, moved by
+// step days (1 or -1) if that is on the wrong side of now.
+func clockFrom(input string, now time.Time, step int) (time.Time, error) {
+	t, err := time.Parse("15:04", input)
+	if err != nil {
+		return time.Time{}, newCLIError(ExitInvalidInput, "invalid_time",
+			fmt.Sprintf("Invalid time %q: %s", input, err))
+	}
+	result := time.Date(now.Year(), now.Month(), now.Day(),
+		t.Hour(), t.Minute(), 0, 0, now.Location())
+	if (step > 0 && result.Before(now)) || (step < 0 && result.After(now)) {
+		// time.Date keeps the wall-clock time across DST transitions.
+		result = time.Date(now.Year(), now.Month(), now.Day()+step,
+			t.Hour(), t.Minute(), 0, 0, now.Location())
+	}
+	return result, nil
+}
+
+// ttlDays matches day and week TTLs such as "3d" and "1w".
+var ttlDays = regexp.MustCompile(`^(\d+)([dw])$`)
+
+// parseTTL parses a time-to-live such as "3d", "1w", or a Go duration ("12h").
+func parseTTL(input string) (time.Duration, error) {
+	if m := ttlDays.FindStringSubmatch(input); m != nil {
+		n, _ := strconv.Atoi(m[1])
+		day := 24 * time.Hour
+		if m[2] == "w" {
+			day *= 7
+		}
+		if n > 0 {
+			return time.Duration(n) * day, nil
+		}
+	} else if dur, err := time.ParseDuration(input); err == nil && dur > 0 {
+		return dur, nil
+	}
+	return 0, newCLIError(ExitInvalidInput, "invalid_ttl",
+		fmt.Sprintf("Cannot parse TTL %q. Use a positive duration like 3d, 1w, or 12h.", input))
+}
+
+// parseSince parses a point in the past for filters such as --since and
+// --until. It reads the formats parseAt does, looking back instead of ahead:
+// RFC3339; HH:MM is today, or yesterday if that is still ahead; and
+// durations count back from now, so "2h" is two hours ago. It also accepts
+// a date (2006-01-02, midnight in now's zone) and TTL-style days and weeks
+// (3d, 1w).
+func parseSince(input string, now time.Time) (time.Time, error) {
+	if t, err := time.Parse(time.RFC3339, input); err == nil {
+		return t, nil
+	}
+	if t, err := time.ParseInLocation("2006-01-02", input, now.Location()); err == nil {
+		return t, nil
+	}
+	if isClock(input) {
+		return clockFrom(input, now, -1)
+	}
+	if d, err := parseTTL(input); err == nil {
+		return now.Add(-d), nil
+	}
+	return time.Time{}, newCLIError(ExitInvalidInput, "invalid_time",
+		fmt.Sprintf("Cannot parse %q. Use a duration (2h, 3d), HH:MM, a date (2006-01-02), or RFC3339.", input))
+}
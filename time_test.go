@@ -7,6 +7,8 @@ import (
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+
+	"github.com/lvrach/slack-social-ai/internal/config"
 )
 
 func TestParseAt_RFC3339(t *testing.T) {
@@ -38,6 +40,34 @@ func TestParseAt_HH_MM_Tomorrow(t *testing.T) {
 	assert.Equal(t, expected, got)
 }
 
+func TestParseAt_HH_MM_AcrossDST(t *testing.T) {
+	ny, err := time.LoadLocation("America/New_York")
+	require.NoError(t, err)
+
+	// 22:00 EST the night before spring forward: 09:00 is EDT, 10 hours on.
+	got, err := parseAtFrom("09:00", time.Date(2026, 3, 7, 22, 0, 0, 0, ny))
+	require.NoError(t, err)
+	assert.Equal(t, time.Date(2026, 3, 8, 13, 0, 0, 0, time.UTC), got.UTC())
+
+	// 22:00 EDT the night before fall back: 09:00 is EST, 12 hours on.
+	got, err = parseAtFrom("09:00", time.Date(2026, 10, 31, 22, 0, 0, 0, ny))
+	require.NoError(t, err)
+	assert.Equal(t, time.Date(2026, 11, 1, 14, 0, 0, 0, time.UTC), got.UTC())
+}
+
+func TestParseAt_ScheduleTimezone(t *testing.T) {
+	withTempHome(t)
+	cfg := config.Default()
+	cfg.Schedule.Timezone = "Asia/Tokyo"
+	require.NoError(t, config.Save(cfg))
+
+	got, err := parseAt("09:00")
+	require.NoError(t, err)
+	assert.Equal(t, "Asia/Tokyo", got.Location().String())
+	assert.Equal(t, 9, got.Hour())
+	assert.Equal(t, 0, got.Minute())
+}
+
 func TestParseAt_SingleDigitHour(t *testing.T) {
 	now := time.Date(2025, 2, 14, 6, 0, 0, 0, time.Local)
 	got, err := parseAtFrom("9:00", now)
@@ -111,3 +141,54 @@ func TestParseAt_Invalid(t *testing.T) {
 		})
 	}
 }
+
+func TestParseTTL(t *testing.T) {
+	tests := []struct {
+		input string
+		want  time.Duration
+	}{
+		{"3d", 72 * time.Hour},
+		{"1w", 7 * 24 * time.Hour},
+		{"12h", 12 * time.Hour},
+		{"90m", 90 * time.Minute},
+	}
+	for _, tt := range tests {
+		got, err := parseTTL(tt.input)
+		require.NoError(t, err, tt.input)
+		assert.Equal(t, tt.want, got, tt.input)
+	}
+}
+
+func TestParseTTL_Invalid(t *testing.T) {
+	for _, input := range []string{"", "0d", "-2h", "3 days", "d"} {
+		_, err := parseTTL(input)
+		var cliErr *CLIError
+		require.True(t, errors.As(err, &cliErr), input)
+		assert.Equal(t, "invalid_ttl", cliErr.Code, input)
+	}
+}
+
+func TestParseSince_LooksBack(t *testing.T) {
+	now := time.Date(2026, 3, 2, 12, 0, 0, 0, time.UTC)
+
+	got, err := parseSince("2h", now)
+	require.NoError(t, err)
+	assert.Equal(t, now.Add(-2*time.Hour), got, "durations count back from now")
+
+	got, err = parseSince("2026-02-27", now)
+	require.NoError(t, err)
+	assert.Equal(t, time.Date(2026, 2, 27, 0, 0, 0, 0, time.UTC), got)
+
+	got, err = parseSince("09:30", now)
+	require.NoError(t, err)
+	assert.Equal(t, time.Date(2026, 3, 2, 9, 30, 0, 0, time.UTC), got, "earlier today")
+
+	got, err = parseSince("18:00", now)
+	require.NoError(t, err)
+	assert.Equal(t, time.Date(2026, 3, 1, 18, 0, 0, 0, time.UTC), got, "still ahead today, so yesterday")
+
+	_, err = parseSince("25:00", now)
+	var cliErr *CLIError
+	require.True(t, asCLIError(err, &cliErr))
+	assert.Equal(t, "invalid_time", cliErr.Code)
+}
@@ -0,0 +1,112 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"slices"
+
+	"github.com/lvrach/slack-social-ai/internal/config"
+	"github.com/lvrach/slack-social-ai/internal/history"
+)
+
+// UndoCmd restores entries removed by history --remove/--clear/--clear-all
+// or queue remove.
+type UndoCmd struct {
+	ID   string `arg:"" optional:"" help:"Snapshot ID to restore (default: the most recent)."`
+	List bool   `help:"List available undo snapshots instead of restoring." short:"l"`
+}
+
+func (cmd *UndoCmd) Run(globals *Globals) error {
+	cfg, _ := config.Load()
+	if cmd.List {
+		return cmd.list(globals, cfg)
+	}
+
+	snap, skipped, err := history.Undo(cmd.ID, cfg.UndoRetention())
+	if err != nil {
+		if errors.Is(err, history.ErrNoSnapshot) {
+			msg := "Nothing to undo."
+			if cmd.ID != "" {
+				msg = fmt.Sprintf("Snapshot %q not found or expired. Run `slack-social-ai undo --list`.", cmd.ID)
+			}
+			return newCLIError(ExitInvalidInput, "nothing_to_undo", msg)
+		}
+		return historyFailure(err, "undo_failed",
+			fmt.Sprintf("Failed to undo: %s", err))
+	}
+
+	restored := len(snap.Entries) - len(skipped)
+	if globals.JSON {
+		ids := make([]string, 0, restored)
+		for _, se := range snap.Entries {
+			if !slices.Contains(skipped, se.Entry.ID) {
+				ids = append(ids, se.Entry.ID)
+			}
+		}
+		resp := map[string]any{
+			"status":    "ok",
+			"snapshot":  snap.ID,
+			"operation": snap.Operation,
+			"restored":  restored,
+			"entry_ids": ids,
+		}
+		if len(skipped) > 0 {
+			resp["skipped"] = skipped
+		}
+		b, _ := json.Marshal(resp)
+		fmt.Fprintln(os.Stdout, string(b))
+		return nil
+	}
+
+	fmt.Fprintf(os.Stdout, "Restored %d entr%s removed by %s at %s.\n",
+		restored, plural(restored, "y", "ies"), snap.Operation, formatShortTime(snap.CreatedAt))
+	if len(skipped) > 0 {
+		fmt.Fprintf(os.Stdout, "Skipped %d already present: %v\n", len(skipped), skipped)
+	}
+	return nil
+}
+
+func (cmd *UndoCmd) list(globals *Globals, cfg config.Config) error {
+	snaps, err := history.Snapshots(cfg.UndoRetention())
+	if err != nil {
+		return historyFailure(err, "undo_failed",
+			fmt.Sprintf("Failed to load undo snapshots: %s", err))
+	}
+
+	if globals.JSON {
+		if snaps == nil {
+			snaps = []history.Snapshot{}
+		}
+		resp := map[string]any{"snapshots": snaps, "count": len(snaps)}
+		b, _ := json.Marshal(resp)
+		fmt.Fprintln(os.Stdout, string(b))
+		return nil
+	}
+
+	if len(snaps) == 0 {
+		fmt.Fprintln(os.Stdout, "No undo snapshots.")
+		return nil
+	}
+	fmt.Fprintf(os.Stdout, "Undo snapshots (newest first, kept %d days):\n\n",
+		int(cfg.UndoRetention().Hours()/24))
+	for _, s := range snaps {
+		fmt.Fprintf(os.Stdout, " %s  [%s]  %-9s %d entr%s\n", s.ID, formatShortTime(s.CreatedAt),
+			s.Operation, len(s.Entries), plural(len(s.Entries), "y", "ies"))
+		for _, se := range s.Entries {
+			fmt.Fprintf(os.Stdout, "     %s %-9s %s\n", se.Entry.ID, se.Entry.Status,
+				truncate(firstLine(se.Entry.Message), 60))
+		}
+	}
+	fmt.Fprintln(os.Stdout, "\nRestore with `slack-social-ai undo [<snapshot-id>]`.")
+	return nil
+}
+
+// plural picks the singular or plural suffix for n.
+func plural(n int, one, many string) string {
+	if n == 1 {
+		return one
+	}
+	return many
+}
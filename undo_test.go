@@ -0,0 +1,61 @@
+package main
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/lvrach/slack-social-ai/internal/history"
+)
+
+func TestUndoCmd_AfterQueueRemove(t *testing.T) {
+	withTempHome(t)
+
+	e, err := history.Append("keep me", "queued", time.Time{})
+	require.NoError(t, err)
+	captureStdout(t, func() {
+		assert.NoError(t, (&QueueRemoveCmd{ID: e.ID}).Run(&Globals{}))
+	})
+
+	output := captureStdout(t, func() {
+		assert.NoError(t, (&UndoCmd{}).Run(&Globals{JSON: true}))
+	})
+	var resp map[string]any
+	require.NoError(t, json.Unmarshal([]byte(output), &resp))
+	assert.Equal(t, "remove", resp["operation"])
+	assert.Equal(t, float64(1), resp["restored"])
+	assert.Equal(t, []any{e.ID}, resp["entry_ids"])
+
+	queued, err := history.Queued()
+	require.NoError(t, err)
+	require.Len(t, queued, 1)
+	assert.Equal(t, e.ID, queued[0].ID)
+}
+
+func TestUndoCmd_List(t *testing.T) {
+	withTempHome(t)
+
+	_, err := history.Append("one", "queued", time.Time{})
+	require.NoError(t, err)
+	captureStdout(t, func() {
+		assert.NoError(t, (&HistoryListCmd{ClearAll: true}).Run(&Globals{}))
+	})
+
+	output := captureStdout(t, func() {
+		assert.NoError(t, (&UndoCmd{List: true}).Run(&Globals{}))
+	})
+	assert.Contains(t, output, "clear-all")
+	assert.Contains(t, output, "one")
+}
+
+func TestUndoCmd_NothingToUndo(t *testing.T) {
+	withTempHome(t)
+
+	err := (&UndoCmd{}).Run(&Globals{})
+	var cliErr *CLIError
+	require.True(t, asCLIError(err, &cliErr))
+	assert.Equal(t, "nothing_to_undo", cliErr.Code)
+}